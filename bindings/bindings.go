@@ -0,0 +1,270 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bindings
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+	"github.com/jetsetilly/gopher2600/paths"
+)
+
+// the name of the bindings file in the resource path
+const bindingsFile = "bindings"
+
+// the sub-directory of the resource path containing the per-cartridge
+// bindings files
+const cartBindingsPath = "cartbindings"
+
+const commentLeader = '#'
+const fieldSeparator = ","
+
+// Action is an event and the data that is sent with it
+type Action struct {
+	Event input.Event
+	Data  input.EventData
+}
+
+// Binding maps a control on the host computer to the events sent to one of
+// the VCS's ports. The Release action will have an Event of input.NoEvent if
+// nothing is to happen when the control is released.
+type Binding struct {
+	ID      input.ID
+	Press   Action
+	Release Action
+}
+
+// Profile is a collection of Bindings, indexed by the name of the control
+type Profile struct {
+	bindings map[string][]Binding
+}
+
+// NewProfile is the preferred method of initialisation for the Profile type.
+// The text argument is parsed as though it were the contents of a bindings
+// file (see package documentation for the format).
+func NewProfile(text string) (*Profile, error) {
+	prf := &Profile{
+		bindings: make(map[string][]Binding),
+	}
+
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		l := strings.TrimSpace(lines[i])
+
+		// ignore empty lines and comment lines
+		if len(l) == 0 || l[0] == commentLeader {
+			continue // for loop
+		}
+
+		control, b, err := parseBinding(l)
+		if err != nil {
+			return nil, errors.New(errors.BindingsError, fmt.Sprintf("%v [line %d]", err, i+1))
+		}
+
+		prf.bindings[control] = append(prf.bindings[control], b)
+	}
+
+	return prf, nil
+}
+
+// Lookup returns the bindings for the named control. Returns nil if there are
+// no bindings for the control.
+func (prf *Profile) Lookup(control string) []Binding {
+	return prf.bindings[control]
+}
+
+// replace the bindings for every control in the other profile
+func (prf *Profile) replace(other *Profile) {
+	for control, b := range other.bindings {
+		prf.bindings[control] = b
+	}
+}
+
+// parse a single line from a bindings file
+func parseBinding(line string) (string, Binding, error) {
+	b := Binding{}
+
+	fields := strings.Split(line, fieldSeparator)
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	if len(fields) < 3 {
+		return "", b, fmt.Errorf("too few fields in binding")
+	}
+	if len(fields) > 4 {
+		return "", b, fmt.Errorf("too many fields in binding")
+	}
+
+	control := fields[0]
+	if control == "" {
+		return "", b, fmt.Errorf("no control specified")
+	}
+
+	switch strings.ToLower(fields[1]) {
+	case "0":
+		b.ID = input.HandControllerZeroID
+	case "1":
+		b.ID = input.HandControllerOneID
+	case "panel":
+		b.ID = input.PanelID
+	default:
+		return "", b, fmt.Errorf("unrecognised port (%s)", fields[1])
+	}
+
+	var err error
+
+	b.Press, err = parseAction(fields[2])
+	if err != nil {
+		return "", b, err
+	}
+
+	b.Release.Event = input.NoEvent
+	if len(fields) == 4 {
+		b.Release, err = parseAction(fields[3])
+		if err != nil {
+			return "", b, err
+		}
+	}
+
+	return control, b, nil
+}
+
+// parse the event and data of a single action
+func parseAction(s string) (Action, error) {
+	ev, data, err := input.ParseEvent(s)
+	return Action{Event: ev, Data: data}, err
+}
+
+// the profiles currently in use. the cartridge profile takes precedence over
+// the default profile
+var active struct {
+	crit      sync.RWMutex
+	profile   *Profile
+	cartridge *Profile
+}
+
+func init() {
+	var err error
+	active.profile, err = NewProfile(defaultProfile)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// read the named file and parse it as a profile. returns nil and no error if
+// the file does not exist
+func readProfile(filename string) (*Profile, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.New(errors.BindingsError, err)
+	}
+	return NewProfile(string(data))
+}
+
+// Load the default profile. This is the built-in profile with the bindings in
+// the bindings file in the resource path taking precedence. If the file does
+// not exist then the built-in profile is used unchanged.
+func Load() error {
+	pth, err := paths.ResourcePath("", bindingsFile)
+	if err != nil {
+		return errors.New(errors.BindingsError, err)
+	}
+
+	usr, err := readProfile(pth)
+	if err != nil {
+		return err
+	}
+
+	prf, err := NewProfile(defaultProfile)
+	if err != nil {
+		return err
+	}
+
+	if usr != nil {
+		prf.replace(usr)
+	}
+
+	active.crit.Lock()
+	defer active.crit.Unlock()
+	active.profile = prf
+
+	return nil
+}
+
+// LoadCartridge loads the named file from the cartbindings sub-directory of the
+// resource path. The bindings in the file take precedence over the default
+// bindings until ClearCartridge() is called or until another file is loaded.
+func LoadCartridge(filename string) error {
+	pth, err := paths.ResourcePath(cartBindingsPath, filename)
+	if err != nil {
+		return errors.New(errors.BindingsError, err)
+	}
+
+	prf, err := readProfile(pth)
+	if err != nil {
+		return err
+	}
+
+	if prf == nil {
+		return errors.New(errors.BindingsError, fmt.Sprintf("bindings file not found (%s)", pth))
+	}
+
+	SetCartridge(prf)
+
+	return nil
+}
+
+// SetCartridge sets the profile that takes precedence over the default
+// profile. A value of nil is the same as calling ClearCartridge().
+func SetCartridge(prf *Profile) {
+	active.crit.Lock()
+	defer active.crit.Unlock()
+	active.cartridge = prf
+}
+
+// ClearCartridge removes any cartridge bindings. Only the default bindings
+// will be used.
+func ClearCartridge() {
+	SetCartridge(nil)
+}
+
+// Lookup returns the bindings for the named control. Bindings in the cartridge
+// profile take precedence over the default profile.
+func Lookup(control string) []Binding {
+	active.crit.RLock()
+	defer active.crit.RUnlock()
+
+	if active.cartridge != nil {
+		if b := active.cartridge.Lookup(control); b != nil {
+			return b
+		}
+	}
+
+	return active.profile.Lookup(control)
+}
@@ -0,0 +1,118 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bindings_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/bindings"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+func TestDefaultBindings(t *testing.T) {
+	b := bindings.Lookup("Space")
+	if len(b) != 1 {
+		t.Fatalf("expected one binding for Space, got %d", len(b))
+	}
+	if b[0].ID != input.HandControllerZeroID {
+		t.Errorf("expected Space to be bound to port 0")
+	}
+	if b[0].Press.Event != input.Fire || b[0].Press.Data != true {
+		t.Errorf("unexpected press action for Space (%v)", b[0].Press)
+	}
+	if b[0].Release.Event != input.Fire || b[0].Release.Data != false {
+		t.Errorf("unexpected release action for Space (%v)", b[0].Release)
+	}
+
+	// no release action
+	b = bindings.Lookup("F3")
+	if len(b) != 1 || b[0].ID != input.PanelID || b[0].Release.Event != input.NoEvent {
+		t.Errorf("unexpected binding for F3 (%v)", b)
+	}
+
+	// keypad data is a rune
+	b = bindings.Lookup("C")
+	if len(b) != 1 || b[0].Press.Data != '#' || b[0].Release.Event != input.KeypadUp || b[0].Release.Data != nil {
+		t.Errorf("unexpected binding for C (%v)", b)
+	}
+
+	// multiple bindings for one control
+	b = bindings.Lookup("J")
+	if len(b) != 2 {
+		t.Errorf("expected two bindings for J, got %d", len(b))
+	}
+
+	if bindings.Lookup("F12") != nil {
+		t.Errorf("unexpected binding for F12")
+	}
+}
+
+func TestCartridgeBindings(t *testing.T) {
+	prf, err := bindings.NewProfile(`
+# second player on the keyboard
+Space, 1, Fire:true, Fire:false
+MouseMiddle, 1, PaddleSet:0.5`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bindings.SetCartridge(prf)
+	defer bindings.ClearCartridge()
+
+	b := bindings.Lookup("Space")
+	if len(b) != 1 || b[0].ID != input.HandControllerOneID {
+		t.Errorf("cartridge binding for Space has not taken precedence")
+	}
+
+	b = bindings.Lookup("MouseMiddle")
+	if len(b) != 1 || b[0].Press.Data != float32(0.5) {
+		t.Errorf("unexpected binding for MouseMiddle (%v)", b)
+	}
+
+	// controls not in the cartridge profile use the default profile
+	b = bindings.Lookup("Left")
+	if len(b) != 1 || b[0].ID != input.HandControllerZeroID {
+		t.Errorf("default binding for Left has been lost")
+	}
+
+	bindings.ClearCartridge()
+	b = bindings.Lookup("Space")
+	if len(b) != 1 || b[0].ID != input.HandControllerZeroID {
+		t.Errorf("cartridge binding for Space has not been cleared")
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	bad := []string{
+		"Space, 0",
+		"Space, 0, Fire:true, Fire:false, Fire:true",
+		", 0, Fire:true",
+		"Space, 2, Fire:true",
+		"Space, 0, Jump:true",
+		"Space, 0, Fire:maybe",
+		"1, 0, KeypadDown:10",
+	}
+
+	for _, s := range bad {
+		if _, err := bindings.NewProfile(s); err == nil {
+			t.Errorf("expected error for binding (%s)", s)
+		}
+	}
+}
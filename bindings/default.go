@@ -0,0 +1,76 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bindings
+
+// the built-in profile. used when there is no bindings file in the resource
+// path
+const defaultProfile = `
+# panel
+F1, panel, PanelSelect:true, PanelSelect:false
+F2, panel, PanelReset:true, PanelReset:false
+F3, panel, PanelToggleColor
+F4, panel, PanelTogglePlayer0Pro
+F5, panel, PanelTogglePlayer1Pro
+
+# joystick (left player)
+Left, 0, Left:true, Left:false
+Right, 0, Right:true, Right:false
+Up, 0, Up:true, Up:false
+Down, 0, Down:true, Down:false
+Space, 0, Fire:true, Fire:false
+
+# extra buttons for booster grip and genesis pad (left player)
+J, 0, BoosterGripTrigger:true, BoosterGripTrigger:false
+J, 0, GenesisButtonC:true, GenesisButtonC:false
+K, 0, BoosterGripBooster:true, BoosterGripBooster:false
+
+# paddle fire button (left player). the left mouse button sends the joystick
+# fire button instead for controllers that are operated by the mouse but which
+# use the joystick fire button (driving controller, pointer devices, light gun)
+MouseLeft, 0, PaddleFire:true, PaddleFire:false
+
+# keypad (left player)
+1, 0, KeypadDown:1, KeypadUp
+2, 0, KeypadDown:2, KeypadUp
+3, 0, KeypadDown:3, KeypadUp
+Q, 0, KeypadDown:4, KeypadUp
+W, 0, KeypadDown:5, KeypadUp
+E, 0, KeypadDown:6, KeypadUp
+A, 0, KeypadDown:7, KeypadUp
+S, 0, KeypadDown:8, KeypadUp
+D, 0, KeypadDown:9, KeypadUp
+Z, 0, KeypadDown:*, KeypadUp
+X, 0, KeypadDown:0, KeypadUp
+C, 0, KeypadDown:#, KeypadUp
+
+# keypad (right player)
+4, 1, KeypadDown:1, KeypadUp
+5, 1, KeypadDown:2, KeypadUp
+6, 1, KeypadDown:3, KeypadUp
+R, 1, KeypadDown:4, KeypadUp
+T, 1, KeypadDown:5, KeypadUp
+Y, 1, KeypadDown:6, KeypadUp
+F, 1, KeypadDown:7, KeypadUp
+G, 1, KeypadDown:8, KeypadUp
+H, 1, KeypadDown:9, KeypadUp
+V, 1, KeypadDown:*, KeypadUp
+B, 1, KeypadDown:0, KeypadUp
+N, 1, KeypadDown:#, KeypadUp
+`
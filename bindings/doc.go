@@ -0,0 +1,63 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// Package bindings maps the controls of the host computer (keyboard keys and
+// mouse buttons) to events that are sent to the VCS's ports. The controls are
+// identified by the names used by the gui package (eg. gui.EventKeyboard.Key).
+// Mouse buttons are identified by the names MouseLeft, MouseRight and
+// MouseMiddle.
+//
+// The default bindings are the built-in bindings, which are the same as those
+// described in the README, combined with the bindings in the bindings file in
+// the resource path (see paths package). The Load() function reads the file.
+// Bindings for a control in the file replace all of the built-in bindings for
+// that control.
+//
+// Bindings for a specific cartridge can be loaded with LoadCartridge(). These
+// bindings take precedence over the default bindings. Per-cartridge binding
+// files are in the cartbindings sub-directory of the resource path. The setup
+// package uses this to apply bindings automatically when a cartridge is
+// attached.
+//
+// Each line in a bindings file describes a single binding:
+//
+//	<control>, <port>, <press event>[:<data>] [, <release event>[:<data>]]
+//
+// Port is one of 0, 1 or panel. Events are those defined in the input package.
+// Data is optional and is interpreted according to the event. The release
+// event is also optional and is sent when the control is released.
+//
+// For example:
+//
+//	# joystick for the right player
+//	W, 1, Up:true, Up:false
+//	Space, 1, Fire:true, Fire:false
+//
+//	# keypad
+//	1, 0, KeypadDown:1, KeypadUp
+//
+// Lines starting with # are ignored, as are empty lines.
+//
+// A control can be bound more than once, in which case all events are sent.
+// Events that are not applicable to the attached controller are ignored by the
+// controller, so for example, a single control can be bound to the Booster
+// Grip trigger and the Genesis pad's C button. Bindings for a control in a
+// cartridge bindings file replace all of the default bindings for that
+// control.
+package bindings
@@ -0,0 +1,114 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bustrace_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/bustrace"
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+	"github.com/jetsetilly/gopher2600/test"
+)
+
+var entries = []bustrace.Entry{
+	{Address: 0xf000, Data: 0x78, Area: memorymap.Cartridge, Bank: 1, Frame: 3, Scanline: 10, HorizPos: -68},
+	{Address: 0xf001, Data: 0xd8, Area: memorymap.Cartridge, Bank: 1, Phantom: true, Frame: 3, Scanline: 10, HorizPos: -65},
+	{Address: 0x0080, Data: 0x1f, Area: memorymap.RAM, Bank: -1, Write: true, Listen: true, Frame: 3, Scanline: 10, HorizPos: -62},
+	{Idle: true, Bank: -1, Frame: 70000, Scanline: 311, HorizPos: 159},
+}
+
+func TestRoundTrip(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	wr, err := bustrace.NewWriter(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := wr.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd, err := bustrace.NewReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		r, err := rd.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != e {
+			t.Errorf("entry read back incorrectly (%v - wanted %v)", r, e)
+		}
+	}
+
+	if _, err := rd.Read(); err != io.EOF {
+		t.Errorf("expected end of file (%v)", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	b := &bytes.Buffer{}
+
+	wr, _ := bustrace.NewWriter(b)
+	for _, e := range entries {
+		_ = wr.Write(e)
+	}
+	_ = wr.Flush()
+
+	out := &strings.Builder{}
+	if err := bustrace.Decode(b, out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	test.Equate(t, len(lines), len(entries))
+	test.Equate(t, lines[0], "fr 3    sl 10  hp -68 R 0xf000 0x78 Cartridge bank 1")
+	test.Equate(t, lines[1], "fr 3    sl 10  hp -65 R 0xf001 0xd8 Cartridge bank 1 phantom")
+	test.Equate(t, lines[2], "fr 3    sl 10  hp -62 W 0x0080 0x1f RAM listen")
+	test.Equate(t, lines[3], "fr 70000 sl 311 hp 159 idle")
+}
+
+func TestBadFile(t *testing.T) {
+	_, err := bustrace.NewReader(strings.NewReader("not a bus trace"))
+	test.ExpectedFailure(t, err)
+
+	// a file with a valid header but a truncated entry
+	b := &bytes.Buffer{}
+	wr, _ := bustrace.NewWriter(b)
+	_ = wr.Write(entries[0])
+	_ = wr.Flush()
+	b.Truncate(b.Len() - 1)
+
+	rd, err := bustrace.NewReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rd.Read()
+	test.ExpectedFailure(t, err)
+}
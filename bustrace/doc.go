@@ -0,0 +1,40 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// Package bustrace defines a cycle-by-cycle record of activity on the VCS
+// address bus, along with a compact binary file format for storing that
+// record.
+//
+// Each Entry records a single access of the bus: the address as it appeared on
+// the bus, the data, the direction of the access, the memory area and
+// cartridge bank that the address mapped to, and the position of the
+// television beam at the moment of the access. Entries are also flagged if
+// the access was a phantom access by the CPU or if the access was a write that
+// the cartridge could see via the Listen() mechanism. CPU cycles on which the
+// bus was not accessed are recorded as idle entries.
+//
+// Entries are written to a file with a Writer and read back with a Reader.
+// The Decode() function prints every entry in a file in a human readable
+// format.
+//
+// The bustrace package does not do the tracing itself. An implementation of
+// the memory.BusTracer interface (such as the one in the debugger package)
+// should create an Entry for every access and pass it to a Writer as
+// required.
+package bustrace
@@ -0,0 +1,93 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bustrace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+)
+
+// Entry is a single access of the address bus
+type Entry struct {
+	// the address as it appeared on the bus (ie. before mapping) and the data
+	// that was read or written
+	Address uint16
+	Data    uint8
+
+	// the area of memory the address mapped to
+	Area memorymap.Area
+
+	// the cartridge bank mapped to the address at the moment it was put on the
+	// bus. -1 if the address did not map to the cartridge
+	Bank int
+
+	// Write is false for a read of the bus
+	Write bool
+
+	// the access was a phantom access by the CPU (eg. the dummy read of an
+	// indexed address that crosses a page boundary)
+	Phantom bool
+
+	// the access was a write outside of cartridge space that the cartridge
+	// could see via its Listen() function
+	Listen bool
+
+	// the CPU did not access the bus during the cycle. Address, Data, Area
+	// and Bank have no meaning for idle entries
+	Idle bool
+
+	// the state of the television at the moment of the access
+	Frame    int
+	Scanline int
+	HorizPos int
+}
+
+func (e Entry) String() string {
+	s := strings.Builder{}
+
+	s.WriteString(fmt.Sprintf("fr %-4d sl %-3d hp %-3d ", e.Frame, e.Scanline, e.HorizPos))
+
+	if e.Idle {
+		s.WriteString("idle")
+		return s.String()
+	}
+
+	if e.Write {
+		s.WriteString("W ")
+	} else {
+		s.WriteString("R ")
+	}
+
+	s.WriteString(fmt.Sprintf("%#04x %#02x %s", e.Address, e.Data, e.Area))
+
+	if e.Bank >= 0 {
+		s.WriteString(fmt.Sprintf(" bank %d", e.Bank))
+	}
+	if e.Phantom {
+		s.WriteString(" phantom")
+	}
+	if e.Listen {
+		s.WriteString(" listen")
+	}
+
+	return s.String()
+}
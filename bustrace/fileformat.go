@@ -0,0 +1,216 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package bustrace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+)
+
+// bus trace file format
+// ---------------------
+//
+// <magic string>
+// <version byte>
+// <entry>...
+//
+// every entry is entrySize bytes long. multi-byte fields are little-endian:
+//
+//	offset	size	field
+//	0	2	address
+//	2	1	data
+//	3	1	flags (see below)
+//	4	2	bank (noBank if there is no bank)
+//	6	4	frame
+//	10	2	scanline
+//	12	2	horizpos (signed)
+//
+// the flags byte is made up of the boolean fields of the Entry and the memory
+// area in bits 4 to 6
+
+const magicString = "gopher2600bustrace"
+const version = 1
+
+const entrySize = 14
+
+const noBank = 0xffff
+
+const (
+	flagWrite = 0x01 << iota
+	flagPhantom
+	flagListen
+	flagIdle
+)
+
+const (
+	areaShift = 4
+	areaMask  = 0x07
+)
+
+// Writer writes entries to a bus trace file
+type Writer struct {
+	w   *bufio.Writer
+	buf [entrySize]byte
+}
+
+// NewWriter is the preferred method of initialisation for the Writer type. The
+// file header is written immediately.
+func NewWriter(w io.Writer) (*Writer, error) {
+	wr := &Writer{w: bufio.NewWriter(w)}
+
+	if _, err := wr.w.WriteString(magicString); err != nil {
+		return nil, errors.New(errors.BusTraceError, err)
+	}
+	if err := wr.w.WriteByte(version); err != nil {
+		return nil, errors.New(errors.BusTraceError, err)
+	}
+
+	return wr, nil
+}
+
+// Write a single entry. Output is buffered so Flush() must be called before
+// the underlying io.Writer is closed.
+func (wr *Writer) Write(e Entry) error {
+	var flags uint8
+	if e.Write {
+		flags |= flagWrite
+	}
+	if e.Phantom {
+		flags |= flagPhantom
+	}
+	if e.Listen {
+		flags |= flagListen
+	}
+	if e.Idle {
+		flags |= flagIdle
+	}
+	flags |= (uint8(e.Area) & areaMask) << areaShift
+
+	bank := uint16(noBank)
+	if e.Bank >= 0 {
+		bank = uint16(e.Bank)
+	}
+
+	binary.LittleEndian.PutUint16(wr.buf[0:], e.Address)
+	wr.buf[2] = e.Data
+	wr.buf[3] = flags
+	binary.LittleEndian.PutUint16(wr.buf[4:], bank)
+	binary.LittleEndian.PutUint32(wr.buf[6:], uint32(e.Frame))
+	binary.LittleEndian.PutUint16(wr.buf[10:], uint16(e.Scanline))
+	binary.LittleEndian.PutUint16(wr.buf[12:], uint16(int16(e.HorizPos)))
+
+	if _, err := wr.w.Write(wr.buf[:]); err != nil {
+		return errors.New(errors.BusTraceError, err)
+	}
+
+	return nil
+}
+
+// Flush any buffered entries to the underlying io.Writer
+func (wr *Writer) Flush() error {
+	if err := wr.w.Flush(); err != nil {
+		return errors.New(errors.BusTraceError, err)
+	}
+	return nil
+}
+
+// Reader reads entries from a bus trace file
+type Reader struct {
+	r   *bufio.Reader
+	buf [entrySize]byte
+}
+
+// NewReader is the preferred method of initialisation for the Reader type. The
+// file header is checked immediately.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+
+	hdr := make([]byte, len(magicString)+1)
+	if _, err := io.ReadFull(rd.r, hdr); err != nil || string(hdr[:len(magicString)]) != magicString {
+		return nil, errors.New(errors.BusTraceError, "not a bus trace file")
+	}
+
+	if hdr[len(magicString)] != version {
+		return nil, errors.New(errors.BusTraceError, fmt.Sprintf("unsupported version (%d)", hdr[len(magicString)]))
+	}
+
+	return rd, nil
+}
+
+// Read the next entry. Returns io.EOF when there are no more entries.
+func (rd *Reader) Read() (Entry, error) {
+	n, err := io.ReadFull(rd.r, rd.buf[:])
+	if err != nil {
+		if n == 0 && err == io.EOF {
+			return Entry{}, io.EOF
+		}
+		return Entry{}, errors.New(errors.BusTraceError, "truncated entry")
+	}
+
+	flags := rd.buf[3]
+
+	e := Entry{
+		Address:  binary.LittleEndian.Uint16(rd.buf[0:]),
+		Data:     rd.buf[2],
+		Area:     memorymap.Area((flags >> areaShift) & areaMask),
+		Bank:     int(binary.LittleEndian.Uint16(rd.buf[4:])),
+		Write:    flags&flagWrite == flagWrite,
+		Phantom:  flags&flagPhantom == flagPhantom,
+		Listen:   flags&flagListen == flagListen,
+		Idle:     flags&flagIdle == flagIdle,
+		Frame:    int(binary.LittleEndian.Uint32(rd.buf[6:])),
+		Scanline: int(binary.LittleEndian.Uint16(rd.buf[10:])),
+		HorizPos: int(int16(binary.LittleEndian.Uint16(rd.buf[12:]))),
+	}
+
+	if e.Bank == noBank {
+		e.Bank = -1
+	}
+
+	return e, nil
+}
+
+// Decode reads every entry from a bus trace file and writes them to the
+// output, one entry per line, in a human readable format.
+func Decode(r io.Reader, output io.Writer) error {
+	rd, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	for {
+		e, err := rd.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintln(output, e); err != nil {
+			return errors.New(errors.BusTraceError, err)
+		}
+	}
+}
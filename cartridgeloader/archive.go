@@ -0,0 +1,261 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the gnu general public license as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridgeloader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// the character that separates the archive filename from the name of the file
+// inside the archive. for example:
+//
+//	roms/library.zip#atari/Pitfall.bin
+const archiveSelector = "#"
+
+type archiveFormat int
+
+const (
+	notArchive archiveFormat = iota
+	archiveZip
+	archiveGzip
+	archiveTarGzip
+)
+
+// archiveFormatOf decides the archive format from the filename extension
+func archiveFormatOf(filename string) archiveFormat {
+	lc := strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(lc, ".zip"):
+		return archiveZip
+	case strings.HasSuffix(lc, ".tar.gz") || strings.HasSuffix(lc, ".tgz"):
+		return archiveTarGzip
+	case strings.HasSuffix(lc, ".gz"):
+		return archiveGzip
+	}
+	return notArchive
+}
+
+// trimArchiveExt removes the archive extension from the filename
+func trimArchiveExt(filename string) string {
+	lc := strings.ToLower(filename)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip", ".gz"} {
+		if strings.HasSuffix(lc, ext) {
+			return filename[:len(filename)-len(ext)]
+		}
+	}
+	return filename
+}
+
+// splitFilename separates the archive filename from the inner filename. if
+// the filename does not refer to an archive then the inner filename will be
+// empty and the archive format will be notArchive
+func splitFilename(filename string) (string, string, archiveFormat) {
+	if i := strings.LastIndex(filename, archiveSelector); i != -1 {
+		if f := archiveFormatOf(filename[:i]); f != notArchive {
+			return filename[:i], filename[i+len(archiveSelector):], f
+		}
+	}
+	return filename, "", archiveFormatOf(filename)
+}
+
+// isROMfile returns true if the filename looks like it is for a cartridge
+func isROMfile(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".bin", ".a26", ".rom":
+		return true
+	}
+	return false
+}
+
+// extract a file from the archive data. if inner is empty then the first file
+// that looks like a cartridge is extracted. returns the data and the name of
+// the extracted file
+func extract(format archiveFormat, archive string, raw []byte, inner string) ([]byte, string, error) {
+	switch format {
+	case archiveZip:
+		return extractZip(raw, inner)
+
+	case archiveTarGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, "", err
+		}
+		defer gz.Close()
+		return extractTar(gz, inner)
+
+	case archiveGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, "", err
+		}
+		defer gz.Close()
+
+		// a gzip file contains only one file so there is nothing to select.
+		// the name of the file is taken from the gzip header if possible
+		name := gz.Name
+		if name == "" {
+			name = path.Base(trimArchiveExt(archive))
+		}
+		if inner != "" && inner != name {
+			return nil, "", fmt.Errorf("%s not found in %s", inner, archive)
+		}
+
+		data, err := ioutil.ReadAll(gz)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, name, nil
+	}
+
+	return raw, "", nil
+}
+
+func extractZip(raw []byte, inner string) ([]byte, string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+
+		if (inner == "" && isROMfile(zf.Name)) || zf.Name == inner {
+			f, err := zf.Open()
+			if err != nil {
+				return nil, "", err
+			}
+			defer f.Close()
+
+			data, err := ioutil.ReadAll(f)
+			if err != nil {
+				return nil, "", err
+			}
+			return data, zf.Name, nil
+		}
+	}
+
+	if inner == "" {
+		return nil, "", fmt.Errorf("no cartridge found in archive")
+	}
+	return nil, "", fmt.Errorf("%s not found in archive", inner)
+}
+
+func extractTar(r io.Reader, inner string) ([]byte, string, error) {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if (inner == "" && isROMfile(hdr.Name)) || hdr.Name == inner {
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, "", err
+			}
+			return data, hdr.Name, nil
+		}
+	}
+
+	if inner == "" {
+		return nil, "", fmt.Errorf("no cartridge found in archive")
+	}
+	return nil, "", fmt.Errorf("%s not found in archive", inner)
+}
+
+// IsArchive returns true if the Filename refers to an archive or to a file
+// inside an archive.
+func (cl Loader) IsArchive() bool {
+	_, _, format := splitFilename(cl.Filename)
+	return format != notArchive
+}
+
+// ArchiveFilename returns the Filename without any inner file selector. If the
+// Filename does not refer to an archive then Filename is returned unchanged.
+func (cl Loader) ArchiveFilename() string {
+	archive, _, _ := splitFilename(cl.Filename)
+	return archive
+}
+
+// InnerFilename returns the name of the file inside the archive that is
+// loaded by Load(). If no file has been selected in the Filename then the
+// archive is opened to find the first file that looks like a cartridge.
+// Returns the empty string if Filename does not refer to an archive or if no
+// suitable file can be found.
+//
+// The name is remembered so the archive is opened at most once, and not at
+// all if Load() has already been called.
+func (cl *Loader) InnerFilename() string {
+	if !cl.innerResolved {
+		cl.inner = cl.findInnerFilename()
+		cl.innerResolved = true
+	}
+	return cl.inner
+}
+
+// findInnerFilename does the work for InnerFilename()
+func (cl Loader) findInnerFilename() string {
+	archive, inner, format := splitFilename(cl.Filename)
+	if format == notArchive {
+		return ""
+	}
+
+	// gzip files need to be opened to discover the name of the inner file
+	if inner != "" && format != archiveGzip {
+		return inner
+	}
+
+	raw, err := readFile(archive)
+	if err != nil {
+		return ""
+	}
+
+	_, name, err := extract(format, archive, raw, inner)
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
+// wrap errors from the archive in the errors type used by the package
+func archiveError(filename string, err error) error {
+	return errors.New(errors.CartridgeLoader, fmt.Sprintf("%s: %v", filename, err))
+}
@@ -28,5 +28,17 @@
 // When the cartridge is ready to be loaded the emulator calls the Load()
 // function. This function currently handles files (specified with Filename)
 // that are stored locally and also over http. Other protocols could easily be
-// added. A good improvement would be to allow loading from zip or tar files.
+// added.
+//
+// Cartridges can also be loaded from zip, gzip and tar.gz archives. By
+// default, the first file in the archive with a cartridge-like extension
+// (.bin, .a26 or .rom) is loaded. A specific file can be selected by
+// appending its name to the archive filename with a # character.
+//
+//	cl := cartridgeloader.Loader{
+//		Filename: "roms/library.zip#atari/Pitfall.bin",
+//	}
+//
+// ShortName() and the Hash field refer to the file inside the archive and not
+// the archive itself.
 package cartridgeloader
@@ -20,6 +20,7 @@
 package cartridgeloader
 
 import (
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path"
@@ -42,12 +43,25 @@ type Loader struct {
 	Hash string
 
 	data []byte
+
+	// the name of the file inside the archive. finding the name can mean
+	// reading and decompressing the archive so it is only done once, either
+	// by Load() or by the first call to InnerFilename()
+	inner         string
+	innerResolved bool
 }
 
-// ShortName returns a shortened version of the CartridgeLoader filename
-func (cl Loader) ShortName() string {
-	shortCartName := path.Base(cl.Filename)
-	shortCartName = strings.TrimSuffix(shortCartName, path.Ext(cl.Filename))
+// ShortName returns a shortened version of the CartridgeLoader filename. If
+// the Filename refers to an archive then the name of the file inside the
+// archive is used
+func (cl *Loader) ShortName() string {
+	name := cl.InnerFilename()
+	if name == "" {
+		name = trimArchiveExt(cl.ArchiveFilename())
+	}
+
+	shortCartName := path.Base(name)
+	shortCartName = strings.TrimSuffix(shortCartName, path.Ext(name))
 	return shortCartName
 }
 
@@ -56,51 +70,52 @@ func (cl Loader) HasLoaded() bool {
 	return len(cl.data) > 0
 }
 
-// Load the cartridge
-func (cl Loader) Load() ([]byte, error) {
+// Load the cartridge. If the Filename refers to an archive then the
+// cartridge data is extracted from the archive
+func (cl *Loader) Load() ([]byte, error) {
 	if len(cl.data) > 0 {
 		return cl.data, nil
 	}
 
-	var err error
+	archive, inner, format := splitFilename(cl.Filename)
 
-	if strings.HasPrefix(cl.Filename, "http://") {
-		var resp *http.Response
+	raw, err := readFile(archive)
+	if err != nil {
+		return nil, errors.New(errors.CartridgeLoader, cl.Filename)
+	}
 
-		resp, err = http.Get(cl.Filename)
-		if err != nil {
-			return nil, errors.New(errors.CartridgeLoader, cl.Filename)
-		}
-		defer resp.Body.Close()
+	if format == notArchive {
+		cl.data = raw
+		cl.innerResolved = true
+		return cl.data, nil
+	}
 
-		size := resp.ContentLength
+	cl.data, cl.inner, err = extract(format, archive, raw, inner)
+	if err != nil {
+		return nil, archiveError(cl.Filename, err)
+	}
+	cl.innerResolved = true
 
-		cl.data = make([]byte, size)
-		_, err = resp.Body.Read(cl.data)
-		if err != nil {
-			return nil, errors.New(errors.CartridgeLoader, cl.Filename)
-		}
-	} else {
-		var f *os.File
-		f, err = os.Open(cl.Filename)
-		if err != nil {
-			return nil, errors.New(errors.CartridgeLoader, cl.Filename)
-		}
-		defer f.Close()
+	return cl.data, nil
+}
 
-		// get file info
-		cfi, err := f.Stat()
+// readFile reads the entire file from disk or over http
+func readFile(filename string) ([]byte, error) {
+	if strings.HasPrefix(filename, "http://") {
+		resp, err := http.Get(filename)
 		if err != nil {
-			return nil, errors.New(errors.CartridgeLoader, cl.Filename)
+			return nil, err
 		}
-		size := cfi.Size()
+		defer resp.Body.Close()
 
-		cl.data = make([]byte, size)
-		_, err = f.Read(cl.data)
-		if err != nil {
-			return nil, errors.New(errors.CartridgeLoader, cl.Filename)
-		}
+		return ioutil.ReadAll(resp.Body)
 	}
 
-	return cl.data, nil
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
 }
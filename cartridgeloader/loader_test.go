@@ -0,0 +1,265 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the gnu general public license as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridgeloader_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
+)
+
+var cartData = []byte{0x78, 0xd8, 0xa2, 0x00, 0x8a, 0xca, 0x9a, 0x48, 0xd0, 0xfb}
+
+func writeZip(t *testing.T, filename string, files map[string][]byte, order []string) {
+	t.Helper()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, n := range order {
+		w, err := zw.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(files[n]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func gzipData(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	gz.Name = name
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func load(t *testing.T, filename string) []byte {
+	t.Helper()
+
+	cl := cartridgeloader.Loader{Filename: filename}
+	data, err := cl.Load()
+	if err != nil {
+		t.Fatalf("unexpected error loading %s (%s)", filename, err)
+	}
+	return data
+}
+
+func TestZip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cartridgeloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "library.zip")
+	writeZip(t, filename, map[string][]byte{
+		"readme.txt":      []byte("not a cartridge"),
+		"atari/test.bin":  cartData,
+		"atari/other.a26": {0x00},
+	}, []string{"readme.txt", "atari/test.bin", "atari/other.a26"})
+
+	// first cartridge-like file is loaded by default
+	if !bytes.Equal(load(t, filename), cartData) {
+		t.Errorf("wrong data loaded from zip")
+	}
+
+	cl := cartridgeloader.Loader{Filename: filename}
+	if cl.ShortName() != "test" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+
+	// specific file
+	if !bytes.Equal(load(t, filename+"#atari/other.a26"), []byte{0x00}) {
+		t.Errorf("wrong data loaded from zip with selector")
+	}
+
+	cl = cartridgeloader.Loader{Filename: filename + "#atari/other.a26"}
+	if cl.ShortName() != "other" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+
+	// missing file
+	cl = cartridgeloader.Loader{Filename: filename + "#missing.bin"}
+	if _, err := cl.Load(); err == nil {
+		t.Errorf("expected error loading missing file from zip")
+	}
+
+	// no cartridge in archive
+	filename = filepath.Join(dir, "empty.zip")
+	writeZip(t, filename, map[string][]byte{
+		"readme.txt": []byte("not a cartridge"),
+	}, []string{"readme.txt"})
+
+	cl = cartridgeloader.Loader{Filename: filename}
+	if _, err := cl.Load(); err == nil {
+		t.Errorf("expected error loading zip with no cartridge")
+	}
+}
+
+func TestGzip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cartridgeloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// name taken from gzip header
+	filename := filepath.Join(dir, "archive.gz")
+	if err := ioutil.WriteFile(filename, gzipData(t, "test.bin", cartData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(load(t, filename), cartData) {
+		t.Errorf("wrong data loaded from gzip")
+	}
+
+	cl := cartridgeloader.Loader{Filename: filename}
+	if cl.ShortName() != "test" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+
+	// name taken from the archive filename
+	filename = filepath.Join(dir, "pitfall.bin.gz")
+	if err := ioutil.WriteFile(filename, gzipData(t, "", cartData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cl = cartridgeloader.Loader{Filename: filename}
+	if cl.ShortName() != "pitfall" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+}
+
+func TestTarGzip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cartridgeloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{"docs/manual.txt", []byte("not a cartridge")},
+		{"roms/test.rom", cartData},
+	} {
+		hdr := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.data)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	filename := filepath.Join(dir, "library.tar.gz")
+	if err := ioutil.WriteFile(filename, gzipData(t, "", b.Bytes()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(load(t, filename), cartData) {
+		t.Errorf("wrong data loaded from tar.gz")
+	}
+
+	if !bytes.Equal(load(t, filename+"#roms/test.rom"), cartData) {
+		t.Errorf("wrong data loaded from tar.gz with selector")
+	}
+
+	cl := cartridgeloader.Loader{Filename: filename}
+	if cl.ShortName() != "test" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+}
+
+func TestInnerFilenameResolvedOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cartridgeloader")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "library.zip")
+	writeZip(t, filename, map[string][]byte{
+		"readme.txt":     []byte("not a cartridge"),
+		"atari/test.bin": cartData,
+	}, []string{"readme.txt", "atari/test.bin"})
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeFile(w, r, filename)
+	}))
+	defer srv.Close()
+
+	// the archive is fetched for the first call to ShortName() only
+	cl := cartridgeloader.Loader{Filename: srv.URL + "/library.zip"}
+	for i := 0; i < 3; i++ {
+		if cl.ShortName() != "test" {
+			t.Errorf("unexpected short name (%s)", cl.ShortName())
+		}
+	}
+	if requests != 1 {
+		t.Errorf("archive fetched %d times for ShortName()", requests)
+	}
+
+	// Load() resolves the name so ShortName() doesn't fetch the archive again
+	requests = 0
+	cl = cartridgeloader.Loader{Filename: srv.URL + "/library.zip"}
+	if _, err := cl.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cl.ShortName() != "test" {
+		t.Errorf("unexpected short name (%s)", cl.ShortName())
+	}
+	if requests != 1 {
+		t.Errorf("archive fetched %d times for Load() and ShortName()", requests)
+	}
+}
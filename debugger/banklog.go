@@ -0,0 +1,113 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// the bank log records every bank switch made by the cartridge. it is a ring
+// buffer so only the most recent switches are kept.
+
+package debugger
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/debugger/terminal"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// the number of bank switches kept in the bank log
+const bankLogLength = 256
+
+// bankLogEntry is a bank switch along with the state of the emulation at the
+// moment of the switch
+type bankLogEntry struct {
+	cartridge.BankSwitch
+
+	// address of the instruction that caused the switch
+	pc uint16
+
+	frame    int
+	scanline int
+	horizpos int
+}
+
+func (e bankLogEntry) String() string {
+	return fmt.Sprintf("fr %-4d sl %-3d hp %-3d PC %#04x %s", e.frame, e.scanline, e.horizpos, e.pc, e.BankSwitch)
+}
+
+type bankLog struct {
+	dbg *Debugger
+
+	entries [bankLogLength]bankLogEntry
+
+	// the index of the next entry to be written and the number of valid
+	// entries in the log
+	next  int
+	count int
+}
+
+// newBankLog is the preferred method of initialisation for the bankLog type
+func newBankLog(dbg *Debugger) *bankLog {
+	return &bankLog{dbg: dbg}
+}
+
+// BankSwitched implements the cartridge.BankSwitchListener interface
+func (bl *bankLog) BankSwitched(bs cartridge.BankSwitch) {
+	e := bankLogEntry{
+		BankSwitch: bs,
+		pc:         bl.dbg.vcs.CPU.LastResult.Address,
+	}
+
+	// errors from the television are not important enough to interrupt the
+	// emulation
+	e.frame, _ = bl.dbg.vcs.TV.GetState(television.ReqFramenum)
+	e.scanline, _ = bl.dbg.vcs.TV.GetState(television.ReqScanline)
+	e.horizpos, _ = bl.dbg.vcs.TV.GetState(television.ReqHorizPos)
+
+	bl.entries[bl.next] = e
+	bl.next = (bl.next + 1) % bankLogLength
+	if bl.count < bankLogLength {
+		bl.count++
+	}
+
+	bl.dbg.breakpoints.checkBankSwitch(e)
+}
+
+// clear all entries from the log
+func (bl *bankLog) clear() {
+	bl.next = 0
+	bl.count = 0
+}
+
+// list the most recent entries in the log, oldest first. a value of less than
+// one lists every entry
+func (bl *bankLog) list(n int) {
+	if bl.count == 0 {
+		bl.dbg.printLine(terminal.StyleFeedback, "bank log is empty")
+		return
+	}
+
+	if n < 1 || n > bl.count {
+		n = bl.count
+	}
+
+	for i := bl.count - n; i < bl.count; i++ {
+		idx := (bl.next - bl.count + i + bankLogLength) % bankLogLength
+		bl.dbg.printLine(terminal.StyleInstrument, "%s", bl.entries[idx])
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/jetsetilly/gopher2600/debugger/terminal/commandline"
 	"github.com/jetsetilly/gopher2600/disassembly"
 	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
 )
 
 // breakpoints keeps track of all the currently defined breakers
@@ -45,6 +46,60 @@ type breakpoints struct {
 	// these targets every time hasBreak() is called.
 	checkPcBreak   *target
 	checkBankBreak *target
+
+	// break whenever a cartridge hotspot is accessed. hotspot breaks are
+	// not part of the breaks array because they do not have a target value
+	hotspots bool
+
+	// the memory access ID of the last hotspot check. we don't want to report
+	// the same access more than once
+	lastHotspotAccessID int
+
+	// breaks on bank switches. like hotspot breaks, these are not part of the
+	// breaks array. bank switches are reported by the bank log as they happen
+	// so matches are accumulated until the next call to check()
+	bankSwitches       []bankSwitchBreak
+	bankSwitchMessages string
+}
+
+// bankSwitchBreak defines a break condition on a cartridge bank switch
+type bankSwitchBreak struct {
+	// the bank being switched to. a value of -1 means any bank
+	bank int
+
+	// the range of (mapped) addresses that the switching instruction must be
+	// in. only used if useRange is true
+	useRange bool
+	from     uint16
+	to       uint16
+}
+
+func (b bankSwitchBreak) String() string {
+	s := strings.Builder{}
+	s.WriteString("bank switch")
+	if b.bank >= 0 {
+		s.WriteString(fmt.Sprintf(" -> %d", b.bank))
+	}
+	if b.useRange {
+		s.WriteString(fmt.Sprintf(" from %#04x-%#04x", b.from, b.to))
+	}
+	return s.String()
+}
+
+// matches returns true if the bank log entry satisfies the break condition
+func (b bankSwitchBreak) matches(e bankLogEntry) bool {
+	if b.bank >= 0 && b.bank != e.NewBank {
+		return false
+	}
+
+	if b.useRange {
+		pc, _ := memorymap.MapAddress(e.pc, true)
+		if pc < b.from || pc > b.to {
+			return false
+		}
+	}
+
+	return true
 }
 
 // breaker defines a specific break condition
@@ -180,6 +235,9 @@ func newBreakpoints(dbg *Debugger) (*breakpoints, error) {
 // clear all breakpoints
 func (bp *breakpoints) clear() {
 	bp.breaks = make([]breaker, 0, 10)
+	bp.hotspots = false
+	bp.bankSwitches = make([]bankSwitchBreak, 0, 10)
+	bp.bankSwitchMessages = ""
 }
 
 // drop a specific breakpoint by position in list
@@ -209,18 +267,48 @@ func (bp *breakpoints) check(previousResult string) string {
 			checkString.WriteString(fmt.Sprintf("break on %s\n", bp.breaks[i]))
 		}
 	}
+
+	if bp.hotspots {
+		mem := bp.dbg.vcs.Mem
+		if mem.LastAccessID != bp.lastHotspotAccessID {
+			bp.lastHotspotAccessID = mem.LastAccessID
+			if h, ok := mem.Cart.HotspotAt(mem.LastAccessUnmapped, mem.LastAccessWrite); ok {
+				checkString.WriteString(fmt.Sprintf("break on hotspot %s\n", h))
+			}
+		}
+	}
+
+	checkString.WriteString(bp.bankSwitchMessages)
+	bp.bankSwitchMessages = ""
+
 	return checkString.String()
 }
 
+// checkBankSwitch is called by the bank log for every bank switch. matches
+// are reported the next time check() is called
+func (bp *breakpoints) checkBankSwitch(e bankLogEntry) {
+	for _, b := range bp.bankSwitches {
+		if b.matches(e) {
+			bp.bankSwitchMessages = fmt.Sprintf("%sbreak on %s (%s)\n", bp.bankSwitchMessages, b, e.BankSwitch)
+		}
+	}
+}
+
 // list currently defined breakpoints
 func (bp breakpoints) list() {
-	if len(bp.breaks) == 0 {
+	if len(bp.breaks) == 0 && !bp.hotspots && len(bp.bankSwitches) == 0 {
 		bp.dbg.printLine(terminal.StyleFeedback, "no breakpoints")
 	} else {
 		bp.dbg.printLine(terminal.StyleFeedback, "breakpoints:")
 		for i := range bp.breaks {
 			bp.dbg.printLine(terminal.StyleFeedback, "% 2d: %s", i, bp.breaks[i])
 		}
+		if bp.hotspots {
+			bp.dbg.printLine(terminal.StyleFeedback, "  *: hotspots")
+		}
+		for i := range bp.bankSwitches {
+			bp.dbg.printLine(terminal.StyleFeedback, "  *: %s", bp.bankSwitches[i])
+		}
 	}
 }
 
@@ -243,6 +331,28 @@ func (bp breakpoints) list() {
 //
 // !!TODO: simplify breakpoints parser to match help description
 func (bp *breakpoints) parseBreakpoint(tokens *commandline.Tokens) error {
+	// the HOTSPOT keyword does not take a value and cannot be combined with
+	// other break conditions
+	if tok, ok := tokens.Peek(); ok && strings.ToUpper(tok) == "HOTSPOT" {
+		tokens.Get()
+		if tokens.Remaining() > 0 {
+			return errors.New(errors.CommandError, "HOTSPOT cannot be combined with other conditions")
+		}
+		if bp.hotspots {
+			return errors.New(errors.CommandError, "already exists (hotspots)")
+		}
+		bp.hotspots = true
+		bp.lastHotspotAccessID = bp.dbg.vcs.Mem.LastAccessID
+		return nil
+	}
+
+	// the BANKSWITCH keyword is also a special case. it can optionally be
+	// followed by a bank number and a range of addresses
+	if tok, ok := tokens.Peek(); ok && strings.ToUpper(tok) == "BANKSWITCH" {
+		tokens.Get()
+		return bp.parseBankSwitch(tokens)
+	}
+
 	andBreaks := false
 
 	// default target of CPU PC. meaning that "BREAK n" will cause a breakpoint
@@ -376,6 +486,58 @@ func (bp *breakpoints) parseBreakpoint(tokens *commandline.Tokens) error {
 	return nil
 }
 
+// parse tokens following the BANKSWITCH keyword. for example:
+//
+//	BANKSWITCH
+//	BANKSWITCH 3
+//	BANKSWITCH 3 FROM 0xf000 0xf0ff
+func (bp *breakpoints) parseBankSwitch(tokens *commandline.Tokens) error {
+	nb := bankSwitchBreak{bank: -1}
+
+	if tok, ok := tokens.Get(); ok {
+		bank, err := strconv.Atoi(tok)
+		if err != nil || bank < 0 {
+			return errors.New(errors.CommandError, fmt.Sprintf("invalid bank (%s)", tok))
+		}
+		nb.bank = bank
+
+		if tok, ok := tokens.Get(); ok {
+			if strings.ToUpper(tok) != "FROM" {
+				return errors.New(errors.CommandError, fmt.Sprintf("unexpected token (%s)", tok))
+			}
+
+			from, _ := tokens.Get()
+			to, _ := tokens.Get()
+
+			fai := bp.dbg.dbgmem.mapAddress(from, true)
+			if fai == nil {
+				return errors.New(errors.CommandError, fmt.Sprintf("invalid address (%s)", from))
+			}
+			tai := bp.dbg.dbgmem.mapAddress(to, true)
+			if tai == nil {
+				return errors.New(errors.CommandError, fmt.Sprintf("invalid address (%s)", to))
+			}
+
+			nb.useRange = true
+			nb.from = fai.mappedAddress
+			nb.to = tai.mappedAddress
+			if nb.from > nb.to {
+				nb.from, nb.to = nb.to, nb.from
+			}
+		}
+	}
+
+	for _, b := range bp.bankSwitches {
+		if b == nb {
+			return errors.New(errors.CommandError, fmt.Sprintf("already exists (%s)", b))
+		}
+	}
+
+	bp.bankSwitches = append(bp.bankSwitches, nb)
+
+	return nil
+}
+
 const noBreakEqualivalent = -1
 
 // checkBreaker returns the index number of the matching breakpoint. returns
@@ -57,4 +57,34 @@ func (trm *mockTerm) testBreakpoints() {
 
 	trm.sndInput("BREAK HP 100")
 	trm.cmpOutput("")
+
+	// hotspot breaks are listed after the numbered breaks
+	trm.sndInput("BREAK HOTSPOT")
+	trm.cmpOutput("")
+
+	trm.sndInput("LIST BREAKS")
+	trm.cmpOutput("  *: hotspots")
+
+	trm.sndInput("BREAK HOTSPOT")
+	trm.cmpOutput("already exists (hotspots)")
+
+	trm.sndInput("BREAK HOTSPOT SL 100")
+	trm.cmpOutput("HOTSPOT cannot be combined with other conditions")
+
+	// bank switch breaks are also listed after the numbered breaks
+	trm.sndInput("BREAK BANKSWITCH")
+	trm.cmpOutput("")
+
+	trm.sndInput("BREAK BANKSWITCH 2 FROM 0xf0ff 0xf000")
+	trm.cmpOutput("")
+
+	trm.sndInput("LIST BREAKS")
+	trm.cmpOutput("  *: bank switch -> 2 from 0x1000-0x10ff")
+
+	trm.sndInput("BREAK BANKSWITCH 2 FROM 0xf000 0xf0ff")
+	trm.cmpOutput("already exists (bank switch -> 2 from 0x1000-0x10ff)")
+
+	// nothing has been switched
+	trm.sndInput("BANKLOG")
+	trm.cmpOutput("bank log is empty")
 }
@@ -0,0 +1,186 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// the bus trace records every access of the address bus, cycle by cycle. like
+// the bank log it is a ring buffer so only the most recent accesses are kept.
+// the trace can also be streamed to a file in the format defined by the
+// bustrace package.
+//
+// unlike the other logs, the bus trace is not active by default because it is
+// called for every CPU cycle.
+
+package debugger
+
+import (
+	"os"
+
+	"github.com/jetsetilly/gopher2600/bustrace"
+	"github.com/jetsetilly/gopher2600/debugger/terminal"
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// the number of bus accesses kept in the bus trace
+const busTraceLength = 1024
+
+type busTrace struct {
+	dbg *Debugger
+
+	entries [busTraceLength]bustrace.Entry
+
+	// the index of the next entry to be written and the number of valid
+	// entries in the trace
+	next  int
+	count int
+
+	// whether the bus has been accessed since the last call to TraceCycle()
+	accessed bool
+
+	// entries are also written to the file if it is not nil
+	file   *os.File
+	writer *bustrace.Writer
+}
+
+// newBusTrace is the preferred method of initialisation for the busTrace type
+func newBusTrace(dbg *Debugger) *busTrace {
+	return &busTrace{dbg: dbg}
+}
+
+// TraceAccess implements the memory.BusTracer interface
+func (bt *busTrace) TraceAccess(address uint16, area memorymap.Area, bank int, data uint8, write bool) {
+	e := bustrace.Entry{
+		Address: address,
+		Data:    data,
+		Area:    area,
+		Bank:    bank,
+		Write:   write,
+		Phantom: bt.dbg.vcs.CPU.PhantomAccess(),
+
+		// the cartridge listens to every write outside of cartridge space
+		Listen: write && area != memorymap.Cartridge,
+	}
+
+	bt.add(e)
+	bt.accessed = true
+}
+
+// TraceCycle implements the memory.BusTracer interface
+func (bt *busTrace) TraceCycle() {
+	if !bt.accessed {
+		bt.add(bustrace.Entry{Idle: true, Bank: -1})
+	}
+	bt.accessed = false
+}
+
+func (bt *busTrace) add(e bustrace.Entry) {
+	// errors from the television are not important enough to interrupt the
+	// emulation
+	e.Frame, _ = bt.dbg.vcs.TV.GetState(television.ReqFramenum)
+	e.Scanline, _ = bt.dbg.vcs.TV.GetState(television.ReqScanline)
+	e.HorizPos, _ = bt.dbg.vcs.TV.GetState(television.ReqHorizPos)
+
+	bt.entries[bt.next] = e
+	bt.next = (bt.next + 1) % busTraceLength
+	if bt.count < busTraceLength {
+		bt.count++
+	}
+
+	if bt.writer != nil {
+		if err := bt.writer.Write(e); err != nil {
+			bt.dbg.printLine(terminal.StyleError, "%v", err)
+			bt.stopFile()
+		}
+	}
+}
+
+// isActive returns true if the bus trace is attached to VCS memory
+func (bt *busTrace) isActive() bool {
+	return bt.dbg.vcs.Mem.BusTracer() == bt
+}
+
+// start attaches the bus trace to VCS memory
+func (bt *busTrace) start() {
+	bt.accessed = false
+	bt.dbg.vcs.Mem.SetBusTracer(bt)
+}
+
+// stop detaches the bus trace from VCS memory and closes any file
+func (bt *busTrace) stop() {
+	bt.dbg.vcs.Mem.SetBusTracer(nil)
+	bt.stopFile()
+}
+
+// clear all entries from the trace. the trace file is unaffected
+func (bt *busTrace) clear() {
+	bt.next = 0
+	bt.count = 0
+}
+
+// startFile begins copying new entries to the named file. any existing file
+// is closed first
+func (bt *busTrace) startFile(filename string) error {
+	bt.stopFile()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w, err := bustrace.NewWriter(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	bt.file = f
+	bt.writer = w
+
+	return nil
+}
+
+// stopFile stops copying entries to the trace file
+func (bt *busTrace) stopFile() {
+	if bt.file == nil {
+		return
+	}
+	if err := bt.writer.Flush(); err != nil {
+		bt.dbg.printLine(terminal.StyleError, "%v", err)
+	}
+	_ = bt.file.Close()
+	bt.file = nil
+	bt.writer = nil
+}
+
+// list the most recent entries in the trace, oldest first. a value of less
+// than one lists every entry
+func (bt *busTrace) list(n int) {
+	if bt.count == 0 {
+		bt.dbg.printLine(terminal.StyleFeedback, "bus trace is empty")
+		return
+	}
+
+	if n < 1 || n > bt.count {
+		n = bt.count
+	}
+
+	for i := bt.count - n; i < bt.count; i++ {
+		idx := (bt.next - bt.count + i + busTraceLength) % busTraceLength
+		bt.dbg.printLine(terminal.StyleInstrument, "%s", bt.entries[idx])
+	}
+}
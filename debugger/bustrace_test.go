@@ -0,0 +1,47 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package debugger_test
+
+func (trm *mockTerm) testBusTrace() {
+	// bus trace is off by default
+	trm.sndInput("BUSTRACE")
+	trm.cmpOutput("bus trace is off")
+
+	trm.sndInput("BUSTRACE 10")
+	trm.cmpOutput("bus trace is empty")
+
+	trm.sndInput("BUSTRACE ON")
+	trm.cmpOutput("bus trace on")
+
+	trm.sndInput("BUSTRACE")
+	trm.cmpOutput("bus trace is on")
+
+	trm.sndInput("BUSTRACE TEN")
+	trm.cmpOutput("unrecognised argument (TEN) for BUSTRACE")
+
+	trm.sndInput("BUSTRACE CLEAR")
+	trm.cmpOutput("bus trace cleared")
+
+	trm.sndInput("BUSTRACE OFF")
+	trm.cmpOutput("bus trace off")
+
+	trm.sndInput("BUSTRACE")
+	trm.cmpOutput("bus trace is off")
+}
@@ -296,11 +296,70 @@ func (dbg *Debugger) processTokens(tokens *commandline.Tokens) (bool, error) {
 						return false, err
 					}
 				}
+			case "FINGERPRINT":
+				dbg.printInstrument(dbg.vcs.Mem.Cart.FingerprintReport())
 			}
 		} else {
 			dbg.printInstrument(dbg.vcs.Mem.Cart)
 		}
 
+	case cmdBankLog:
+		arg, ok := tokens.Get()
+		if ok {
+			if strings.ToUpper(arg) == "CLEAR" {
+				dbg.bankLog.clear()
+				dbg.printLine(terminal.StyleFeedback, "bank log cleared")
+			} else {
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					return false, errors.New(errors.CommandError, "number of entries must be numeric")
+				}
+				dbg.bankLog.list(n)
+			}
+		} else {
+			dbg.bankLog.list(0)
+		}
+
+	case cmdBusTrace:
+		arg, ok := tokens.Get()
+		if ok {
+			switch strings.ToUpper(arg) {
+			case "ON":
+				dbg.busTrace.start()
+				dbg.printLine(terminal.StyleFeedback, "bus trace on")
+			case "OFF":
+				dbg.busTrace.stop()
+				dbg.printLine(terminal.StyleFeedback, "bus trace off")
+			case "CLEAR":
+				dbg.busTrace.clear()
+				dbg.printLine(terminal.StyleFeedback, "bus trace cleared")
+			case "FILE":
+				fn, _ := tokens.Get()
+				if strings.ToUpper(fn) == "OFF" {
+					dbg.busTrace.stopFile()
+					dbg.printLine(terminal.StyleFeedback, "bus trace file closed")
+				} else {
+					if err := dbg.busTrace.startFile(fn); err != nil {
+						return false, errors.New(errors.CommandError, err)
+					}
+					dbg.busTrace.start()
+					dbg.printLine(terminal.StyleFeedback, "tracing bus to %s", fn)
+				}
+			default:
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					return false, errors.New(errors.CommandError, "number of entries must be numeric")
+				}
+				dbg.busTrace.list(n)
+			}
+		} else {
+			if dbg.busTrace.isActive() {
+				dbg.printLine(terminal.StyleFeedback, "bus trace is on")
+			} else {
+				dbg.printLine(terminal.StyleFeedback, "bus trace is off")
+			}
+		}
+
 	case cmdPatch:
 		f, _ := tokens.Get()
 		patched, err := patch.CartridgeMemory(dbg.vcs.Mem.Cart, f)
@@ -928,6 +987,30 @@ func (dbg *Debugger) processTokens(tokens *commandline.Tokens) (bool, error) {
 				p.SwitchType(input.PaddleType)
 			case "keypad":
 				p.SwitchType(input.KeypadType)
+			case "driving":
+				p.SwitchType(input.DrivingType)
+			case "trakball":
+				p.SwitchType(input.TrakBallType)
+			case "amigamouse":
+				p.SwitchType(input.AmigaMouseType)
+			case "stmouse":
+				p.SwitchType(input.STMouseType)
+			case "boostergrip":
+				p.SwitchType(input.BoosterGripType)
+			case "genesis":
+				p.SwitchType(input.GenesisType)
+			case "lightgun":
+				p.SwitchType(input.LightGunType)
+			case "savekey":
+				err := p.SwitchType(input.SaveKeyType)
+				if err != nil {
+					return false, err
+				}
+			case "atarivox":
+				err := p.SwitchType(input.AtariVoxType)
+				if err != nil {
+					return false, err
+				}
 			}
 		}
 
@@ -940,6 +1023,24 @@ func (dbg *Debugger) processTokens(tokens *commandline.Tokens) (bool, error) {
 			s.WriteString("Paddle")
 		case input.KeypadType:
 			s.WriteString("Keypad")
+		case input.DrivingType:
+			s.WriteString("Driving")
+		case input.TrakBallType:
+			s.WriteString("TrakBall")
+		case input.AmigaMouseType:
+			s.WriteString("AmigaMouse")
+		case input.STMouseType:
+			s.WriteString("STMouse")
+		case input.BoosterGripType:
+			s.WriteString("BoosterGrip")
+		case input.GenesisType:
+			s.WriteString("Genesis")
+		case input.LightGunType:
+			s.WriteString("LightGun")
+		case input.SaveKeyType:
+			s.WriteString("SaveKey")
+		case input.AtariVoxType:
+			s.WriteString("AtariVox")
 		default:
 			s.WriteString("Unknown")
 		}
@@ -950,6 +1051,139 @@ func (dbg *Debugger) processTokens(tokens *commandline.Tokens) (bool, error) {
 
 		dbg.printLine(terminal.StyleFeedback, s.String())
 
+	case cmdSaveKey:
+		// use the SaveKey in the right player port in preference to the left.
+		// the AtariVox also has a SaveKey
+		var sk *input.SaveKey
+		if t := dbg.vcs.HandController1.ControllerType; t == input.SaveKeyType || t == input.AtariVoxType {
+			sk = dbg.vcs.HandController1.SaveKey
+		} else if t := dbg.vcs.HandController0.ControllerType; t == input.SaveKeyType || t == input.AtariVoxType {
+			sk = dbg.vcs.HandController0.SaveKey
+		} else {
+			return false, errors.New(errors.CommandError, "no SaveKey attached")
+		}
+
+		var address uint16
+		arg, ok := tokens.Get()
+		if ok {
+			a, err := strconv.ParseUint(arg, 0, 16)
+			if err != nil || a >= input.SaveKeySize {
+				return false, errors.New(errors.CommandError, fmt.Sprintf("invalid EEPROM address (%s)", arg))
+			}
+			address = uint16(a)
+		}
+
+		dbg.printInstrument(sk)
+		dbg.printLine(terminal.StyleInstrument, "%s", sk.Dump(address))
+
+	case cmdAtariVox:
+		arg, ok := tokens.Get()
+		if ok {
+			switch strings.ToUpper(arg) {
+			case "CLEAR":
+				dbg.speechLog.clear()
+				dbg.printLine(terminal.StyleFeedback, "speech log cleared")
+			case "LOG":
+				fn, _ := tokens.Get()
+				if strings.ToUpper(fn) == "OFF" {
+					dbg.speechLog.stopFile()
+					dbg.printLine(terminal.StyleFeedback, "speech log file closed")
+				} else {
+					if err := dbg.speechLog.startFile(fn); err != nil {
+						return false, errors.New(errors.CommandError, err)
+					}
+					dbg.printLine(terminal.StyleFeedback, "logging speech to %s", fn)
+				}
+			default:
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					return false, errors.New(errors.CommandError, "number of entries must be numeric")
+				}
+				dbg.speechLog.list(n)
+			}
+		} else {
+			dbg.speechLog.list(0)
+		}
+
+	case cmdMacro:
+		var port input.Port
+
+		player, _ := tokens.Get()
+		switch strings.ToUpper(player) {
+		case "0":
+			port = dbg.vcs.HandController0
+		case "1":
+			port = dbg.vcs.HandController1
+		case "PANEL":
+			port = dbg.vcs.Panel
+		}
+
+		mcr := port.Macros()
+
+		option, _ := tokens.Get()
+		switch strings.ToUpper(option) {
+		case "LOAD":
+			fn, _ := tokens.Get()
+			if err := mcr.Load(fn); err != nil {
+				return false, err
+			}
+		case "DEFINE":
+			mac, err := input.ParseMacro(strings.TrimSpace(tokens.Remainder()))
+			tokens.End()
+			if err != nil {
+				return false, errors.New(errors.CommandError, err)
+			}
+			if err := mcr.Define(mac); err != nil {
+				return false, err
+			}
+		case "DROP":
+			name, _ := tokens.Get()
+			if err := mcr.Drop(name); err != nil {
+				return false, err
+			}
+		case "START":
+			name, _ := tokens.Get()
+			if err := mcr.Start(name); err != nil {
+				return false, err
+			}
+		case "STOP":
+			name, ok := tokens.Get()
+			if !ok {
+				mcr.StopAll()
+				return false, nil
+			}
+			if err := mcr.Stop(name); err != nil {
+				return false, err
+			}
+		case "TURBO":
+			arg, _ := tokens.Get()
+			if strings.ToUpper(arg) == "OFF" {
+				if err := mcr.Drop(input.TurboFire(1).Name); err != nil {
+					return false, err
+				}
+				return false, nil
+			}
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return false, errors.New(errors.CommandError, "number of frames must be numeric")
+			}
+			if err := mcr.Define(input.TurboFire(n)); err != nil {
+				return false, err
+			}
+		default:
+			if len(mcr.Defined()) == 0 {
+				dbg.printLine(terminal.StyleFeedback, "no macros defined")
+				return false, nil
+			}
+			for _, mac := range mcr.Defined() {
+				if mcr.IsRunning(mac.Name) {
+					dbg.printLine(terminal.StyleFeedback, "%s (running)", mac)
+				} else {
+					dbg.printLine(terminal.StyleFeedback, "%s", mac)
+				}
+			}
+		}
+
 	case cmdPanel:
 		mode, ok := tokens.Get()
 		if !ok {
@@ -1101,6 +1335,16 @@ func (dbg *Debugger) processTokens(tokens *commandline.Tokens) (bool, error) {
 			dbg.traps.list()
 		case "WATCHES":
 			dbg.watches.list()
+		case "HOTSPOTS":
+			hotspots := dbg.vcs.Mem.Cart.Hotspots()
+			if len(hotspots) == 0 {
+				dbg.printLine(terminal.StyleFeedback, "no hotspots")
+			} else {
+				dbg.printLine(terminal.StyleFeedback, "hotspots:")
+				for _, h := range hotspots {
+					dbg.printLine(terminal.StyleFeedback, "  %s", h)
+				}
+			}
 		case "ALL":
 			dbg.breakpoints.list()
 			dbg.traps.list()
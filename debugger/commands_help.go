@@ -23,7 +23,9 @@ var helps = map[string]string{
 	cmdHelp: "Lists commands and provides help for individual commands.",
 
 	cmdReset: `Reset the emulated machine (including television) to its initial state. The
-debugger itself (breakpoints, etc.) will not be reset.`,
+debugger itself (breakpoints, etc.) will not be reset.
+
+A CPU that has been killed by a KIL instruction will run again after a reset.`,
 
 	cmdQuit: `Quit the debugger. If script is being recorded then QUIT will instead halt
 recording of the script and not cause the debugger to exit.`,
@@ -82,7 +84,29 @@ cartridge will be loaded from disk.`,
 
 	cmdCartridge: `Display information about the current cartridge. Without arguments the command
 will show where the game was loaded from, the cartridge type and bank number. The BANK
-argument meanwhile can be used to switch banks (if possible).`,
+argument meanwhile can be used to switch banks (if possible). The FINGERPRINT argument
+lists every cartridge format considered when the cartridge was attached, along with the
+confidence score and the evidence found in the cartridge data.`,
+
+	cmdBankLog: `Display the most recent cartridge bank switches, oldest first. Each entry shows
+the frame, scanline and horizontal position of the switch, the address of the
+instruction that caused it, the range of cartridge addresses affected, the old
+and new banks and the address that triggered the switch. A number can be given
+to limit the output to that many entries. CLEAR empties the log.`,
+
+	cmdBusTrace: `Trace every cycle of activity on the address bus. The trace is off by default
+and is started with ON and stopped with OFF. Without arguments the command reports whether
+the trace is on. A number lists that many of the most recent entries, oldest first. CLEAR
+empties the trace.
+
+Each entry shows the television state, the direction of the access, the address as it
+appeared on the bus, the data and the memory area. Cartridge accesses also show the
+bank. Phantom accesses by the CPU and writes that the cartridge can listen to are
+flagged. Cycles on which the bus was not accessed are shown as idle.
+
+The trace can be streamed to a file with the FILE argument, which also starts the trace.
+FILE OFF closes the file. The file is in a compact binary format and can be printed
+with the BUSTRACE mode of gopher2600.`,
 
 	cmdPatch: "Apply a patch file to the loaded cartridge",
 
@@ -358,7 +382,12 @@ process key video events were triggered.`,
 
 	// user input
 	cmdController: `Change the current controller type for the specified player. Specifying a
-controller turns off AUTO changing. Turn AUTO changing back on with the AUTO flag.`,
+controller turns off AUTO changing. Turn AUTO changing back on with the AUTO flag.
+
+A DRIVING controller, TRAKBALL, AMIGAMOUSE, STMOUSE, BOOSTERGRIP, GENESIS pad,
+LIGHTGUN, SAVEKEY or ATARIVOX can not be detected automatically so selecting one
+always turns off AUTO changing. The contents of the EEPROM in either device are saved to
+disk and can be inspected with the SAVEKEY command.`,
 
 	cmdPanel: "Inspect and set front panel settings. Switches can be set or toggled..",
 
@@ -375,6 +404,44 @@ video cycles.
 
 Specify the player with the 0 or 1 arguments.`,
 
+	cmdSaveKey: `Display the state of the attached SaveKey and 256 bytes of its EEPROM. The
+EEPROM address to start from can be specified, otherwise the display starts from
+address zero.
+
+A SaveKey or AtariVox must have been attached to one of the player ports with the
+CONTROLLER command.`,
+
+	cmdAtariVox: `Display the most recent bytes sent to the SpeakJet in an attached AtariVox,
+oldest first. Each entry shows the television state and the CPU cycle on which the
+byte was received. The number of entries to display can be specified.
+
+The log can be copied to a file as it is written with the LOG argument. LOG OFF
+closes the file. CLEAR removes all entries from the log but does not affect the
+log file.`,
+
+	cmdMacro: `Define, list and run input macros for Player 0, Player 1 or the PANEL. With no
+further arguments, the macros for the port are listed.
+
+A macro is defined with DEFINE, using the same format as a line in a macro file:
+
+	<name>, <trigger>, <once|repeat>, <step>, <step>, ...
+
+Each step is an input event with optional data and an optional number of frames
+to wait before the next step. For example:
+
+	MACRO 0 DEFINE jump, NoEvent, once, Right:true/30, Fire:true/2, Fire:false, Right:false
+
+Macros with a trigger event start when the trigger is pressed and stop when it
+is released. Macros can also be started and stopped with START and STOP. STOP
+without a name stops all macros for the port. LOAD reads the macro definitions
+in a file and DROP removes a macro.
+
+TURBO defines a macro that presses and releases the fire button every specified
+number of frames while the fire button is held. TURBO OFF removes it.
+
+Macro events are sent to the controller as the emulation runs and are included
+in any recording.`,
+
 	// halt conditions
 	cmdBreak: `Halt execution of the emulation when a specific value is "loaded" into a named
 target. A target is a part of the emulation hardware that can be interegated
@@ -413,6 +480,22 @@ execution breaks on SL 10 & X 255. After resumption, the break will not apply
 until X changes from 255 to something else and then back again, or SL is hit on
 the next frame and X again (or still) has a value of 255.i
 
+The HOTSPOT keyword will halt execution whenever one of the cartridge's hotspot
+addresses is accessed. The hotspots for the current cartridge can be listed
+with LIST HOTSPOTS.
+
+	BREAK HOTSPOT
+
+The BANKSWITCH keyword will halt execution whenever the cartridge switches
+banks. A bank number can be given to halt only when that bank is switched in.
+The bank number can be followed by a range of addresses with the FROM keyword.
+In that case, the instruction causing the switch must be in that range.
+
+	BREAK BANKSWITCH
+	BREAK BANKSWITCH 2 FROM 0xf000 0xf0ff
+
+Recent bank switches can be reviewed with the BANKLOG command.
+
 Existing breakpoints can be reviewed with the LIST command and deleted with the
 DROP or CLEAR commands`,
 
@@ -465,7 +548,7 @@ address 0x80.
 Existing watches can be reviewed with the LIST command and deleted with the
 DROP or CLEAR commands`,
 
-	cmdList:  "List currently defined BREAKS, TRAPS and WATCHES.",
+	cmdList:  "List currently defined BREAKS, TRAPS and WATCHES. HOTSPOTS lists the cartridge's hotspot addresses.",
 	cmdDrop:  "Drop a specific BREAK, TRAP or WATCH condition, using the number of the condition reported by LIST.",
 	cmdClear: "Clear all BREAKS, TRAPS and WATCHES.",
 }
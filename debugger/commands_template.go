@@ -32,6 +32,8 @@ const (
 
 	cmdInsert      = "INSERT"
 	cmdCartridge   = "CARTRIDGE"
+	cmdBankLog     = "BANKLOG"
+	cmdBusTrace    = "BUSTRACE"
 	cmdPatch       = "PATCH"
 	cmdDisassembly = "DISASSEMBLY"
 	cmdGrep        = "GREP"
@@ -59,6 +61,9 @@ const (
 	cmdPanel      = "PANEL"
 	cmdJoystick   = "JOYSTICK"
 	cmdKeypad     = "KEYPAD"
+	cmdSaveKey    = "SAVEKEY"
+	cmdAtariVox   = "ATARIVOX"
+	cmdMacro      = "MACRO"
 
 	// halt conditions
 	cmdBreak = "BREAK"
@@ -82,7 +87,9 @@ var commandTemplate = []string{
 	cmdScript + " [RECORD %<new file>F|END|%<file>F]",
 
 	cmdInsert + " %<cartridge>F",
-	cmdCartridge + " (BANK %<number>N)",
+	cmdCartridge + " (BANK %<number>N|FINGERPRINT)",
+	cmdBankLog + " (CLEAR|%<number of entries>N)",
+	cmdBusTrace + " (ON|OFF|CLEAR|FILE [OFF|%<file>F]|%<number of entries>N)",
 	cmdPatch + " %<patch file>S",
 	cmdDisassembly + " (BYTECODE) (%<bank num>N)",
 	cmdGrep + " (MNEMONIC|OPERAND) %<search>S",
@@ -106,16 +113,19 @@ var commandTemplate = []string{
 	cmdDisplay + " (ON|OFF|SCALE [%<scale value>P]|MASKING (ON|OFF)|ALT (ON|OFF)|OVERLAY (ON|OFF))", // see notes
 
 	// user input
-	cmdController + " [0|1] (AUTO|NOAUTO|JOYSTICK|PADDLE|KEYPAD)",
+	cmdController + " [0|1] (AUTO|NOAUTO|JOYSTICK|PADDLE|KEYPAD|DRIVING|TRAKBALL|AMIGAMOUSE|STMOUSE|BOOSTERGRIP|GENESIS|LIGHTGUN|SAVEKEY|ATARIVOX)",
 	cmdPanel + " (SET [P0PRO|P1PRO|P0AM|P1AM|COL|BW]|TOGGLE [P0|P1|COL])",
 	cmdJoystick + " [0|1] [LEFT|RIGHT|UP|DOWN|FIRE|NOLEFT|NORIGHT|NOUP|NODOWN|NOFIRE]",
 	cmdKeypad + " [0|1] [none|0|1|2|3|4|5|6|7|8|9|*|#]",
+	cmdSaveKey + " (%<address>N)",
+	cmdAtariVox + " (CLEAR|LOG [OFF|%<file>F]|%<number of entries>N)",
+	cmdMacro + " [0|1|PANEL] (LIST|LOAD %<file>F|DEFINE %<definition>S {%<definition>S}|DROP %<name>S|START %<name>S|STOP (%<name>S)|TURBO [OFF|%<frames>N])",
 
 	// halt conditions
-	cmdBreak + " [%<target>S %<value>N|%<pc value>S] {& %<target>S %<value>S|& %<value>S}",
+	cmdBreak + " [HOTSPOT|BANKSWITCH (%<bank>N (FROM %<from>S %<to>S))|%<target>S %<value>N|%<pc value>S] {& %<target>S %<value>S|& %<value>S}",
 	cmdTrap + " [%<target>S] {%<targets>S}",
 	cmdWatch + " (READ|WRITE) [%<address>S] (%<value>S)",
-	cmdList + " [BREAKS|TRAPS|WATCHES|ALL]",
+	cmdList + " [BREAKS|TRAPS|WATCHES|HOTSPOTS|ALL]",
 	cmdDrop + " [BREAK|TRAP|WATCH] %<number in list>N",
 	cmdClear + " [BREAKS|TRAPS|WATCHES|ALL]",
 }
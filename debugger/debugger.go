@@ -24,6 +24,7 @@ import (
 	"os/signal"
 	"strings"
 
+	"github.com/jetsetilly/gopher2600/bindings"
 	"github.com/jetsetilly/gopher2600/cartridgeloader"
 	"github.com/jetsetilly/gopher2600/debugger/script"
 	"github.com/jetsetilly/gopher2600/debugger/terminal"
@@ -74,6 +75,15 @@ type Debugger struct {
 	traps       *traps
 	watches     *watches
 
+	// record of recent cartridge bank switches
+	bankLog *bankLog
+
+	// record of recent commands sent to an AtariVox
+	speechLog *speechLog
+
+	// record of recent activity on the address bus
+	busTrace *busTrace
+
 	// single-fire step traps. these are used for the STEP command, allowing
 	// things like "STEP FRAME".
 	stepTraps *traps
@@ -148,6 +158,12 @@ func NewDebugger(tv television.Television, scr gui.GUI, term terminal.Terminal)
 		return nil, errors.New(errors.DebuggerError, err)
 	}
 
+	// the debugger uses the playmode input bindings
+	err = bindings.Load()
+	if err != nil {
+		return nil, errors.New(errors.DebuggerError, err)
+	}
+
 	// create instance of disassembly -- the same base structure is used
 	// for disassemblies subseuquent to the first one.
 	dbg.disasm, err = disassembly.FromMemory(dbg.vcs.Mem.Cart, nil)
@@ -185,6 +201,19 @@ func NewDebugger(tv television.Television, scr gui.GUI, term terminal.Terminal)
 	dbg.watches = newWatches(dbg)
 	dbg.stepTraps = newTraps(dbg)
 
+	// listen for bank switches. the bank log must be created after the
+	// breakpoints because it checks for bank switch breaks
+	dbg.bankLog = newBankLog(dbg)
+	dbg.vcs.Mem.Cart.SetBankSwitchListener(dbg.bankLog)
+
+	// listen for AtariVox speech in either controller port
+	dbg.speechLog = newSpeechLog(dbg)
+	dbg.vcs.HandController0.SetSpeakJetListener(dbg.speechLog)
+	dbg.vcs.HandController1.SetSpeakJetListener(dbg.speechLog)
+
+	// the bus trace is only attached to VCS memory on request
+	dbg.busTrace = newBusTrace(dbg)
+
 	// make synchronisation channels
 	dbg.events = &terminal.ReadEvents{
 		GuiEvents:       make(chan gui.Event, 2),
@@ -256,6 +285,9 @@ func (dbg *Debugger) Start(initScript string, cartload cartridgeloader.Loader) e
 		}
 	}()
 
+	// make sure the bus trace file is complete
+	defer dbg.busTrace.stopFile()
+
 	// prepare and run main input loop. inputLoop will not return until
 	// debugging session is to be terminated
 	err = dbg.inputLoop(dbg.term, false)
@@ -275,27 +307,46 @@ func (dbg *Debugger) Start(initScript string, cartload cartridgeloader.Loader) e
 // this is the glue that hold the cartridge and disassembly packages together.
 // especially important is the repointing of symtable in the instance of dbgmem
 func (dbg *Debugger) loadCartridge(cartload cartridgeloader.Loader) error {
-	err := setup.AttachCartridge(dbg.vcs, cartload)
-	if err != nil && !errors.Has(err, errors.CartridgeEjected) {
-		return err
-	}
-
 	symtable, err := symbols.ReadSymbolsFile(cartload.Filename)
 	if err != nil {
 		dbg.printLine(terminal.StyleError, "%s", err)
 		// continuing because symtable is always valid even if err non-nil
 	}
 
-	dbg.disasm, err = disassembly.FromMemory(dbg.vcs.Mem.Cart, symtable)
+	// the disassembly used by the setup package to detect the hand
+	// controllers is used by the debugger too so that the cartridge is only
+	// disassembled once
+	dsm, err := setup.AttachCartridgeWithDisassembly(dbg.vcs, cartload, symtable)
 	if err != nil {
-		return err
+		switch {
+		case errors.Has(err, errors.CartridgeEjected):
+		case errors.Is(err, errors.SetupDetectionError):
+			dbg.printLine(terminal.StyleError, "%s", err)
+		default:
+			return err
+		}
 	}
 
+	// there is no disassembly from the setup package if the cartridge is
+	// ejected, couldn't be disassembled or has been patched
+	if dsm == nil {
+		dsm, err = disassembly.FromMemory(dbg.vcs.Mem.Cart, symtable)
+		if err != nil {
+			return err
+		}
+	}
+	dbg.disasm = dsm
+
 	dbg.scr.SetFeature(gui.ReqAddDisasm, dbg.disasm)
 
 	// repoint debug memory's symbol table
 	dbg.dbgmem.symtable = dbg.disasm.Symtable
 
+	// bank switches from the previous cartridge are of no interest
+	dbg.bankLog.clear()
+	dbg.speechLog.clear()
+	dbg.busTrace.clear()
+
 	return nil
 }
 
@@ -216,6 +216,8 @@ func (trm *mockTerm) testSequence() {
 	trm.testBreakpoints()
 	trm.testTraps()
 	trm.testWatches()
+	trm.testMacros()
+	trm.testBusTrace()
 }
 
 func TestDebugger_withNonExistantInitScript(t *testing.T) {
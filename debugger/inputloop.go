@@ -250,6 +250,10 @@ func (dbg *Debugger) inputLoop(inputter terminal.Input, videoCycle bool) error {
 			// use this value to prepare the LastDisasmEntry.
 			dbg.lastBank = dbg.vcs.Mem.Cart.GetBank(dbg.vcs.CPU.PC.Address())
 
+			// note whether the CPU has already been killed so that we only
+			// halt the first time it happens
+			killed := dbg.vcs.CPU.Killed
+
 			switch dbg.quantum {
 			case QuantumCPU:
 				err = dbg.vcs.Step(vcsStep)
@@ -286,6 +290,17 @@ func (dbg *Debugger) inputLoop(inputter terminal.Input, videoCycle bool) error {
 						return errors.New(errors.DebuggerError, err)
 					}
 				}
+
+				// the CPU has just been killed. the emulation can continue
+				// (the TIA is still running) but the user will want to know
+				// about it
+				if !killed && dbg.vcs.CPU.Killed {
+					dbg.lastStepError = true
+					dbg.printLine(terminal.StyleError, "%s", errors.New(errors.CPUKilled,
+						dbg.vcs.CPU.LastResult.Defn.OpCode,
+						dbg.vcs.CPU.LastResult.Address,
+						dbg.lastBank))
+				}
 			}
 
 			if dbg.commandOnStep != nil {
@@ -0,0 +1,55 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package debugger_test
+
+func (trm *mockTerm) testMacros() {
+	trm.sndInput("MACRO 0")
+	trm.cmpOutput("no macros defined")
+
+	trm.sndInput("MACRO 0 DEFINE jump, NoEvent, once, Right:true/30, Fire:true/2, Fire:false, Right:false")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0 TURBO 4")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0 LIST")
+	trm.cmpOutput("turbo, Fire, repeat, Fire:true/4, Fire:false/4")
+
+	trm.sndInput("MACRO 0 START jump")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0 TURBO OFF")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0")
+	trm.cmpOutput("jump, NoEvent, once, Right:true/30, Fire:true/2, Fire:false, Right:false (running)")
+
+	trm.sndInput("MACRO 0 STOP")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0 DROP jump")
+	trm.cmpOutput("")
+
+	trm.sndInput("MACRO 0 DROP jump")
+	trm.cmpOutput("macro error: no macro named jump")
+
+	trm.sndInput("MACRO 0 DEFINE turbo, Fire, repeat, Fire:true, Fire:false")
+	trm.cmpOutput("turbo repeats but never waits")
+}
@@ -0,0 +1,142 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// the speech log records every byte sent to the SpeakJet in an AtariVox. like
+// the bank log it is a ring buffer so only the most recent bytes are kept. the
+// log can also be copied to a file as it is being written.
+
+package debugger
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jetsetilly/gopher2600/debugger/terminal"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// the number of SpeakJet commands kept in the speech log
+const speechLogLength = 256
+
+// speechLogEntry is a SpeakJet command along with the state of the television
+// at the moment the command was received
+type speechLogEntry struct {
+	input.SpeakJetCommand
+
+	frame    int
+	scanline int
+	horizpos int
+}
+
+func (e speechLogEntry) String() string {
+	return fmt.Sprintf("fr %-4d sl %-3d hp %-3d %s", e.frame, e.scanline, e.horizpos, e.SpeakJetCommand)
+}
+
+type speechLog struct {
+	dbg *Debugger
+
+	entries [speechLogLength]speechLogEntry
+
+	// the index of the next entry to be written and the number of valid
+	// entries in the log
+	next  int
+	count int
+
+	// entries are also written to the file if it is not nil
+	file *os.File
+}
+
+// newSpeechLog is the preferred method of initialisation for the speechLog type
+func newSpeechLog(dbg *Debugger) *speechLog {
+	return &speechLog{dbg: dbg}
+}
+
+// SpeakJet implements the input.SpeakJetListener interface
+func (sl *speechLog) SpeakJet(cmd input.SpeakJetCommand) {
+	e := speechLogEntry{
+		SpeakJetCommand: cmd,
+	}
+
+	// errors from the television are not important enough to interrupt the
+	// emulation
+	e.frame, _ = sl.dbg.vcs.TV.GetState(television.ReqFramenum)
+	e.scanline, _ = sl.dbg.vcs.TV.GetState(television.ReqScanline)
+	e.horizpos, _ = sl.dbg.vcs.TV.GetState(television.ReqHorizPos)
+
+	sl.entries[sl.next] = e
+	sl.next = (sl.next + 1) % speechLogLength
+	if sl.count < speechLogLength {
+		sl.count++
+	}
+
+	if sl.file != nil {
+		if _, err := fmt.Fprintln(sl.file, e); err != nil {
+			sl.dbg.printLine(terminal.StyleError, "speech log: %v", err)
+			sl.stopFile()
+		}
+	}
+}
+
+// clear all entries from the log. the log file is unaffected
+func (sl *speechLog) clear() {
+	sl.next = 0
+	sl.count = 0
+}
+
+// startFile begins copying new entries to the named file. any existing file
+// is closed first
+func (sl *speechLog) startFile(filename string) error {
+	sl.stopFile()
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	sl.file = f
+
+	return nil
+}
+
+// stopFile stops copying entries to the log file
+func (sl *speechLog) stopFile() {
+	if sl.file == nil {
+		return
+	}
+	_ = sl.file.Close()
+	sl.file = nil
+}
+
+// list the most recent entries in the log, oldest first. a value of less than
+// one lists every entry
+func (sl *speechLog) list(n int) {
+	if sl.count == 0 {
+		sl.dbg.printLine(terminal.StyleFeedback, "speech log is empty")
+		return
+	}
+
+	if n < 1 || n > sl.count {
+		n = sl.count
+	}
+
+	for i := sl.count - n; i < sl.count; i++ {
+		idx := (sl.next - sl.count + i + speechLogLength) % speechLogLength
+		sl.dbg.printLine(terminal.StyleInstrument, "%s", sl.entries[idx])
+	}
+}
@@ -0,0 +1,198 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package disassembly
+
+import (
+	"github.com/jetsetilly/gopher2600/hardware/cpu/instructions"
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+// the TIA and RIOT registers of interest to ControllerTypes(). these are the
+// addresses returned by memorymap.MapAddress()
+const (
+	inpt0  = 0x08
+	inpt1  = 0x09
+	inpt2  = 0x0a
+	inpt3  = 0x0b
+	swcha  = 0x0280
+	swacnt = 0x0281
+)
+
+// an immediate value loaded into one of the CPU registers
+type immediateValue struct {
+	value uint8
+	known bool
+}
+
+// the evidence found for each controller port by ControllerTypes()
+type controllerEvidence struct {
+	// the paddle lines for the port have been read
+	paddleReads int
+
+	// all the pins for the port have been set to output with SWACNT
+	keypadDDR bool
+
+	// the rows of the keypad are strobed one at a time by writing to SWCHA.
+	// each bit is set for a different row value seen.
+	keypadRows uint8
+}
+
+// ControllerTypes analyses the disassembly for patterns of access that
+// suggest the type of hand controller expected in each of the two ports. Ports
+// for which there is no evidence for anything else are reported as needing a
+// joystick.
+//
+// The analysis only considers blessed entries and is necessarily
+// conservative:
+//
+//	paddle: the INPT0/INPT1 (port 0) or INPT2/INPT3 (port 1) registers are
+//	read
+//
+//	keypad: all four pins of the port are set to output with SWACNT and the
+//	rows are strobed one at a time by writing to SWCHA. keypad evidence takes
+//	precedence over paddle evidence because the keypad columns are also read
+//	through the paddle registers
+//
+// Values written to SWACNT and SWCHA are only known when the register being
+// stored was loaded with an immediate value a short time beforehand, with no
+// intervening change of program flow.
+func (dsm *Disassembly) ControllerTypes() [2]input.ControllerType {
+	var evidence [2]controllerEvidence
+
+	for bank := 0; bank < len(dsm.reference); bank++ {
+		itr, _, err := dsm.NewIteration(EntryLevelBlessed, bank)
+		if err != nil {
+			continue // for loop
+		}
+
+		// the most recent immediate value loaded into the A, X and Y
+		// registers
+		var regs [3]immediateValue
+
+		for e := itr.Start(); e != nil; e = itr.Next() {
+			defn := e.Result.Defn
+			if defn == nil || e.Result.ByteCount != defn.Bytes {
+				regs = [3]immediateValue{}
+				continue // for loop
+			}
+
+			reg := -1
+			switch defn.Mnemonic {
+			case "LDA", "STA":
+				reg = 0
+			case "LDX", "STX":
+				reg = 1
+			case "LDY", "STY":
+				reg = 2
+			}
+
+			switch defn.AddressingMode {
+			case instructions.ZeroPage, instructions.IndexedZeroPageX, instructions.IndexedZeroPageY,
+				instructions.Absolute, instructions.AbsoluteIndexedX, instructions.AbsoluteIndexedY:
+
+				// indexed addressing is assumed to be offsetting from the
+				// register of interest. this is common for paddle routines,
+				// which read INPT0,X for example
+				switch defn.Effect {
+				case instructions.Read:
+					address, area := memorymap.MapAddress(e.Result.InstructionData, true)
+					if area == memorymap.TIA {
+						switch address {
+						case inpt0, inpt1:
+							evidence[0].paddleReads++
+						case inpt2, inpt3:
+							evidence[1].paddleReads++
+						}
+					}
+
+				case instructions.Write:
+					address, area := memorymap.MapAddress(e.Result.InstructionData, false)
+					if area == memorymap.RIOT && reg != -1 && regs[reg].known {
+						v := regs[reg].value
+						switch address {
+						case swacnt:
+							if v&0xf0 == 0xf0 {
+								evidence[0].keypadDDR = true
+							}
+							if v&0x0f == 0x0f {
+								evidence[1].keypadDDR = true
+							}
+						case swcha:
+							evidence[0].keypadRows |= keypadRow(v >> 4)
+							evidence[1].keypadRows |= keypadRow(v & 0x0f)
+						}
+					}
+				}
+			}
+
+			// keep track of immediate values. anything that might change a
+			// register or the flow of the program causes the values to be
+			// forgotten
+			if defn.AddressingMode == instructions.Immediate && reg != -1 {
+				regs[reg] = immediateValue{value: uint8(e.Result.InstructionData), known: true}
+			} else if !preservesRegisters(defn.Mnemonic) {
+				regs = [3]immediateValue{}
+			}
+		}
+	}
+
+	var types [2]input.ControllerType
+	for i, ev := range evidence {
+		switch {
+		case ev.keypadDDR && ev.keypadRows != 0:
+			types[i] = input.KeypadType
+		case ev.paddleReads > 0:
+			types[i] = input.PaddleType
+		default:
+			types[i] = input.JoystickType
+		}
+	}
+
+	return types
+}
+
+// preservesRegisters returns true if the instruction does not change the
+// contents of the A, X or Y registers and does not change the flow of the
+// program
+func preservesRegisters(mnemonic string) bool {
+	switch mnemonic {
+	case "STA", "STX", "STY", "CMP", "CPX", "CPY", "BIT", "NOP", "PHA", "PHP",
+		"CLC", "SEC", "CLI", "SEI", "CLD", "SED", "CLV":
+		return true
+	}
+	return false
+}
+
+// keypadRow returns a value with a single bit set if the nibble has exactly one
+// bit cleared. ie. the value selects exactly one row of a keypad.
+func keypadRow(n uint8) uint8 {
+	switch n & 0x0f {
+	case 0x0e:
+		return 0x01
+	case 0x0d:
+		return 0x02
+	case 0x0b:
+		return 0x04
+	case 0x07:
+		return 0x08
+	}
+	return 0x00
+}
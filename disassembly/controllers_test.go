@@ -0,0 +1,122 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package disassembly_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
+	"github.com/jetsetilly/gopher2600/disassembly"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+// controllerTypes creates a 4k cartridge with the program placed at the reset
+// address and returns the result of ControllerTypes()
+func controllerTypes(t *testing.T, program []byte) [2]input.ControllerType {
+	t.Helper()
+
+	data := make([]byte, 4096)
+	copy(data, program)
+
+	// reset address of 0xf000
+	data[0xffc] = 0x00
+	data[0xffd] = 0xf0
+
+	dir, err := ioutil.TempDir("", "disassembly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "test.bin")
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dsm, err := disassembly.FromCartridge(cartridgeloader.Loader{Filename: filename})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dsm.ControllerTypes()
+}
+
+func TestControllerTypes(t *testing.T) {
+	var types [2]input.ControllerType
+
+	// no input at all
+	types = controllerTypes(t, []byte{
+		0x4c, 0x00, 0xf0, // JMP $f000
+	})
+	if types != [2]input.ControllerType{input.JoystickType, input.JoystickType} {
+		t.Errorf("unexpected controller types (%v)", types)
+	}
+
+	// paddles in port 0
+	types = controllerTypes(t, []byte{
+		0xa5, 0x0c, // LDA INPT4
+		0xa5, 0x09, // LDA INPT1
+		0x4c, 0x00, 0xf0, // JMP $f000
+	})
+	if types != [2]input.ControllerType{input.PaddleType, input.JoystickType} {
+		t.Errorf("unexpected controller types (%v)", types)
+	}
+
+	// paddles in port 1 read with indexed addressing
+	types = controllerTypes(t, []byte{
+		0xa2, 0x01, // LDX #$01
+		0xb5, 0x0a, // LDA INPT2,X
+		0x4c, 0x00, 0xf0, // JMP $f000
+	})
+	if types != [2]input.ControllerType{input.JoystickType, input.PaddleType} {
+		t.Errorf("unexpected controller types (%v)", types)
+	}
+
+	// keypad in port 1. keypad columns are read through the paddle registers
+	// but the keypad evidence takes precedence
+	types = controllerTypes(t, []byte{
+		0xa9, 0x0f, // LDA #$0f
+		0x8d, 0x81, 0x02, // STA SWACNT
+		0xa0, 0xfe, // LDY #$fe
+		0x8c, 0x80, 0x02, // STY SWCHA
+		0xa5, 0x0a, // LDA INPT2
+		0x4c, 0x00, 0xf0, // JMP $f000
+	})
+	if types != [2]input.ControllerType{input.JoystickType, input.KeypadType} {
+		t.Errorf("unexpected controller types (%v)", types)
+	}
+
+	// the value written to SWACNT is not known because of the intervening
+	// instruction
+	types = controllerTypes(t, []byte{
+		0xa9, 0xf0, // LDA #$f0
+		0x0a,             // ASL A
+		0x8d, 0x81, 0x02, // STA SWACNT
+		0xa9, 0xef, // LDA #$ef
+		0x8d, 0x80, 0x02, // STA SWCHA
+		0x4c, 0x00, 0xf0, // JMP $f000
+	})
+	if types != [2]input.ControllerType{input.JoystickType, input.JoystickType} {
+		t.Errorf("unexpected controller types (%v)", types)
+	}
+}
@@ -122,6 +122,12 @@ func FromMemory(cart *cartridge.Cartridge, symtable *symbols.Table) (*Disassembl
 	state := dsm.cart.SaveState()
 	defer dsm.cart.RestoreState(state)
 
+	// bank switches caused by the disassembly process are of no interest to
+	// anything listening for bank switches during emulation
+	listener := dsm.cart.BankSwitchListener()
+	dsm.cart.SetBankSwitchListener(nil)
+	defer dsm.cart.SetBankSwitchListener(listener)
+
 	// put cart into its initial state
 	dsm.cart.Initialise()
 
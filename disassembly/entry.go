@@ -26,6 +26,7 @@ import (
 	"github.com/jetsetilly/gopher2600/hardware/cpu/execution"
 	"github.com/jetsetilly/gopher2600/hardware/cpu/instructions"
 	"github.com/jetsetilly/gopher2600/hardware/cpu/registers"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
 	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
 )
 
@@ -123,6 +124,11 @@ type Entry struct {
 	// the computation
 	ActualCycles string
 	ActualNotes  string
+
+	// the effect of the cartridge hotspot accessed by the instruction. empty
+	// if the instruction does not access a hotspot or if the address cannot
+	// be known at disassembly time
+	Hotspot string
 }
 
 // FormatResult It is the preferred method of initialising for the Entry type.
@@ -274,6 +280,35 @@ func (dsm *Disassembly) FormatResult(bank int, result execution.Result, level En
 		}
 	}
 
+	// note whether the instruction accesses a cartridge hotspot. we can only
+	// do this for addressing modes where the address is known without
+	// executing the instruction
+	if operandDecoded {
+		switch result.Defn.AddressingMode {
+		case instructions.Absolute:
+			fallthrough
+		case instructions.ZeroPage:
+			var h cartridge.Hotspot
+			var ok bool
+
+			switch result.Defn.Effect {
+			case instructions.Read:
+				h, ok = dsm.cart.HotspotAt(operand, false)
+			case instructions.Write:
+				h, ok = dsm.cart.HotspotAt(operand, true)
+			case instructions.RMW:
+				h, ok = dsm.cart.HotspotAt(operand, false)
+				if !ok {
+					h, ok = dsm.cart.HotspotAt(operand, true)
+				}
+			}
+
+			if ok {
+				d.Hotspot = h.Effect
+			}
+		}
+	}
+
 	// decorate operand with addressing mode indicators
 	switch result.Defn.AddressingMode {
 	case instructions.Implied:
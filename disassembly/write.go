@@ -94,5 +94,9 @@ func (dsm *Disassembly) WriteEntry(output io.Writer, attr WriteAttr, e *Entry) {
 	output.Write([]byte(" "))
 	output.Write([]byte(dsm.GetField(FldDefnNotes, e)))
 
+	if e.Hotspot != "" {
+		output.Write([]byte(fmt.Sprintf(" ; hotspot: %s", e.Hotspot)))
+	}
+
 	output.Write([]byte("\n"))
 }
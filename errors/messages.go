@@ -82,10 +82,22 @@ const (
 	SetupPanelError      = "panel setup: %v"
 	SetupPatchError      = "patch setup: %v"
 	SetupTelevisionError = "tv setup: %v"
+	SetupControllerError = "controller setup: %v"
+	SetupBindingsError   = "bindings setup: %v"
+	SetupDetectionError  = "controller detection: %v"
 
 	// patch
 	PatchError = "patch error: %v"
 
+	// bindings
+	BindingsError = "bindings error: %v"
+
+	// macros
+	MacroError = "macro error: %v"
+
+	// bus trace
+	BusTraceError = "bus trace error: %v"
+
 	// symbols
 	SymbolsFileError       = "symbols error: error processing symbols file: %v"
 	SymbolsFileUnavailable = "symbols error: no symbols file for %v"
@@ -99,6 +111,7 @@ const (
 
 	// cpu
 	UnimplementedInstruction       = "cpu error: unimplemented instruction (%#02x) at (%#04x)"
+	CPUKilled                      = "cpu error: killed by KIL instruction (%#02x) at (%#04x) in bank %d"
 	InvalidResult                  = "cpu error: %v"
 	ProgramCounterCycled           = "cpu error: program counter cycled back to 0x0000"
 	InvalidOperationMidInstruction = "cpu error: invalid operation mid-instruction (%v)"
@@ -115,6 +128,9 @@ const (
 	CartridgeEjected    = "cartridge error: no cartridge attached"
 	UnpatchableCartType = "cartridge error: cannot patch this cartridge type (%v)"
 
+	// arm7tdmi
+	ARMError = "arm7tdmi error: %v"
+
 	// input
 	UnknownInputEvent     = "input error: %v: unsupported event (%v)"
 	BadInputEventType     = "input error: bad value type for event %v (expecting %s)"
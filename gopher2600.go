@@ -26,6 +26,7 @@ import (
 	"os/signal"
 	"strings"
 
+	"github.com/jetsetilly/gopher2600/bustrace"
 	"github.com/jetsetilly/gopher2600/cartridgeloader"
 	"github.com/jetsetilly/gopher2600/debugger"
 	"github.com/jetsetilly/gopher2600/debugger/terminal"
@@ -38,12 +39,15 @@ import (
 	"github.com/jetsetilly/gopher2600/gui/sdlimgui"
 	"github.com/jetsetilly/gopher2600/gui/sdlimgui_play"
 	"github.com/jetsetilly/gopher2600/gui/sdlplay"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
 	"github.com/jetsetilly/gopher2600/modalflag"
 	"github.com/jetsetilly/gopher2600/paths"
 	"github.com/jetsetilly/gopher2600/performance"
 	"github.com/jetsetilly/gopher2600/playmode"
 	"github.com/jetsetilly/gopher2600/recorder"
 	"github.com/jetsetilly/gopher2600/regression"
+	"github.com/jetsetilly/gopher2600/setup"
 	"github.com/jetsetilly/gopher2600/television"
 	"github.com/jetsetilly/gopher2600/wavwriter"
 )
@@ -179,7 +183,7 @@ func launch(sync *mainSync) {
 	md := &modalflag.Modes{Output: os.Stdout}
 	md.NewArgs(os.Args[1:])
 	md.NewMode()
-	md.AddSubModes("RUN", "PLAY", "DEBUG", "DISASM", "PERFORMANCE", "REGRESS")
+	md.AddSubModes("RUN", "PLAY", "DEBUG", "DISASM", "INFO", "BUSTRACE", "PERFORMANCE", "REGRESS", "SETUP")
 
 	p, err := md.Parse()
 	switch p {
@@ -203,11 +207,20 @@ func launch(sync *mainSync) {
 	case "DISASM":
 		err = disasm(md)
 
+	case "INFO":
+		err = info(md)
+
+	case "BUSTRACE":
+		err = busTrace(md)
+
 	case "PERFORMANCE":
 		err = perform(md, sync)
 
 	case "REGRESS":
 		err = regress(md)
+
+	case "SETUP":
+		err = setupDB(md)
 	}
 
 	if err != nil {
@@ -485,6 +498,77 @@ func disasm(md *modalflag.Modes) error {
 	return nil
 }
 
+func info(md *modalflag.Modes) error {
+	md.NewMode()
+
+	cartFormat := md.AddString("cartformat", "AUTO", "force use of cartridge format")
+
+	p, err := md.Parse()
+	if p != modalflag.ParseContinue {
+		return err
+	}
+
+	switch len(md.RemainingArgs()) {
+	case 0:
+		return fmt.Errorf("2600 cartridge required for %s mode", md)
+	case 1:
+		cartload := cartridgeloader.Loader{
+			Filename: md.GetArg(0),
+			Format:   *cartFormat,
+		}
+
+		cart := cartridge.NewCartridge()
+		err := cart.Attach(cartload)
+
+		// output the fingerprint report even if the cartridge could not be
+		// attached. if the report is empty then fingerprint the data directly
+		rep := cart.FingerprintReport()
+		if rep.Size == 0 {
+			if data, lerr := cartload.Load(); lerr == nil {
+				rep = cartridge.FingerprintData(data)
+			}
+		}
+		_, _ = io.WriteString(md.Output, fmt.Sprintf("%s\n", rep))
+
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("too many arguments for %s mode", md)
+	}
+
+	return nil
+}
+
+func busTrace(md *modalflag.Modes) error {
+	md.NewMode()
+
+	p, err := md.Parse()
+	if p != modalflag.ParseContinue {
+		return err
+	}
+
+	switch len(md.RemainingArgs()) {
+	case 0:
+		return fmt.Errorf("bus trace file required for %s mode", md)
+	case 1:
+		f, err := os.Open(md.GetArg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		err = bustrace.Decode(f, md.Output)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("too many arguments for %s mode", md)
+	}
+
+	return nil
+}
+
 func perform(md *modalflag.Modes, sync *mainSync) error {
 	md.NewMode()
 
@@ -657,6 +741,103 @@ func regress(md *modalflag.Modes) error {
 	return nil
 }
 
+func setupDB(md *modalflag.Modes) error {
+	md.NewMode()
+	md.AddSubModes("LIST", "DELETE", "CONTROLLER")
+
+	p, err := md.Parse()
+	if p != modalflag.ParseContinue {
+		return err
+	}
+
+	switch md.Mode() {
+	case "LIST":
+		md.NewMode()
+
+		// no additional arguments
+
+		p, err := md.Parse()
+		if p != modalflag.ParseContinue {
+			return err
+		}
+
+		switch len(md.RemainingArgs()) {
+		case 0:
+			err := setup.List(md.Output)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("no additional arguments required for %s mode", md)
+		}
+
+	case "DELETE":
+		md.NewMode()
+
+		answerYes := md.AddBool("yes", false, "answer yes to confirmation")
+
+		p, err := md.Parse()
+		if p != modalflag.ParseContinue {
+			return err
+		}
+
+		switch len(md.RemainingArgs()) {
+		case 0:
+			return fmt.Errorf("database key required for %s mode", md)
+		case 1:
+
+			// use stdin for confirmation unless "yes" flag has been sent
+			var confirmation io.Reader
+			if *answerYes {
+				confirmation = &yesReader{}
+			} else {
+				confirmation = os.Stdin
+			}
+
+			err := setup.Delete(md.Output, confirmation, md.GetArg(0))
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("only one entry can be deleted at at time when using %s mode", md)
+		}
+
+	case "CONTROLLER":
+		md.NewMode()
+
+		cartFormat := md.AddString("cartformat", "AUTO", "force use of cartridge format")
+		port := md.AddInt("port", 0, "controller port: 0 or 1")
+		controller := md.AddString("type", "Joystick", fmt.Sprintf("controller type: %s", strings.Join(input.ControllerTypeList, ", ")))
+		auto := md.AddBool("auto", true, "allow automatic switching of controller type")
+		paddle := md.AddFloat64("paddle", 0.5, "initial paddle position: 0.0 to 1.0 [paddle type only]")
+		notes := md.AddString("notes", "", "annotation for the database")
+
+		p, err := md.Parse()
+		if p != modalflag.ParseContinue {
+			return err
+		}
+
+		switch len(md.RemainingArgs()) {
+		case 0:
+			return fmt.Errorf("2600 cartridge required for %s mode", md)
+		case 1:
+			cartload := cartridgeloader.Loader{
+				Filename: md.GetArg(0),
+				Format:   *cartFormat,
+			}
+
+			err := setup.AddController(md.Output, cartload, *port, *controller, *auto, float32(*paddle), *notes)
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("setup entries can only be added one at a time")
+		}
+	}
+
+	return nil
+}
+
 func regressAdd(md *modalflag.Modes) error {
 	md.NewMode()
 
@@ -60,6 +60,16 @@ type CPU struct {
 	// 3 of the 6507)
 	RdyFlg bool
 
+	// phantom is true while the CPU is making a phantom access of the bus.
+	// see PhantomAccess()
+	phantom bool
+
+	// Killed is true if the CPU has executed one of the KIL instructions. the
+	// CPU will not fetch any more instructions until it is reset. this is
+	// how the real 6507 behaves except that there, the only way of resetting
+	// the chip is to switch the power off and on again
+	Killed bool
+
 	// last result. the address field is guaranteed to be always valid except
 	// when the CPU has just been reset. we use this fact to help us decide
 	// whether the CPU has just been reset (see HasReset() function)
@@ -131,17 +141,38 @@ func (mc *CPU) Reset() error {
 	mc.isExecuting = false
 	mc.cycleCallback = nil
 	mc.RdyFlg = true
+	mc.Killed = false
 
 	// not touching NoFlowControl
 
 	return nil
 }
 
+// ClearKilled allows a killed CPU to fetch instructions again. Unlike Reset()
+// the registers are left as they are
+func (mc *CPU) ClearKilled() {
+	mc.Killed = false
+}
+
 // HasReset checks whether the CPU has recently been reset
 func (mc CPU) HasReset() bool {
 	return mc.LastResult.Address == 0 && mc.LastResult.Defn == nil
 }
 
+// stackAddress returns the address pointed to by the stack pointer. the
+// stack is always in page one
+func (mc *CPU) stackAddress() uint16 {
+	return 0x0100 | mc.SP.Address()
+}
+
+// pullStatus sets the status register from a value pulled from the stack.
+// the break bit in the value is ignored because PHP and BRK always set it
+func (mc *CPU) pullStatus(value uint8) {
+	brk := mc.Status.Break
+	mc.Status.FromValue(value)
+	mc.Status.Break = brk
+}
+
 // LoadPCIndirect loads the contents of indirectAddress into the PC
 func (mc *CPU) LoadPCIndirect(indirectAddress uint16) error {
 	// changing the program counter mid-instruction could have unwanted side
@@ -194,6 +225,25 @@ func (mc *CPU) read8Bit(address uint16) (uint8, error) {
 	return val, nil
 }
 
+// phantomRead reads 8 bits from the specified address but discards the value.
+// phantom reads happen on cycles where the 6507 has nothing useful to do with
+// the address bus. they are worth emulating because some cartridge mappers are
+// sensitive to them.
+//
+// * note that phantomRead calls endCycle as appropriate
+func (mc *CPU) phantomRead(address uint16) error {
+	mc.phantom = true
+	_, err := mc.read8Bit(address)
+	mc.phantom = false
+	return err
+}
+
+// PhantomAccess returns true if the current access of the bus is a phantom
+// read or write. Useful for bus tracing.
+func (mc *CPU) PhantomAccess() bool {
+	return mc.phantom
+}
+
 // read8BitZero reads 8 bits from the specified zero page address
 //
 // * note that read8BitZeroPage calls endCycle as appropriate
@@ -362,7 +412,7 @@ func (mc *CPU) branch(flag bool, address uint16) error {
 
 		// phantom read
 		// +1 cycle
-		_, err := mc.read8Bit(mc.PC.Address())
+		err := mc.phantomRead(mc.PC.Address())
 		if err != nil {
 			return err
 		}
@@ -382,7 +432,7 @@ func (mc *CPU) branch(flag bool, address uint16) error {
 		if mc.LastResult.PageFault {
 			// phantom read
 			// +1 cycle
-			_, err := mc.read8Bit(mc.PC.Address())
+			err := mc.phantomRead(mc.PC.Address())
 			if err != nil {
 				return err
 			}
@@ -402,6 +452,28 @@ func (mc *CPU) branch(flag bool, address uint16) error {
 	return nil
 }
 
+// storeHighAND writes a value that has been ANDed with the high byte of the
+// unindexed address plus one. this is the unusual behaviour of the sha, shx,
+// shy and tas instructions. if adding the index crossed a page boundary then
+// the high byte of the address is also replaced with the value.
+//
+// * note that storeHighAND calls endCycle as appropriate
+func (mc *CPU) storeHighAND(address uint16, index uint8, value uint8) error {
+	base := address - uint16(index)
+	value &= uint8(base>>8) + 1
+
+	if base&0xff00 != address&0xff00 {
+		address = uint16(value)<<8 | address&0x00ff
+	}
+
+	// +1 cycle
+	err := mc.write8Bit(address, value)
+	if err != nil {
+		return err
+	}
+	return mc.endCycle()
+}
+
 // endCycle is called at the end of the imaginary CPU cycle. for example,
 // reading a byte from memory takes one cycle and so the emulation will call
 // endCycle() at that point.
@@ -442,6 +514,15 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		return err
 	}
 
+	// a killed CPU does not fetch anything from the bus but the rest of the
+	// VCS continues to be clocked
+	if mc.Killed {
+		if cycleCallback != nil {
+			return cycleCallback()
+		}
+		return nil
+	}
+
 	// prepare new round of results
 	mc.LastResult.Reset()
 	mc.LastResult.Address = mc.PC.Address()
@@ -509,7 +590,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		} else {
 			// phantom read
 			// +1 cycle
-			_, err := mc.read8Bit(mc.PC.Address())
+			err := mc.phantomRead(mc.PC.Address())
 			if err != nil {
 				return err
 			}
@@ -705,7 +786,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 		// phantom read before adjusting the index
 		// +1 cycle
-		_, err = mc.read8Bit(uint16(indirectAddress))
+		err = mc.phantomRead(uint16(indirectAddress))
 		if err != nil {
 			return err
 		}
@@ -756,9 +837,11 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		}
 
 		if mc.LastResult.PageFault || defn.Effect == instructions.Write || defn.Effect == instructions.RMW {
-			// phantom read (always happends for Write and RMW)
+			// phantom read (always happends for Write and RMW). the address
+			// on the bus is the unfixed address: the MSB of the base address
+			// and the LSB of the indexed address
 			// +1 cycle
-			_, err := mc.read8Bit(address)
+			err := mc.phantomRead((indexedAddress & 0xff00) | (address & 0x00ff))
 			if err != nil {
 				return err
 			}
@@ -784,9 +867,11 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		// check for page fault
 		mc.LastResult.PageFault = defn.PageSensitive && (address&0xff00 == 0x0100)
 		if mc.LastResult.PageFault || defn.Effect == instructions.Write || defn.Effect == instructions.RMW {
-			// phantom read (always happends for Write and RMW)
+			// phantom read (always happends for Write and RMW). the address
+			// on the bus is the unfixed address: the MSB of the base address
+			// and the LSB of the indexed address
 			// +1 cycle
-			_, err := mc.read8Bit(address)
+			err := mc.phantomRead((indirectAddress & 0xff00) | (address & 0x00ff))
 			if err != nil {
 				return err
 			}
@@ -812,9 +897,11 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		// check for page fault
 		mc.LastResult.PageFault = defn.PageSensitive && (address&0xff00 == 0x0100)
 		if mc.LastResult.PageFault || defn.Effect == instructions.Write || defn.Effect == instructions.RMW {
-			// phantom read (always happends for Write and RMW)
+			// phantom read (always happends for Write and RMW). the address
+			// on the bus is the unfixed address: the MSB of the base address
+			// and the LSB of the indexed address
 			// +1 cycle
-			_, err := mc.read8Bit(address)
+			err := mc.phantomRead((indirectAddress & 0xff00) | (address & 0x00ff))
 			if err != nil {
 				return err
 			}
@@ -861,7 +948,9 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 			// phantom write
 			// +1 cycle
+			mc.phantom = true
 			err = mc.write8Bit(address, value)
+			mc.phantom = false
 
 			if err != nil {
 				return err
@@ -901,7 +990,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 	case "PHA":
 		// +1 cycle
-		err = mc.write8Bit(mc.SP.Address(), mc.A.Value())
+		err = mc.write8Bit(mc.stackAddress(), mc.A.Value())
 		if err != nil {
 			return err
 		}
@@ -920,7 +1009,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		}
 
 		// +1 cycle
-		value, err = mc.read8Bit(mc.SP.Address())
+		value, err = mc.read8Bit(mc.stackAddress())
 		if err != nil {
 			return err
 		}
@@ -930,7 +1019,9 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 	case "PHP":
 		// +1 cycle
-		err = mc.write8Bit(mc.SP.Address(), mc.Status.Value())
+		//
+		// the break bit is always set in the value pushed by PHP and BRK
+		err = mc.write8Bit(mc.stackAddress(), mc.Status.Value()|0x10)
 		if err != nil {
 			return err
 		}
@@ -948,11 +1039,11 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 			return err
 		}
 		// +1 cycle
-		value, err = mc.read8Bit(mc.SP.Address())
+		value, err = mc.read8Bit(mc.stackAddress())
 		if err != nil {
 			return err
 		}
-		mc.Status.FromValue(value)
+		mc.pullStatus(value)
 
 	case "TXA":
 		mc.A.Load(mc.X.Value())
@@ -1104,7 +1195,8 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 			mc.Status.Sign = mc.A.IsNegative()
 		}
 
-	case "SBC":
+	case "SBC", "sbc":
+		// the undocumented sbc is exactly the same as the documented SBC
 		if mc.Status.DecimalMode {
 			mc.Status.Carry,
 				mc.Status.Zero,
@@ -1264,7 +1356,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 		// push MSB of PC onto stack, and decrement SP
 		// +1 cycle
-		err = mc.write8Bit(mc.SP.Address(), uint8(mc.PC.Address()>>8))
+		err = mc.write8Bit(mc.stackAddress(), uint8(mc.PC.Address()>>8))
 		if err != nil {
 			return err
 		}
@@ -1276,7 +1368,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 		// push LSB of PC onto stack, and decrement SP
 		// +1 cycle
-		err = mc.write8Bit(mc.SP.Address(), uint8(mc.PC.Address()))
+		err = mc.write8Bit(mc.stackAddress(), uint8(mc.PC.Address()))
 		if err != nil {
 			return err
 		}
@@ -1316,7 +1408,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		}
 
 		// +2 cycles
-		rtsAddress, err := mc.read16Bit(mc.SP.Address())
+		rtsAddress, err := mc.read16Bit(mc.stackAddress())
 		if err != nil {
 			return err
 		}
@@ -1336,7 +1428,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 	case "BRK":
 		// push PC onto register (same effect as JSR)
-		err := mc.write8Bit(mc.SP.Address(), uint8(mc.PC.Address()>>8))
+		err := mc.write8Bit(mc.stackAddress(), uint8(mc.PC.Address()>>8))
 		if err != nil {
 			return err
 		}
@@ -1348,7 +1440,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 			return err
 		}
 
-		err = mc.write8Bit(mc.SP.Address(), uint8(mc.PC.Address()))
+		err = mc.write8Bit(mc.stackAddress(), uint8(mc.PC.Address()))
 		if err != nil {
 			return err
 		}
@@ -1361,7 +1453,7 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		}
 
 		// push status register (same effect as PHP)
-		err = mc.write8Bit(mc.SP.Address(), mc.Status.Value())
+		err = mc.write8Bit(mc.stackAddress(), mc.Status.Value()|0x10)
 		if err != nil {
 			return err
 		}
@@ -1398,18 +1490,18 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 			return err
 		}
 
-		value, err = mc.read8Bit(mc.SP.Address())
+		value, err = mc.read8Bit(mc.stackAddress())
 		if err != nil {
 			return err
 		}
-		mc.Status.FromValue(value)
+		mc.pullStatus(value)
 
 		// pull program counter (same effect as RTS)
 		if !mc.NoFlowControl {
 			mc.SP.Add(1, false)
 		}
 
-		rtiAddress, err := mc.read16Bit(mc.SP.Address())
+		rtiAddress, err := mc.read16Bit(mc.stackAddress())
 		if err != nil {
 			return err
 		}
@@ -1431,6 +1523,62 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		mc.Status.Sign = mc.A.IsNegative()
 		mc.X.Load(value)
 
+	case "lxa":
+		// the A register is ORed with a "magic" value before the AND. the
+		// magic value differs between chips. we use the same value as the
+		// stella emulator
+		mc.A.ORA(0xee)
+		mc.A.AND(value)
+		mc.Status.Zero = mc.A.IsZero()
+		mc.Status.Sign = mc.A.IsNegative()
+		mc.X.Load(mc.A.Value())
+
+	case "las":
+		mc.SP.AND(value)
+		mc.A.Load(mc.SP.Value())
+		mc.X.Load(mc.SP.Value())
+		mc.Status.Zero = mc.SP.IsZero()
+		mc.Status.Sign = mc.SP.IsNegative()
+
+	case "sha":
+		// +1 cycle
+		err = mc.storeHighAND(address, mc.Y.Value(), mc.A.Value()&mc.X.Value())
+		if err != nil {
+			return err
+		}
+
+	case "shx":
+		// +1 cycle
+		err = mc.storeHighAND(address, mc.Y.Value(), mc.X.Value())
+		if err != nil {
+			return err
+		}
+
+	case "shy":
+		// +1 cycle
+		err = mc.storeHighAND(address, mc.X.Value(), mc.Y.Value())
+		if err != nil {
+			return err
+		}
+
+	case "tas":
+		mc.SP.Load(mc.A.Value())
+		mc.SP.AND(mc.X.Value())
+
+		// +1 cycle
+		err = mc.storeHighAND(address, mc.Y.Value(), mc.SP.Value())
+		if err != nil {
+			return err
+		}
+
+	case "kil":
+		// the CPU locks up. the PC is left pointing to the kil instruction so
+		// that it is obvious where the CPU stopped
+		if !mc.NoFlowControl {
+			mc.PC.Load(mc.LastResult.Address)
+			mc.Killed = true
+		}
+
 	case "skw":
 		// does nothing (2 byte skip)
 		// differs to dop because the second byte is actually read
@@ -1490,9 +1638,33 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 
 	case "arr":
 		mc.A.AND(value)
-		mc.Status.Carry = mc.A.ROR(mc.Status.Carry)
-		mc.Status.Zero = mc.A.IsZero()
-		mc.Status.Sign = mc.A.IsNegative()
+		t := mc.A.Value()
+		mc.A.ROR(mc.Status.Carry)
+
+		if mc.Status.DecimalMode {
+			// decimal mode behaviour as described in "64doc" by John West and
+			// Marko Mäkelä. the sign flag is the old carry flag and the
+			// overflow flag is set if bit 6 changed
+			mc.Status.Sign = mc.Status.Carry
+			mc.Status.Zero = mc.A.IsZero()
+			mc.Status.Overflow = (t^mc.A.Value())&0x40 == 0x40
+
+			// BCD fixup of each nibble
+			if t&0x0f+t&0x01 > 0x05 {
+				mc.A.Load(mc.A.Value()&0xf0 | (mc.A.Value()+0x06)&0x0f)
+			}
+			mc.Status.Carry = t>>4+(t>>4)&0x01 > 0x05
+			if mc.Status.Carry {
+				mc.A.Add(0x60, false)
+			}
+		} else {
+			// the carry flag is bit 6 of the result and the overflow flag is
+			// bit 6 exclusive-or bit 5
+			mc.Status.Zero = mc.A.IsZero()
+			mc.Status.Sign = mc.A.IsNegative()
+			mc.Status.Carry = mc.A.Value()&0x40 == 0x40
+			mc.Status.Overflow = (mc.A.Value()>>6)&0x01 != (mc.A.Value()>>5)&0x01
+		}
 
 	case "slo":
 		r := mc.acc8
@@ -1510,26 +1682,62 @@ func (mc *CPU) ExecuteInstruction(cycleCallback func() error) error {
 		r.Load(value)
 		mc.Status.Carry = r.ROL(mc.Status.Carry)
 		value = r.Value()
-		mc.A.AND(r.Value())
-		mc.Status.Zero = r.IsZero()
-		mc.Status.Sign = r.IsNegative()
+		mc.A.AND(value)
+		mc.Status.Zero = mc.A.IsZero()
+		mc.Status.Sign = mc.A.IsNegative()
 
-	case "isc":
+	case "sre":
 		r := mc.acc8
 		r.Load(value)
-		r.Add(1, false)
+		mc.Status.Carry = r.LSR()
 		value = r.Value()
-		mc.Status.Carry, mc.Status.Overflow = mc.A.Subtract(value, mc.Status.Carry)
+		mc.A.EOR(value)
 		mc.Status.Zero = mc.A.IsZero()
 		mc.Status.Sign = mc.A.IsNegative()
 
+	case "rra":
+		r := mc.acc8
+		r.Load(value)
+		mc.Status.Carry = r.ROR(mc.Status.Carry)
+		value = r.Value()
+
+		// ... and add to the A register using the carry from the ROR
+		if mc.Status.DecimalMode {
+			mc.Status.Carry,
+				mc.Status.Zero,
+				mc.Status.Overflow,
+				mc.Status.Sign = mc.A.AddDecimal(value, mc.Status.Carry)
+		} else {
+			mc.Status.Carry, mc.Status.Overflow = mc.A.Add(value, mc.Status.Carry)
+			mc.Status.Zero = mc.A.IsZero()
+			mc.Status.Sign = mc.A.IsNegative()
+		}
+
+	case "isc":
+		r := mc.acc8
+		r.Load(value)
+		r.Add(1, false)
+		value = r.Value()
+
+		// ... and subtract from the A register
+		if mc.Status.DecimalMode {
+			mc.Status.Carry,
+				mc.Status.Zero,
+				mc.Status.Overflow,
+				mc.Status.Sign = mc.A.SubtractDecimal(value, mc.Status.Carry)
+		} else {
+			mc.Status.Carry, mc.Status.Overflow = mc.A.Subtract(value, mc.Status.Carry)
+			mc.Status.Zero = mc.A.IsZero()
+			mc.Status.Sign = mc.A.IsNegative()
+		}
+
 	case "anc":
-		// immediate AND. puts bit 7 into the carry flag (in microcode terms
-		// this is as though ASL had been enacted)
+		// immediate AND. puts bit 7 of the result into the carry flag (in
+		// microcode terms this is as though ASL had been enacted)
 		mc.A.AND(value)
 		mc.Status.Zero = mc.A.IsZero()
 		mc.Status.Sign = mc.A.IsNegative()
-		mc.Status.Carry = value&0x80 == 0x80
+		mc.Status.Carry = mc.A.IsNegative()
 
 	default:
 		// this should never, ever happen
@@ -25,6 +25,7 @@ import (
 
 	"github.com/jetsetilly/gopher2600/errors"
 	"github.com/jetsetilly/gopher2600/hardware/cpu"
+	"github.com/jetsetilly/gopher2600/hardware/cpu/instructions"
 	rtest "github.com/jetsetilly/gopher2600/hardware/cpu/registers/test"
 )
 
@@ -124,6 +125,9 @@ func testStatusInstructions(t *testing.T, mc *cpu.CPU, mem *mockMem) {
 	rtest.EquateRegisters(t, mc.Status, "sv-bdIZc")
 	rtest.EquateRegisters(t, mc.SP, 254)
 
+	// the stack is in page one and the pushed value has the break bit set
+	mem.assert(t, 0x01ff, 0x36)
+
 	// mangle status register
 	mc.Status.Sign = true
 	mc.Status.Overflow = true
@@ -576,15 +580,15 @@ func testSubroutineInstructions(t *testing.T, mc *cpu.CPU, mem *mockMem) {
 	_ = mem.putInstructions(origin, 0x20, 0x00, 0x01)
 	step(t, mc) // JSR $0100
 	rtest.EquateRegisters(t, mc.PC, 0x0100)
-	mem.assert(t, 255, 0x00)
-	mem.assert(t, 254, 0x02)
+	mem.assert(t, 0x01ff, 0x00)
+	mem.assert(t, 0x01fe, 0x02)
 	rtest.EquateRegisters(t, mc.SP, 253)
 
 	_ = mem.putInstructions(0x100, 0x60)
 	step(t, mc) // RTS
 	rtest.EquateRegisters(t, mc.PC, 0x0003)
-	mem.assert(t, 255, 0x00)
-	mem.assert(t, 254, 0x02)
+	mem.assert(t, 0x01ff, 0x00)
+	mem.assert(t, 0x01fe, 0x02)
 	rtest.EquateRegisters(t, mc.SP, 255)
 }
 
@@ -610,6 +614,501 @@ func testBRK(t *testing.T, mc *cpu.CPU, mem *mockMem) {
 	step(t, mc) // SED
 }
 
+func testUndocumented(t *testing.T, mc *cpu.CPU, mem *mockMem) {
+	// undocumented instructions are tested away from the zero page so that
+	// they can be used as operands
+	var origin uint16
+
+	reset := func() {
+		mem.Clear()
+		_ = mc.Reset()
+		origin = 0x0200
+		mc.PC.Load(origin)
+	}
+
+	// SLO absolute
+	reset()
+	mem.putInstructions(0x0300, 0x81)
+	mem.putInstructions(origin, 0x0f, 0x00, 0x03)
+	mc.A.Load(0x01)
+	step(t, mc) // SLO $0300
+	mem.assert(t, 0x0300, 0x02)
+	rtest.EquateRegisters(t, mc.A, 0x03)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdizC")
+
+	// RLA zero page
+	reset()
+	mem.putInstructions(0x10, 0x80)
+	mem.putInstructions(origin, 0x27, 0x10)
+	mc.A.Load(0xff)
+	mc.Status.Carry = true
+	step(t, mc) // RLA $10
+	mem.assert(t, 0x10, 0x01)
+	rtest.EquateRegisters(t, mc.A, 0x01)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdizC")
+
+	// SRE zero page, X
+	reset()
+	mem.putInstructions(0x11, 0x03)
+	mem.putInstructions(origin, 0x57, 0x10)
+	mc.A.Load(0x01)
+	mc.X.Load(0x01)
+	step(t, mc) // SRE $10,X
+	mem.assert(t, 0x11, 0x01)
+	rtest.EquateRegisters(t, mc.A, 0x00)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdiZC")
+
+	// RRA absolute, X
+	reset()
+	mem.putInstructions(0x0302, 0x02)
+	mem.putInstructions(origin, 0x7f, 0x00, 0x03)
+	mc.A.Load(0x01)
+	mc.X.Load(0x02)
+	mc.Status.Carry = true
+	step(t, mc) // RRA $0300,X
+	mem.assert(t, 0x0302, 0x81)
+	rtest.EquateRegisters(t, mc.A, 0x82)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizc")
+
+	// DCP (indirect), Y
+	reset()
+	mem.putInstructions(0x20, 0x00, 0x04)
+	mem.putInstructions(0x0401, 0x05)
+	mem.putInstructions(origin, 0xd3, 0x20)
+	mc.A.Load(0x04)
+	mc.Y.Load(0x01)
+	step(t, mc) // DCP ($20),Y
+	mem.assert(t, 0x0401, 0x04)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdiZC")
+
+	// ISC absolute, Y
+	reset()
+	mem.putInstructions(0x0503, 0x0f)
+	mem.putInstructions(origin, 0xfb, 0x00, 0x05)
+	mc.A.Load(0x20)
+	mc.Y.Load(0x03)
+	mc.Status.Carry = true
+	step(t, mc) // ISC $0500,Y
+	mem.assert(t, 0x0503, 0x10)
+	rtest.EquateRegisters(t, mc.A, 0x10)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdizC")
+
+	// ISC zero page (decimal mode)
+	reset()
+	mem.putInstructions(origin, 0xe7, 0x30)
+	mc.A.Load(0x20)
+	mc.Status.Carry = true
+	mc.Status.DecimalMode = true
+	step(t, mc) // ISC $30
+	mem.assert(t, 0x30, 0x01)
+	rtest.EquateRegisters(t, mc.A, 0x19)
+
+	// LAX (indirect, X)
+	reset()
+	mem.putInstructions(0x42, 0x00, 0x06)
+	mem.putInstructions(0x0600, 0x85)
+	mem.putInstructions(origin, 0xa3, 0x40)
+	mc.X.Load(0x02)
+	step(t, mc) // LAX ($40,X)
+	rtest.EquateRegisters(t, mc.A, 0x85)
+	rtest.EquateRegisters(t, mc.X, 0x85)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizc")
+
+	// LXA immediate
+	reset()
+	mem.putInstructions(origin, 0xab, 0x0f)
+	mc.A.Load(0x01)
+	step(t, mc) // LXA #$0f
+	rtest.EquateRegisters(t, mc.A, 0x0f)
+	rtest.EquateRegisters(t, mc.X, 0x0f)
+
+	// LXA immediate. the A register is ORed with the magic value of $ee
+	// before the AND
+	reset()
+	mem.putInstructions(origin, 0xab, 0xff, 0xab, 0x11)
+	mc.A.Load(0x00)
+	step(t, mc) // LXA #$ff
+	rtest.EquateRegisters(t, mc.A, 0xee)
+	rtest.EquateRegisters(t, mc.X, 0xee)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizc")
+	mc.A.Load(0x00)
+	step(t, mc) // LXA #$11
+	rtest.EquateRegisters(t, mc.A, 0x00)
+	rtest.EquateRegisters(t, mc.X, 0x00)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdiZc")
+
+	// LAS absolute, Y
+	reset()
+	mem.putInstructions(0x0700, 0x3c)
+	mem.putInstructions(origin, 0xbb, 0x00, 0x07)
+	mc.SP.Load(0xf0)
+	step(t, mc) // LAS $0700,Y
+	rtest.EquateRegisters(t, mc.A, 0x30)
+	rtest.EquateRegisters(t, mc.X, 0x30)
+	rtest.EquateRegisters(t, mc.SP, 0x30)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdizc")
+
+	// LAS absolute, Y (negative result)
+	reset()
+	mem.putInstructions(0x0710, 0x80)
+	mem.putInstructions(origin, 0xbb, 0x00, 0x07)
+	mc.SP.Load(0xff)
+	mc.Y.Load(0x10)
+	step(t, mc) // LAS $0700,Y
+	rtest.EquateRegisters(t, mc.A, 0x80)
+	rtest.EquateRegisters(t, mc.X, 0x80)
+	rtest.EquateRegisters(t, mc.SP, 0x80)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizc")
+
+	// SAX zero page, Y
+	reset()
+	mem.putInstructions(origin, 0x97, 0x50)
+	mc.A.Load(0xf0)
+	mc.X.Load(0x3c)
+	mc.Y.Load(0x01)
+	step(t, mc) // SAX $50,Y
+	mem.assert(t, 0x51, 0x30)
+
+	// SHX absolute, Y
+	reset()
+	mem.putInstructions(origin, 0x9e, 0x00, 0x12)
+	mc.X.Load(0xff)
+	mc.Y.Load(0x10)
+	step(t, mc) // SHX $1200,Y
+	mem.assert(t, 0x1210, 0x13)
+
+	// SHX absolute, Y (crossing a page boundary)
+	reset()
+	mem.putInstructions(origin, 0x9e, 0xf0, 0x12)
+	mc.X.Load(0x0f)
+	mc.Y.Load(0x20)
+	step(t, mc) // SHX $12f0,Y
+	mem.assert(t, 0x1310, 0x00)
+	mem.assert(t, 0x0310, 0x03)
+
+	// SHY absolute, X
+	reset()
+	mem.putInstructions(origin, 0x9c, 0x00, 0x12)
+	mc.X.Load(0x01)
+	mc.Y.Load(0xff)
+	step(t, mc) // SHY $1200,X
+	mem.assert(t, 0x1201, 0x13)
+
+	// SHY absolute, X (crossing a page boundary)
+	reset()
+	mem.putInstructions(origin, 0x9c, 0xf0, 0x12)
+	mc.X.Load(0x20)
+	mc.Y.Load(0x0f)
+	step(t, mc) // SHY $12f0,X
+	mem.assert(t, 0x1310, 0x00)
+	mem.assert(t, 0x0310, 0x03)
+
+	// SHA absolute, Y
+	reset()
+	mem.putInstructions(origin, 0x9f, 0x00, 0x12)
+	mc.A.Load(0xff)
+	mc.X.Load(0x07)
+	mc.Y.Load(0x02)
+	step(t, mc) // SHA $1200,Y
+	mem.assert(t, 0x1202, 0x03)
+
+	// SHA absolute, Y (crossing a page boundary)
+	reset()
+	mem.putInstructions(origin, 0x9f, 0xf0, 0x12)
+	mc.A.Load(0xff)
+	mc.X.Load(0x0f)
+	mc.Y.Load(0x20)
+	step(t, mc) // SHA $12f0,Y
+	mem.assert(t, 0x1310, 0x00)
+	mem.assert(t, 0x0310, 0x03)
+
+	// SHA (indirect), Y
+	reset()
+	mem.putInstructions(0x60, 0x00, 0x12)
+	mem.putInstructions(origin, 0x93, 0x60)
+	mc.A.Load(0xff)
+	mc.X.Load(0xff)
+	mc.Y.Load(0x03)
+	step(t, mc) // SHA ($60),Y
+	mem.assert(t, 0x1203, 0x13)
+
+	// TAS absolute, Y
+	reset()
+	mem.putInstructions(origin, 0x9b, 0x00, 0x12)
+	mc.A.Load(0xf3)
+	mc.X.Load(0x3f)
+	mc.Y.Load(0x04)
+	step(t, mc) // TAS $1200,Y
+	rtest.EquateRegisters(t, mc.SP, 0x33)
+	mem.assert(t, 0x1204, 0x13)
+
+	// TAS absolute, Y (crossing a page boundary). the status flags are not
+	// affected so the zero flag from the reset is still set
+	reset()
+	mem.putInstructions(origin, 0x9b, 0xf0, 0x12)
+	mc.A.Load(0x0f)
+	mc.X.Load(0xff)
+	mc.Y.Load(0x20)
+	step(t, mc) // TAS $12f0,Y
+	rtest.EquateRegisters(t, mc.SP, 0x0f)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdiZc")
+	mem.assert(t, 0x1310, 0x00)
+	mem.assert(t, 0x0310, 0x03)
+
+	// ANC immediate
+	reset()
+	mem.putInstructions(origin, 0x0b, 0x80)
+	mc.A.Load(0xff)
+	step(t, mc) // ANC #$80
+	rtest.EquateRegisters(t, mc.A, 0x80)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizC")
+
+	// ARR immediate. carry flag is bit 6 of the result and the overflow flag
+	// is bit 6 exclusive-or bit 5
+	reset()
+	mem.putInstructions(origin, 0x6b, 0xff, 0x6b, 0xff)
+	mc.A.Load(0xff)
+	mc.Status.Carry = true
+	step(t, mc) // ARR #$ff
+	rtest.EquateRegisters(t, mc.A, 0xff)
+	rtest.EquateRegisters(t, mc.Status, "Sv-bdizC")
+	mc.A.Load(0x60)
+	mc.Status.Carry = false
+	step(t, mc) // ARR #$ff
+	rtest.EquateRegisters(t, mc.A, 0x30)
+	rtest.EquateRegisters(t, mc.Status, "sV-bdizc")
+
+	// ARR immediate (decimal mode). the sign flag is the old carry flag, the
+	// overflow flag is set if bit 6 changed and each nibble is corrected as
+	// described in "64doc" by John West and Marko Mäkelä
+	reset()
+	mem.putInstructions(origin, 0x6b, 0x5a)
+	mc.A.Load(0xff)
+	mc.Status.Carry = true
+	mc.Status.DecimalMode = true
+	step(t, mc) // ARR #$5a
+	rtest.EquateRegisters(t, mc.A, 0x03)
+	rtest.EquateRegisters(t, mc.Status, "SV-bDizC")
+
+	// SBC immediate (undocumented opcode)
+	reset()
+	mem.putInstructions(origin, 0xeb, 0x01)
+	mc.A.Load(0x05)
+	mc.Status.Carry = true
+	step(t, mc) // SBC #$01
+	rtest.EquateRegisters(t, mc.A, 0x04)
+	rtest.EquateRegisters(t, mc.Status, "sv-bdizC")
+
+	// NOP of different addressing modes
+	reset()
+	origin = mem.putInstructions(origin, 0x1a, 0x89, 0x00, 0x44, 0x00, 0xf4, 0x00)
+	step(t, mc) // NOP
+	step(t, mc) // NOP #$00
+	step(t, mc) // NOP $00
+	step(t, mc) // NOP $00,X
+	rtest.EquateRegisters(t, mc.PC, int(origin))
+
+	// KIL does not advance the program counter
+	reset()
+	mem.putInstructions(origin, 0x02)
+	step(t, mc) // KIL
+	rtest.EquateRegisters(t, mc.PC, int(origin))
+}
+
+func testKIL(t *testing.T, mc *cpu.CPU, mem *mockMem) {
+	var origin uint16
+	mem.Clear()
+	_ = mc.Reset()
+
+	_ = mem.putInstructions(origin, 0xe8, 0x02, 0xe8)
+	step(t, mc) // INX
+	step(t, mc) // KIL
+	if !mc.Killed {
+		t.Fatalf("CPU should be killed after KIL instruction")
+	}
+	rtest.EquateRegisters(t, mc.PC, 1)
+
+	// no more instructions are fetched but the cycle callback is still called
+	// once per call to ExecuteInstruction()
+	cycles := 0
+	for i := 0; i < 10; i++ {
+		err := mc.ExecuteInstruction(func() error {
+			cycles++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cycles != 10 {
+		t.Errorf("expected 10 cycles from killed CPU, got %d", cycles)
+	}
+	rtest.EquateRegisters(t, mc.PC, 1)
+	rtest.EquateRegisters(t, mc.X, 1)
+
+	// a nil cycle callback is allowed for a killed CPU
+	if err := mc.ExecuteInstruction(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// clearing the killed state leaves the registers alone
+	mc.ClearKilled()
+	if mc.Killed {
+		t.Errorf("CPU should not be killed after ClearKilled()")
+	}
+	rtest.EquateRegisters(t, mc.PC, 1)
+	rtest.EquateRegisters(t, mc.X, 1)
+
+	// reset clears the killed state
+	mc.Killed = true
+	_ = mc.Reset()
+	if mc.Killed {
+		t.Errorf("CPU should not be killed after reset")
+	}
+	step(t, mc) // INX
+	rtest.EquateRegisters(t, mc.X, 1)
+}
+
+// the number of cycles for each undocumented instruction, as published in "No
+// More Secrets - NMOS 6510 Unintended Opcodes". the KIL instructions are not
+// included because they never complete
+var undocumentedCycles = map[uint8]int{
+	// SLO, RLA, SRE, RRA, DCP, ISC
+	0x07: 5, 0x17: 6, 0x0f: 6, 0x1f: 7, 0x1b: 7, 0x03: 8, 0x13: 8,
+	0x27: 5, 0x37: 6, 0x2f: 6, 0x3f: 7, 0x3b: 7, 0x23: 8, 0x33: 8,
+	0x47: 5, 0x57: 6, 0x4f: 6, 0x5f: 7, 0x5b: 7, 0x43: 8, 0x53: 8,
+	0x67: 5, 0x77: 6, 0x6f: 6, 0x7f: 7, 0x7b: 7, 0x63: 8, 0x73: 8,
+	0xc7: 5, 0xd7: 6, 0xcf: 6, 0xdf: 7, 0xdb: 7, 0xc3: 8, 0xd3: 8,
+	0xe7: 5, 0xf7: 6, 0xef: 6, 0xff: 7, 0xfb: 7, 0xe3: 8, 0xf3: 8,
+
+	// SAX, LAX
+	0x87: 3, 0x97: 4, 0x8f: 4, 0x83: 6,
+	0xa7: 3, 0xb7: 4, 0xaf: 4, 0xbf: 4, 0xa3: 6, 0xb3: 5,
+
+	// LAS, TAS, SHA, SHX, SHY
+	0xbb: 4, 0x9b: 5, 0x93: 6, 0x9f: 5, 0x9e: 5, 0x9c: 5,
+
+	// immediate
+	0x0b: 2, 0x2b: 2, 0x4b: 2, 0x6b: 2, 0x8b: 2, 0xab: 2, 0xcb: 2, 0xeb: 2,
+
+	// NOP
+	0x1a: 2, 0x3a: 2, 0x5a: 2, 0x7a: 2, 0xda: 2, 0xfa: 2,
+	0x80: 2, 0x82: 2, 0x89: 2, 0xc2: 2, 0xe2: 2,
+	0x04: 3, 0x44: 3, 0x64: 3,
+	0x14: 4, 0x34: 4, 0x54: 4, 0x74: 4, 0xd4: 4, 0xf4: 4,
+	0x0c: 4, 0x1c: 4, 0x3c: 4, 0x5c: 4, 0x7c: 4, 0xdc: 4, 0xfc: 4,
+}
+
+// undocumented instructions that take an extra cycle when indexing crosses a
+// page boundary. the read-modify-write and store instructions always take the
+// same number of cycles
+var undocumentedPageFaultCycles = map[uint8]bool{
+	0xbf: true, 0xb3: true, 0xbb: true,
+	0x1c: true, 0x3c: true, 0x5c: true, 0x7c: true, 0xdc: true, 0xfc: true,
+}
+
+// countCycles executes a single instruction and returns the number of times
+// the cycle callback was called
+func countCycles(t *testing.T, mc *cpu.CPU) int {
+	t.Helper()
+
+	cycles := 0
+	err := mc.ExecuteInstruction(func() error {
+		cycles++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mc.LastResult.ActualCycles != cycles {
+		t.Errorf("%s: cycle callback called %d times for %d cycles", mc.LastResult.Defn.Mnemonic, cycles, mc.LastResult.ActualCycles)
+	}
+
+	return cycles
+}
+
+func TestUndocumentedCycles(t *testing.T) {
+	defs, err := instructions.GetDefinitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for opcode, defn := range defs {
+		if defn == nil {
+			t.Errorf("no definition for opcode %#02x", opcode)
+			continue // for loop
+		}
+
+		if cycles, ok := undocumentedCycles[uint8(opcode)]; ok {
+			if defn.Cycles != cycles {
+				t.Errorf("wrong number of cycles for %s (%#02x): %d instead of %d", defn.Mnemonic, opcode, defn.Cycles, cycles)
+			}
+		}
+	}
+
+	// execute each instruction with index registers that don't cross a page
+	// boundary and then with index registers that do. the operand is $10f0
+	// for absolute addressing and $f0 for zero page addressing. the pointers
+	// used by indirect addressing also point to $10f0
+	for opcode, cycles := range undocumentedCycles {
+		for _, index := range []uint8{0x00, 0x20} {
+			mem := &flatMem{}
+			mem.internal[0x10] = 0xf0
+			mem.internal[0x11] = 0x10
+			mem.internal[0xf0] = 0xf0
+			mem.internal[0xf1] = 0x10
+			copy(mem.internal[0x0200:], []uint8{opcode, 0xf0, 0x10})
+
+			mc, err := cpu.NewCPU(mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mc.PC.Load(0x0200)
+			mc.X.Load(index)
+			mc.Y.Load(index)
+
+			expected := cycles
+			if index != 0x00 && undocumentedPageFaultCycles[opcode] {
+				expected++
+			}
+
+			if c := countCycles(t, mc); c != expected {
+				t.Errorf("%s (%#02x) with index %#02x: %d cycles instead of %d", mc.LastResult.Defn.Mnemonic, opcode, index, c, expected)
+			}
+		}
+	}
+}
+
+func TestBranchCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin uint16
+		zero   bool
+		cycles int
+	}{
+		{name: "not taken", origin: 0x0200, zero: true, cycles: 2},
+		{name: "taken", origin: 0x0200, zero: false, cycles: 3},
+		{name: "taken across page", origin: 0x02f0, zero: false, cycles: 4},
+	}
+
+	for _, tt := range tests {
+		mem := &flatMem{}
+		copy(mem.internal[tt.origin:], []uint8{0xd0, 0x10}) // BNE +16
+
+		mc, err := cpu.NewCPU(mem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mc.PC.Load(tt.origin)
+		mc.Status.Zero = tt.zero
+
+		if c := countCycles(t, mc); c != tt.cycles {
+			t.Errorf("BNE %s: %d cycles instead of %d", tt.name, c, tt.cycles)
+		}
+	}
+}
+
 func TestCPU(t *testing.T) {
 	mem := newMockMem()
 	mc, err := cpu.NewCPU(mem)
@@ -632,4 +1131,69 @@ func TestCPU(t *testing.T) {
 	testSubroutineInstructions(t, mc, mem)
 	testDecimalMode(t, mc, mem)
 	testBRK(t, mc, mem)
+	testUndocumented(t, mc, mem)
+	testKIL(t, mc, mem)
+}
+
+// phantomLog is a flat memory that records the address of every phantom read
+type phantomLog struct {
+	flatMem
+	mc    *cpu.CPU
+	reads []uint16
+}
+
+func (mem *phantomLog) Read(address uint16) (uint8, error) {
+	if mem.mc.PhantomAccess() {
+		mem.reads = append(mem.reads, address)
+	}
+	return mem.flatMem.Read(address)
+}
+
+func TestPhantomReadAddress(t *testing.T) {
+	mem := &phantomLog{}
+	mc, err := cpu.NewCPU(mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem.mc = mc
+
+	// the phantom read of an indexed address is made before the MSB of the
+	// address is fixed. the address on the bus is the MSB of the base address
+	// and the LSB of the indexed address
+	tests := []struct {
+		name    string
+		program []uint8
+		phantom uint16
+	}{
+		// page fault
+		{name: "LDA $10f0,X", program: []uint8{0xbd, 0xf0, 0x10}, phantom: 0x1010},
+		{name: "LDA $10f0,Y", program: []uint8{0xb9, 0xf0, 0x10}, phantom: 0x1010},
+		{name: "LDA ($80),Y", program: []uint8{0xb1, 0x80}, phantom: 0x1010},
+
+		// write instructions always make the phantom read
+		{name: "STA $1000,X", program: []uint8{0x9d, 0x00, 0x10}, phantom: 0x1020},
+		{name: "STA $10f0,Y", program: []uint8{0x99, 0xf0, 0x10}, phantom: 0x1010},
+	}
+
+	for _, tst := range tests {
+		mem.internal = [0x10000]uint8{}
+		copy(mem.internal[0x0200:], tst.program)
+		mem.internal[0x80] = 0xf0
+		mem.internal[0x81] = 0x10
+		mem.reads = mem.reads[:0]
+
+		_ = mc.Reset()
+		mc.PC.Load(0x0200)
+		mc.X.Load(0x20)
+		mc.Y.Load(0x20)
+
+		step(t, mc)
+		if len(mem.reads) != 1 {
+			t.Errorf("%s: expected one phantom read, got %d", tst.name, len(mem.reads))
+			continue // for loop
+		}
+		if mem.reads[0] != tst.phantom {
+			t.Errorf("%s: phantom read of %#04x instead of %#04x", tst.name, mem.reads[0], tst.phantom)
+		}
+	}
 }
@@ -0,0 +1,146 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cpu_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/cpu"
+)
+
+// flatMem is a 64k memory with no mapping and no bus errors. it is used to run
+// the 6502 test suites by Klaus Dormann, which expect a plain 6502 system
+type flatMem struct {
+	internal [0x10000]uint8
+}
+
+func (mem *flatMem) Read(address uint16) (uint8, error) {
+	return mem.internal[address], nil
+}
+
+func (mem *flatMem) ReadZeroPage(address uint8) (uint8, error) {
+	return mem.internal[address], nil
+}
+
+func (mem *flatMem) Write(address uint16, data uint8) error {
+	mem.internal[address] = data
+	return nil
+}
+
+// the maximum number of instructions to execute before deciding that the
+// test program has run away. the functional test requires about 30 million
+// instructions to complete
+const dormannMaxInstructions = 100000000
+
+// runDormann loads the binary into memory at the origin address and executes
+// it from the start address until the program traps itself in a loop (ie. a
+// branch or jump to itself). the trap address is returned. the binaries are
+// part of the repository so the test fails if one cannot be read
+func runDormann(t *testing.T, filename string, origin uint16, start uint16) (*flatMem, uint16) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("%v (see testdata/README)", err)
+	}
+
+	mem := &flatMem{}
+	copy(mem.internal[origin:], data)
+
+	mc, err := cpu.NewCPU(mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc.PC.Load(start)
+
+	for i := 0; i < dormannMaxInstructions; i++ {
+		err := mc.ExecuteInstruction(nil)
+		if err != nil {
+			t.Fatalf("%v (PC %#04x)", err, mc.LastResult.Address)
+		}
+
+		if mc.PC.Address() == mc.LastResult.Address {
+			return mem, mc.PC.Address()
+		}
+	}
+
+	t.Fatalf("%s did not complete after %d instructions (PC %#04x)", filename, dormannMaxInstructions, mc.PC.Address())
+	return nil, 0
+}
+
+// the values in the following tests are correct for the binaries as
+// assembled with the options described in testdata/README. if the binaries
+// are reassembled with different options then the values will need to be
+// changed
+
+func TestDormannFunctional(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping functional test in short mode")
+	}
+
+	const (
+		// the binary starts at the zero_page address given in the source
+		// file and the program starts at code_segment
+		origin = 0x000a
+		start  = 0x0400
+
+		// address of the trap indicating that all tests have passed
+		success = 0x347d
+
+		// the number of the test currently being run is stored here
+		testCase = 0x0200
+	)
+
+	mem, trap := runDormann(t, "testdata/6502_functional_test.bin", origin, start)
+	if trap != success {
+		t.Errorf("functional test %#02x failed (trap at %#04x)", mem.internal[testCase], trap)
+	}
+}
+
+func TestDormannDecimal(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping decimal test in short mode")
+	}
+
+	const (
+		origin = 0x0200
+		start  = 0x0200
+
+		// address of the trap at the end of the test (the DONE label)
+		done = 0x024b
+
+		// the decimal test finishes with the result in the ERROR location.
+		// zero for success, one for failure
+		errorFlag = 0x000b
+
+		// the operands and carry flag for the failing test
+		n1 = 0x0000
+		n2 = 0x0001
+	)
+
+	mem, trap := runDormann(t, "testdata/6502_decimal_test.bin", origin, start)
+	if trap != done {
+		t.Fatalf("decimal test did not finish (trap at %#04x)", trap)
+	}
+	if mem.internal[errorFlag] != 0 {
+		t.Errorf("decimal test failed for %#02x and %#02x (trap at %#04x)", mem.internal[n1], mem.internal[n2], trap)
+	}
+}
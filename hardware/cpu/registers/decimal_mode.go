@@ -78,8 +78,9 @@ func (r *Register) AddDecimal(val uint8, carry bool) (bool, bool, bool, bool) {
 		rtens -= 10
 	}
 
-	// pack units/tens nibbles into register
-	r.value = (rtens << 4) | runits
+	// pack units/tens nibbles into register. the units value can be larger
+	// than a nibble if the operands were not valid BCD
+	r.value = (rtens << 4) | (runits & 0x0f)
 
 	return tcarry, zero, overflow, sign
 }
@@ -136,8 +137,9 @@ func (r *Register) SubtractDecimal(val uint8, carry bool) (bool, bool, bool, boo
 		rtens += 10
 	}
 
-	// pack units/tens nibbles into register
-	r.value = (rtens << 4) | runits
+	// pack units/tens nibbles into register. the units value can be larger
+	// than a nibble if the operands were not valid BCD
+	r.value = (rtens << 4) | (runits & 0x0f)
 
 	return !tcarry, zero, overflow, sign
 }
@@ -98,3 +98,25 @@ func TestDecimalModeInvalid(t *testing.T) {
 	test.Equate(t, rcarry, true)
 	test.Equate(t, rzero, false)
 }
+
+func TestDecimalModeInvalidNibbles(t *testing.T) {
+	var rcarry bool
+
+	r8 := registers.NewRegister(0x1f, "test")
+
+	// the sum of the units is larger than a nibble
+	rcarry, _, _, _ = r8.AddDecimal(0x0a, true)
+	rtest.EquateRegisters(t, r8, 0x20)
+	test.Equate(t, rcarry, false)
+
+	r8.Load(0x1f)
+	rcarry, _, _, _ = r8.AddDecimal(0x0a, false)
+	rtest.EquateRegisters(t, r8, 0x2f)
+	test.Equate(t, rcarry, false)
+
+	// the difference of the units is less than zero
+	r8.Load(0x00)
+	rcarry, _, _, _ = r8.SubtractDecimal(0x0f, true)
+	rtest.EquateRegisters(t, r8, 0x9b)
+	test.Equate(t, rcarry, false)
+}
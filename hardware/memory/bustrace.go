@@ -0,0 +1,55 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package memory
+
+import "github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+
+// BusTracer implementations are notified of every access of the address bus.
+// See the SetBusTracer() function.
+type BusTracer interface {
+	// TraceAccess is called for every read and write of the address bus. The
+	// address is the address as it appeared on the bus, before it was mapped.
+	// The bank is the cartridge bank mapped to the address before the access
+	// took place, or -1 if the address is not in cartridge space.
+	TraceAccess(address uint16, area memorymap.Area, bank int, data uint8, write bool)
+
+	// TraceCycle is called once at the end of every CPU cycle, whether or not
+	// the address bus was accessed during that cycle.
+	TraceCycle()
+}
+
+// SetBusTracer sets the BusTracer to be notified of bus activity. A nil value
+// removes any existing tracer.
+func (mem *VCSMemory) SetBusTracer(t BusTracer) {
+	mem.tracer = t
+}
+
+// BusTracer returns the current BusTracer. nil if there is no tracer.
+func (mem *VCSMemory) BusTracer() BusTracer {
+	return mem.tracer
+}
+
+// TraceCycle notifies the BusTracer, if there is one, that a CPU cycle has
+// completed.
+func (mem *VCSMemory) TraceCycle() {
+	if mem.tracer != nil {
+		mem.tracer.TraceCycle()
+	}
+}
@@ -0,0 +1,365 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package arm7tdmi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// register names
+const (
+	rSP = 13 + iota
+	rLR
+	rPC
+	NumRegisters
+)
+
+// memory map of the LPC2103
+const (
+	FlashOrigin = uint32(0x00000000)
+	SRAMOrigin  = uint32(0x40000000)
+
+	// initial value of the stack pointer. the top of the 8k SRAM area less
+	// the space reserved by the Harmony driver
+	stackOrigin = uint32(0x40001fb4)
+
+	// peripheral registers
+	mamcr  = uint32(0xe01fc000)
+	mamtim = uint32(0xe01fc004)
+	t1tcr  = uint32(0xe0008004)
+	t1tc   = uint32(0xe0008008)
+)
+
+// the maximum number of instructions that will be executed in a single call
+// to Run(). protects the emulation from badly behaved ARM programs
+const maxInstructions = 10000000
+
+// CartridgeHook allows the cartridge mapper to respond to the ARM branching to
+// a non-Thumb address. in Harmony cartridges these addresses are functions in
+// the driver written in 32bit ARM code, which is not emulated.
+type CartridgeHook interface {
+	// addr is the address of the BX instruction that caused the branch. r2 and
+	// r3 are the current values of those registers, which is how arguments
+	// are passed to driver functions
+	ARMinterrupt(addr uint32, r2 uint32, r3 uint32) (ARMinterruptReturn, error)
+}
+
+// ARMinterruptReturn is the result of a call to ARMinterrupt()
+type ARMinterruptReturn struct {
+	// if InterruptServiced is false then the ARM program ends
+	InterruptServiced bool
+
+	// the driver function returned a value that should be placed in
+	// register SaveRegister
+	SaveResult   bool
+	SaveRegister uint32
+	SaveValue    uint32
+}
+
+// ARM implements the ARM7TDMI-S LPC2103 processor.
+type ARM struct {
+	registers [NumRegisters]uint32
+	status    status
+
+	flash []uint8
+	sram  []uint8
+	hook  CartridgeHook
+
+	// the values of the registers at the start of every call to Run()
+	entryPC uint32
+	entryLR uint32
+
+	// memory accelerator module and timer registers. the values have no
+	// effect on the emulation except for the timer counter, which increases
+	// with every cycle when the timer is enabled
+	mamcr        uint32
+	mamtim       uint32
+	timerControl uint32
+	timerCounter uint32
+
+	// the number of cycles consumed by the current call to Run()
+	cycles int
+
+	// set by the instruction that changes the program counter
+	branched bool
+
+	// whether the ARM program should continue. set to false when the program
+	// returns to the cartridge driver or when an error occurs
+	continueExecution bool
+
+	// errors encountered during execution of an instruction
+	executionError error
+}
+
+// NewARM is the preferred method of initialisation for the ARM type. flash
+// and sram are shared with the cartridge mapper. entryPC is the address of
+// the first Thumb instruction and entryLR is the value of the link register
+// when the program starts.
+func NewARM(flash []uint8, sram []uint8, entryPC uint32, entryLR uint32, hook CartridgeHook) *ARM {
+	arm := &ARM{
+		flash:   flash,
+		sram:    sram,
+		entryPC: entryPC,
+		entryLR: entryLR,
+		hook:    hook,
+	}
+	arm.reset()
+	return arm
+}
+
+func (arm ARM) String() string {
+	s := strings.Builder{}
+	for i, r := range arm.registers {
+		if i > 0 {
+			if i%4 == 0 {
+				s.WriteString("\n")
+			} else {
+				s.WriteString("\t")
+			}
+		}
+		s.WriteString(fmt.Sprintf("R%-2d: %08x", i, r))
+	}
+	s.WriteString(fmt.Sprintf("\t%s", arm.status))
+	return s.String()
+}
+
+func (arm *ARM) reset() {
+	for i := range arm.registers {
+		arm.registers[i] = 0
+	}
+	arm.registers[rSP] = stackOrigin
+	arm.registers[rLR] = arm.entryLR
+	arm.registers[rPC] = arm.entryPC
+	arm.status.reset()
+}
+
+// armState is the part of the ARM that persists between calls to Run()
+type armState struct {
+	registers    [NumRegisters]uint32
+	status       status
+	mamcr        uint32
+	mamtim       uint32
+	timerControl uint32
+	timerCounter uint32
+}
+
+// SaveState returns a snapshot of the ARM. Flash and SRAM are not included
+// because they belong to the cartridge mapper.
+func (arm *ARM) SaveState() interface{} {
+	return armState{
+		registers:    arm.registers,
+		status:       arm.status,
+		mamcr:        arm.mamcr,
+		mamtim:       arm.mamtim,
+		timerControl: arm.timerControl,
+		timerCounter: arm.timerCounter,
+	}
+}
+
+// RestoreState returns the ARM to the state returned by an earlier call to
+// SaveState().
+func (arm *ARM) RestoreState(state interface{}) {
+	s := state.(armState)
+	arm.registers = s.registers
+	arm.status = s.status
+	arm.mamcr = s.mamcr
+	arm.mamtim = s.mamtim
+	arm.timerControl = s.timerControl
+	arm.timerCounter = s.timerCounter
+}
+
+// Register returns the value of the specified register. Values from rPC are
+// the address of the next instruction to be executed.
+func (arm ARM) Register(reg int) uint32 {
+	return arm.registers[reg]
+}
+
+// Run the ARM program from the entry point until it returns to the cartridge
+// driver. Returns the number of ARM cycles consumed.
+func (arm *ARM) Run() (int, error) {
+	arm.reset()
+	arm.cycles = 0
+	arm.executionError = nil
+	arm.continueExecution = true
+
+	for n := 0; arm.continueExecution; n++ {
+		if n >= maxInstructions {
+			return arm.cycles, errors.New(errors.ARMError, fmt.Sprintf("program did not return after %d instructions", maxInstructions))
+		}
+
+		c := arm.cycles
+		arm.executeInstruction()
+		if arm.executionError != nil {
+			return arm.cycles, arm.executionError
+		}
+
+		if arm.timerControl&0x01 == 0x01 {
+			arm.timerCounter += uint32(arm.cycles - c)
+		}
+	}
+
+	return arm.cycles, nil
+}
+
+// mapAddress returns the memory area and the index into that area for the
+// specified address. returns nil if the address is not in flash or sram, or
+// if a write is being attempted to flash.
+func (arm *ARM) mapAddress(addr uint32, write bool) ([]uint8, uint32) {
+	if addr >= SRAMOrigin && addr-SRAMOrigin < uint32(len(arm.sram)) {
+		return arm.sram, addr - SRAMOrigin
+	}
+
+	if !write && addr-FlashOrigin < uint32(len(arm.flash)) {
+		return arm.flash, addr - FlashOrigin
+	}
+
+	return nil, 0
+}
+
+func (arm *ARM) memoryError(addr uint32, write bool) {
+	if arm.executionError != nil {
+		return
+	}
+	if write {
+		arm.executionError = errors.New(errors.ARMError, fmt.Sprintf("illegal write to %08x", addr))
+	} else {
+		arm.executionError = errors.New(errors.ARMError, fmt.Sprintf("illegal read from %08x", addr))
+	}
+	arm.continueExecution = false
+}
+
+func (arm *ARM) readPeripheral(addr uint32) (uint32, bool) {
+	switch addr {
+	case mamcr:
+		return arm.mamcr, true
+	case mamtim:
+		return arm.mamtim, true
+	case t1tcr:
+		return arm.timerControl, true
+	case t1tc:
+		return arm.timerCounter, true
+	}
+	return 0, false
+}
+
+func (arm *ARM) writePeripheral(addr uint32, val uint32) bool {
+	switch addr {
+	case mamcr:
+		arm.mamcr = val
+	case mamtim:
+		arm.mamtim = val
+	case t1tcr:
+		arm.timerControl = val
+		// bit 1 resets the counter
+		if val&0x02 == 0x02 {
+			arm.timerCounter = 0
+		}
+	case t1tc:
+		arm.timerCounter = val
+	default:
+		return false
+	}
+	return true
+}
+
+func (arm *ARM) read8bit(addr uint32) uint8 {
+	mem, idx := arm.mapAddress(addr, false)
+	if mem == nil {
+		if v, ok := arm.readPeripheral(addr &^ 0x03); ok {
+			return uint8(v >> ((addr & 0x03) * 8))
+		}
+		arm.memoryError(addr, false)
+		return 0
+	}
+	return mem[idx]
+}
+
+func (arm *ARM) write8bit(addr uint32, val uint8) {
+	mem, idx := arm.mapAddress(addr, true)
+	if mem == nil {
+		if arm.writePeripheral(addr, uint32(val)) {
+			return
+		}
+		arm.memoryError(addr, true)
+		return
+	}
+	mem[idx] = val
+}
+
+// 16bit and 32bit accesses are forced to the correct alignment
+
+func (arm *ARM) read16bit(addr uint32) uint16 {
+	addr &= 0xfffffffe
+	mem, idx := arm.mapAddress(addr, false)
+	if mem == nil || idx+1 >= uint32(len(mem)) {
+		if v, ok := arm.readPeripheral(addr &^ 0x03); ok {
+			return uint16(v >> ((addr & 0x02) * 8))
+		}
+		arm.memoryError(addr, false)
+		return 0
+	}
+	return uint16(mem[idx]) | uint16(mem[idx+1])<<8
+}
+
+func (arm *ARM) write16bit(addr uint32, val uint16) {
+	addr &= 0xfffffffe
+	mem, idx := arm.mapAddress(addr, true)
+	if mem == nil || idx+1 >= uint32(len(mem)) {
+		if arm.writePeripheral(addr, uint32(val)) {
+			return
+		}
+		arm.memoryError(addr, true)
+		return
+	}
+	mem[idx] = uint8(val)
+	mem[idx+1] = uint8(val >> 8)
+}
+
+func (arm *ARM) read32bit(addr uint32) uint32 {
+	addr &= 0xfffffffc
+	mem, idx := arm.mapAddress(addr, false)
+	if mem == nil || idx+3 >= uint32(len(mem)) {
+		if v, ok := arm.readPeripheral(addr); ok {
+			return v
+		}
+		arm.memoryError(addr, false)
+		return 0
+	}
+	return uint32(mem[idx]) | uint32(mem[idx+1])<<8 | uint32(mem[idx+2])<<16 | uint32(mem[idx+3])<<24
+}
+
+func (arm *ARM) write32bit(addr uint32, val uint32) {
+	addr &= 0xfffffffc
+	mem, idx := arm.mapAddress(addr, true)
+	if mem == nil || idx+3 >= uint32(len(mem)) {
+		if arm.writePeripheral(addr, val) {
+			return
+		}
+		arm.memoryError(addr, true)
+		return
+	}
+	mem[idx] = uint8(val)
+	mem[idx+1] = uint8(val >> 8)
+	mem[idx+2] = uint8(val >> 16)
+	mem[idx+3] = uint8(val >> 24)
+}
@@ -0,0 +1,137 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package arm7tdmi_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge/arm7tdmi"
+)
+
+// assemble little-endian thumb opcodes (and literal values) into flash memory
+func assemble(opcodes ...uint16) []uint8 {
+	flash := make([]uint8, 256)
+	for i, o := range opcodes {
+		flash[i*2] = uint8(o)
+		flash[i*2+1] = uint8(o >> 8)
+	}
+	return flash
+}
+
+type hook struct {
+	calls []uint32
+}
+
+func (h *hook) ARMinterrupt(addr uint32, r2 uint32, r3 uint32) (arm7tdmi.ARMinterruptReturn, error) {
+	h.calls = append(h.calls, addr)
+	if addr != 0x0e {
+		return arm7tdmi.ARMinterruptReturn{}, nil
+	}
+	return arm7tdmi.ARMinterruptReturn{
+		InterruptServiced: true,
+		SaveResult:        true,
+		SaveRegister:      0,
+		SaveValue:         r2 + r3,
+	}, nil
+}
+
+func TestArithmetic(t *testing.T) {
+	flash := assemble(
+		0x2005, // MOV r0, #5
+		0x2103, // MOV r1, #3
+		0x1842, // ADD r2, r0, r1
+		0x1a0b, // SUB r3, r1, r0
+		0x4770, // BX LR
+	)
+
+	arm := arm7tdmi.NewARM(flash, make([]uint8, 8192), 0x00, 0x00, nil)
+	if _, err := arm.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if arm.Register(2) != 8 {
+		t.Errorf("ADD: expected 8, got %d", arm.Register(2))
+	}
+	if arm.Register(3) != 0xfffffffe {
+		t.Errorf("SUB: expected fffffffe, got %08x", arm.Register(3))
+	}
+}
+
+func TestLoopAndStore(t *testing.T) {
+	flash := assemble(
+		0x4803, // LDR r0, [PC, #12]
+		0x2100, // MOV r1, #0
+		0x220a, // MOV r2, #10
+		0x1889, // ADD r1, r1, r2
+		0x3a01, // SUB r2, #1
+		0xd1fc, // BNE -8
+		0x6001, // STR r1, [r0, #0]
+		0x4770, // BX LR
+		0x0000, // literal 0x40000000
+		0x4000,
+	)
+
+	sram := make([]uint8, 8192)
+	arm := arm7tdmi.NewARM(flash, sram, 0x00, 0x00, nil)
+	if _, err := arm.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sram[0] != 55 {
+		t.Errorf("expected 55 in sram, got %d", sram[0])
+	}
+
+	// running the program again must produce the same result
+	sram[0] = 0
+	c1, _ := arm.Run()
+	c2, _ := arm.Run()
+	if c1 != c2 || sram[0] != 55 {
+		t.Errorf("execution is not deterministic")
+	}
+}
+
+func TestInterwork(t *testing.T) {
+	flash := assemble(
+		0xf000, // BL +2
+		0xf802,
+		0x2700, // MOV r7, #0
+		0x4738, // BX r7
+		0x2202, // MOV r2, #2
+		0x2307, // MOV r3, #7
+		0x4c00, // LDR r4, [PC, #0]
+		0x4720, // BX r4
+		0x0100, // literal 0x00000100
+		0x0000,
+	)
+
+	h := &hook{}
+	arm := arm7tdmi.NewARM(flash, make([]uint8, 8192), 0x00, 0x00, h)
+	if _, err := arm.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if arm.Register(0) != 9 {
+		t.Errorf("expected driver function to return 9 in r0, got %d", arm.Register(0))
+	}
+
+	if len(h.calls) != 2 || h.calls[0] != 0x0e || h.calls[1] != 0x06 {
+		t.Errorf("unexpected calls to cartridge hook: %v", h.calls)
+	}
+}
@@ -0,0 +1,40 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+// Package arm7tdmi implements the ARM7TDMI processor found in the Harmony and
+// Melody cartridges. Only the Thumb instruction set is emulated, which is all
+// that is required by the custom code found in DPC+ and CDF cartridges.
+//
+// The memory map is that of the LPC2103 microcontroller. Flash memory (the
+// cartridge ROM) is mapped from 0x00000000 and SRAM is mapped from
+// 0x40000000. Both are supplied by the cartridge mapper when the ARM is
+// created, meaning that changes made by the ARM are immediately visible to the
+// mapper and vice-versa.
+//
+// The Thumb program is started with the Run() function. It runs until the
+// program branches to a non-Thumb address that the cartridge mapper does not
+// recognise. Non-Thumb branches to addresses the mapper does recognise are
+// treated as function calls into the cartridge driver (see the CartridgeHook
+// interface).
+//
+// From the point of view of the VCS, the ARM program executes instantly. A
+// count of the ARM cycles consumed is returned by Run() but the count is an
+// approximation. Importantly, execution is entirely deterministic; the same
+// program with the same memory contents will always produce the same result.
+package arm7tdmi
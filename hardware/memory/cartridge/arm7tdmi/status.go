@@ -0,0 +1,135 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package arm7tdmi
+
+import "strings"
+
+// the condition flags of the current program status register. the other bits
+// in the register are of no interest to a thumb-only emulation
+type status struct {
+	negative bool
+	zero     bool
+	overflow bool
+	carry    bool
+}
+
+func (sr status) String() string {
+	s := strings.Builder{}
+	if sr.negative {
+		s.WriteRune('N')
+	} else {
+		s.WriteRune('n')
+	}
+	if sr.zero {
+		s.WriteRune('Z')
+	} else {
+		s.WriteRune('z')
+	}
+	if sr.overflow {
+		s.WriteRune('V')
+	} else {
+		s.WriteRune('v')
+	}
+	if sr.carry {
+		s.WriteRune('C')
+	} else {
+		s.WriteRune('c')
+	}
+	return s.String()
+}
+
+func (sr *status) reset() {
+	sr.negative = false
+	sr.zero = false
+	sr.overflow = false
+	sr.carry = false
+}
+
+func (sr *status) isNegative(a uint32) {
+	sr.negative = a&0x80000000 == 0x80000000
+}
+
+func (sr *status) isZero(a uint32) {
+	sr.zero = a == 0x00
+}
+
+// setCarry and setOverflow should be called with the operands of an addition.
+// subtractions should be expressed as an addition of the inverted second
+// operand with a carry-in of one.
+func (sr *status) setCarry(a, b, c uint32) {
+	sr.carry = uint64(a)+uint64(b)+uint64(c) > 0xffffffff
+}
+
+func (sr *status) setOverflow(a, b, c uint32) {
+	r := a + b + c
+	sr.overflow = (a^r)&(b^r)&0x80000000 == 0x80000000
+}
+
+// condition returns true if the four bit condition code is satisfied by the
+// current state of the status flags
+func (sr status) condition(cond uint8) bool {
+	switch cond {
+	case 0b0000:
+		// EQ
+		return sr.zero
+	case 0b0001:
+		// NE
+		return !sr.zero
+	case 0b0010:
+		// CS
+		return sr.carry
+	case 0b0011:
+		// CC
+		return !sr.carry
+	case 0b0100:
+		// MI
+		return sr.negative
+	case 0b0101:
+		// PL
+		return !sr.negative
+	case 0b0110:
+		// VS
+		return sr.overflow
+	case 0b0111:
+		// VC
+		return !sr.overflow
+	case 0b1000:
+		// HI
+		return sr.carry && !sr.zero
+	case 0b1001:
+		// LS
+		return !sr.carry || sr.zero
+	case 0b1010:
+		// GE
+		return sr.negative == sr.overflow
+	case 0b1011:
+		// LT
+		return sr.negative != sr.overflow
+	case 0b1100:
+		// GT
+		return !sr.zero && sr.negative == sr.overflow
+	case 0b1101:
+		// LE
+		return sr.zero || sr.negative != sr.overflow
+	}
+
+	// AL
+	return true
+}
@@ -0,0 +1,697 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package arm7tdmi
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// the format numbers used in the comments below refer to the nineteen thumb
+// instruction formats described in the ARM7TDMI data sheet (ARM DDI 0029E)
+
+func (arm *ARM) executeInstruction() {
+	addr := arm.registers[rPC]
+	opcode := arm.read16bit(addr)
+
+	// the pipeline means that the program counter is two instructions ahead
+	// of the instruction being executed. instructions that read the program
+	// counter will see this value
+	arm.registers[rPC] = addr + 4
+	arm.branched = false
+
+	// one cycle for the instruction fetch. instructions add additional cycles
+	// as required
+	arm.cycles++
+
+	switch {
+	case opcode&0xf800 == 0x1800:
+		arm.thumbAddSubtract(opcode)
+	case opcode&0xe000 == 0x0000:
+		arm.thumbMoveShiftedRegister(opcode)
+	case opcode&0xe000 == 0x2000:
+		arm.thumbMovCmpAddSubImm(opcode)
+	case opcode&0xfc00 == 0x4000:
+		arm.thumbALUoperations(opcode)
+	case opcode&0xfc00 == 0x4400:
+		arm.thumbHiRegisterOps(opcode)
+	case opcode&0xf800 == 0x4800:
+		arm.thumbPCrelativeLoad(opcode)
+	case opcode&0xf200 == 0x5000:
+		arm.thumbLoadStoreWithRegisterOffset(opcode)
+	case opcode&0xf200 == 0x5200:
+		arm.thumbLoadStoreSignExtendedByteHalfword(opcode)
+	case opcode&0xe000 == 0x6000:
+		arm.thumbLoadStoreWithImmOffset(opcode)
+	case opcode&0xf000 == 0x8000:
+		arm.thumbLoadStoreHalfword(opcode)
+	case opcode&0xf000 == 0x9000:
+		arm.thumbSPRelativeLoadStore(opcode)
+	case opcode&0xf000 == 0xa000:
+		arm.thumbLoadAddress(opcode)
+	case opcode&0xff00 == 0xb000:
+		arm.thumbAddOffsetToSP(opcode)
+	case opcode&0xf600 == 0xb400:
+		arm.thumbPushPopRegisters(opcode)
+	case opcode&0xf000 == 0xc000:
+		arm.thumbMultipleLoadStore(opcode)
+	case opcode&0xff00 == 0xdf00:
+		arm.thumbSoftwareInterrupt(opcode)
+	case opcode&0xf000 == 0xd000:
+		arm.thumbConditionalBranch(opcode)
+	case opcode&0xf800 == 0xe000:
+		arm.thumbUnconditionalBranch(opcode)
+	case opcode&0xf000 == 0xf000:
+		arm.thumbLongBranchWithLink(opcode)
+	default:
+		arm.undefinedInstruction(opcode)
+	}
+
+	if !arm.branched {
+		arm.registers[rPC] = addr + 2
+	}
+}
+
+func (arm *ARM) undefinedInstruction(opcode uint16) {
+	arm.executionError = errors.New(errors.ARMError, fmt.Sprintf("undefined instruction (%04x) at %08x", opcode, arm.registers[rPC]-4))
+	arm.continueExecution = false
+}
+
+// branch to a thumb address
+func (arm *ARM) branch(addr uint32) {
+	arm.registers[rPC] = addr &^ 0x01
+	arm.branched = true
+
+	// refilling the pipeline
+	arm.cycles += 2
+}
+
+// interwork branches to the address, which may be a non-thumb address
+func (arm *ARM) interwork(addr uint32) {
+	if addr&0x01 == 0x01 {
+		arm.branch(addr)
+		return
+	}
+
+	// 32bit ARM code is not emulated. the cartridge driver is asked to handle
+	// the branch. if it does not recognise the address then the program has
+	// finished
+	if arm.hook == nil {
+		arm.continueExecution = false
+		return
+	}
+
+	r, err := arm.hook.ARMinterrupt(arm.registers[rPC]-4, arm.registers[2], arm.registers[3])
+	if err != nil {
+		arm.executionError = err
+		arm.continueExecution = false
+		return
+	}
+
+	if !r.InterruptServiced {
+		arm.continueExecution = false
+		return
+	}
+
+	if r.SaveResult {
+		arm.registers[r.SaveRegister] = r.SaveValue
+	}
+
+	arm.branch(arm.registers[rLR])
+}
+
+// add sets all flags and returns the result of a+b+c. subtraction is
+// performed by inverting b and setting c to one
+func (arm *ARM) add(a, b, c uint32) uint32 {
+	arm.status.setCarry(a, b, c)
+	arm.status.setOverflow(a, b, c)
+	r := a + b + c
+	arm.status.isNegative(r)
+	arm.status.isZero(r)
+	return r
+}
+
+func (arm *ARM) logical(r uint32) uint32 {
+	arm.status.isNegative(r)
+	arm.status.isZero(r)
+	return r
+}
+
+// the shift functions are used by the ALU operations, where the shift amount
+// is taken from a register and may be larger than 32
+func (arm *ARM) lsl(a uint32, shift uint32) uint32 {
+	switch {
+	case shift == 0:
+		return a
+	case shift < 32:
+		arm.status.carry = (a>>(32-shift))&0x01 == 0x01
+	case shift == 32:
+		arm.status.carry = a&0x01 == 0x01
+	default:
+		arm.status.carry = false
+	}
+	return a << shift
+}
+
+func (arm *ARM) lsr(a uint32, shift uint32) uint32 {
+	switch {
+	case shift == 0:
+		return a
+	case shift <= 32:
+		arm.status.carry = (a>>(shift-1))&0x01 == 0x01
+	default:
+		arm.status.carry = false
+	}
+	return a >> shift
+}
+
+func (arm *ARM) asr(a uint32, shift uint32) uint32 {
+	switch {
+	case shift == 0:
+		return a
+	case shift < 32:
+		arm.status.carry = (a>>(shift-1))&0x01 == 0x01
+	default:
+		arm.status.carry = a&0x80000000 == 0x80000000
+	}
+	return uint32(int32(a) >> shift)
+}
+
+func (arm *ARM) ror(a uint32, shift uint32) uint32 {
+	if shift == 0 {
+		return a
+	}
+	shift &= 0x1f
+	if shift == 0 {
+		arm.status.carry = a&0x80000000 == 0x80000000
+		return a
+	}
+	arm.status.carry = (a>>(shift-1))&0x01 == 0x01
+	return bits.RotateLeft32(a, -int(shift))
+}
+
+// format 1
+func (arm *ARM) thumbMoveShiftedRegister(opcode uint16) {
+	op := (opcode & 0x1800) >> 11
+	shift := uint32((opcode & 0x07c0) >> 6)
+	srcReg := (opcode & 0x0038) >> 3
+	destReg := opcode & 0x0007
+
+	src := arm.registers[srcReg]
+
+	switch op {
+	case 0b00:
+		// LSL
+		arm.registers[destReg] = arm.logical(arm.lsl(src, shift))
+	case 0b01:
+		// LSR. a shift of zero is encoded to mean a shift of 32
+		if shift == 0 {
+			shift = 32
+		}
+		arm.registers[destReg] = arm.logical(arm.lsr(src, shift))
+	case 0b10:
+		// ASR. a shift of zero is encoded to mean a shift of 32
+		if shift == 0 {
+			shift = 32
+		}
+		arm.registers[destReg] = arm.logical(arm.asr(src, shift))
+	}
+}
+
+// format 2
+func (arm *ARM) thumbAddSubtract(opcode uint16) {
+	immediate := opcode&0x0400 == 0x0400
+	subtract := opcode&0x0200 == 0x0200
+	val := (opcode & 0x01c0) >> 6
+	srcReg := (opcode & 0x0038) >> 3
+	destReg := opcode & 0x0007
+
+	var operand uint32
+	if immediate {
+		operand = uint32(val)
+	} else {
+		operand = arm.registers[val]
+	}
+
+	if subtract {
+		arm.registers[destReg] = arm.add(arm.registers[srcReg], ^operand, 1)
+	} else {
+		arm.registers[destReg] = arm.add(arm.registers[srcReg], operand, 0)
+	}
+}
+
+// format 3
+func (arm *ARM) thumbMovCmpAddSubImm(opcode uint16) {
+	op := (opcode & 0x1800) >> 11
+	destReg := (opcode & 0x0700) >> 8
+	imm := uint32(opcode & 0x00ff)
+
+	switch op {
+	case 0b00:
+		// MOV
+		arm.registers[destReg] = arm.logical(imm)
+	case 0b01:
+		// CMP
+		arm.add(arm.registers[destReg], ^imm, 1)
+	case 0b10:
+		// ADD
+		arm.registers[destReg] = arm.add(arm.registers[destReg], imm, 0)
+	case 0b11:
+		// SUB
+		arm.registers[destReg] = arm.add(arm.registers[destReg], ^imm, 1)
+	}
+}
+
+// format 4
+func (arm *ARM) thumbALUoperations(opcode uint16) {
+	op := (opcode & 0x03c0) >> 6
+	srcReg := (opcode & 0x0038) >> 3
+	destReg := opcode & 0x0007
+
+	src := arm.registers[srcReg]
+	dest := arm.registers[destReg]
+
+	var carry uint32
+	if arm.status.carry {
+		carry = 1
+	}
+
+	switch op {
+	case 0b0000:
+		// AND
+		arm.registers[destReg] = arm.logical(dest & src)
+	case 0b0001:
+		// EOR
+		arm.registers[destReg] = arm.logical(dest ^ src)
+	case 0b0010:
+		// LSL
+		arm.registers[destReg] = arm.logical(arm.lsl(dest, src&0xff))
+		arm.cycles++
+	case 0b0011:
+		// LSR
+		arm.registers[destReg] = arm.logical(arm.lsr(dest, src&0xff))
+		arm.cycles++
+	case 0b0100:
+		// ASR
+		arm.registers[destReg] = arm.logical(arm.asr(dest, src&0xff))
+		arm.cycles++
+	case 0b0101:
+		// ADC
+		arm.registers[destReg] = arm.add(dest, src, carry)
+	case 0b0110:
+		// SBC
+		arm.registers[destReg] = arm.add(dest, ^src, carry)
+	case 0b0111:
+		// ROR
+		arm.registers[destReg] = arm.logical(arm.ror(dest, src&0xff))
+		arm.cycles++
+	case 0b1000:
+		// TST
+		arm.logical(dest & src)
+	case 0b1001:
+		// NEG
+		arm.registers[destReg] = arm.add(0, ^src, 1)
+	case 0b1010:
+		// CMP
+		arm.add(dest, ^src, 1)
+	case 0b1011:
+		// CMN
+		arm.add(dest, src, 0)
+	case 0b1100:
+		// ORR
+		arm.registers[destReg] = arm.logical(dest | src)
+	case 0b1101:
+		// MUL. the number of cycles depends on the value of the multiplier
+		// but a fixed value is good enough
+		arm.registers[destReg] = arm.logical(dest * src)
+		arm.cycles += 2
+	case 0b1110:
+		// BIC
+		arm.registers[destReg] = arm.logical(dest &^ src)
+	case 0b1111:
+		// MVN
+		arm.registers[destReg] = arm.logical(^src)
+	}
+}
+
+// format 5
+func (arm *ARM) thumbHiRegisterOps(opcode uint16) {
+	op := (opcode & 0x0300) >> 8
+	srcReg := (opcode & 0x0038) >> 3
+	destReg := opcode & 0x0007
+
+	if opcode&0x0080 == 0x0080 {
+		destReg += 8
+	}
+	if opcode&0x0040 == 0x0040 {
+		srcReg += 8
+	}
+
+	switch op {
+	case 0b00:
+		// ADD
+		r := arm.registers[destReg] + arm.registers[srcReg]
+		if destReg == rPC {
+			arm.branch(r)
+		} else {
+			arm.registers[destReg] = r
+		}
+	case 0b01:
+		// CMP
+		arm.add(arm.registers[destReg], ^arm.registers[srcReg], 1)
+	case 0b10:
+		// MOV
+		r := arm.registers[srcReg]
+		if destReg == rPC {
+			arm.branch(r)
+		} else {
+			arm.registers[destReg] = r
+		}
+	case 0b11:
+		// BX
+		arm.interwork(arm.registers[srcReg])
+	}
+}
+
+// format 6
+func (arm *ARM) thumbPCrelativeLoad(opcode uint16) {
+	destReg := (opcode & 0x0700) >> 8
+	imm := uint32(opcode&0x00ff) << 2
+
+	// bit 1 of the program counter is ignored so that the address is always
+	// word aligned
+	arm.registers[destReg] = arm.read32bit((arm.registers[rPC] &^ 0x02) + imm)
+	arm.cycles += 2
+}
+
+// format 7
+func (arm *ARM) thumbLoadStoreWithRegisterOffset(opcode uint16) {
+	load := opcode&0x0800 == 0x0800
+	byteTransfer := opcode&0x0400 == 0x0400
+	offsetReg := (opcode & 0x01c0) >> 6
+	baseReg := (opcode & 0x0038) >> 3
+	reg := opcode & 0x0007
+
+	addr := arm.registers[baseReg] + arm.registers[offsetReg]
+
+	if load {
+		if byteTransfer {
+			arm.registers[reg] = uint32(arm.read8bit(addr))
+		} else {
+			arm.registers[reg] = arm.read32bit(addr)
+		}
+		arm.cycles += 2
+		return
+	}
+
+	if byteTransfer {
+		arm.write8bit(addr, uint8(arm.registers[reg]))
+	} else {
+		arm.write32bit(addr, arm.registers[reg])
+	}
+	arm.cycles++
+}
+
+// format 8
+func (arm *ARM) thumbLoadStoreSignExtendedByteHalfword(opcode uint16) {
+	hi := opcode&0x0800 == 0x0800
+	sign := opcode&0x0400 == 0x0400
+	offsetReg := (opcode & 0x01c0) >> 6
+	baseReg := (opcode & 0x0038) >> 3
+	reg := opcode & 0x0007
+
+	addr := arm.registers[baseReg] + arm.registers[offsetReg]
+
+	switch {
+	case !sign && !hi:
+		// STRH
+		arm.write16bit(addr, uint16(arm.registers[reg]))
+		arm.cycles++
+		return
+	case !sign && hi:
+		// LDRH
+		arm.registers[reg] = uint32(arm.read16bit(addr))
+	case sign && !hi:
+		// LDSB
+		arm.registers[reg] = uint32(int32(int8(arm.read8bit(addr))))
+	case sign && hi:
+		// LDSH
+		arm.registers[reg] = uint32(int32(int16(arm.read16bit(addr))))
+	}
+	arm.cycles += 2
+}
+
+// format 9
+func (arm *ARM) thumbLoadStoreWithImmOffset(opcode uint16) {
+	byteTransfer := opcode&0x1000 == 0x1000
+	load := opcode&0x0800 == 0x0800
+	offset := uint32((opcode & 0x07c0) >> 6)
+	baseReg := (opcode & 0x0038) >> 3
+	reg := opcode & 0x0007
+
+	// word transfers have an offset in units of four bytes
+	if !byteTransfer {
+		offset <<= 2
+	}
+
+	addr := arm.registers[baseReg] + offset
+
+	if load {
+		if byteTransfer {
+			arm.registers[reg] = uint32(arm.read8bit(addr))
+		} else {
+			arm.registers[reg] = arm.read32bit(addr)
+		}
+		arm.cycles += 2
+		return
+	}
+
+	if byteTransfer {
+		arm.write8bit(addr, uint8(arm.registers[reg]))
+	} else {
+		arm.write32bit(addr, arm.registers[reg])
+	}
+	arm.cycles++
+}
+
+// format 10
+func (arm *ARM) thumbLoadStoreHalfword(opcode uint16) {
+	load := opcode&0x0800 == 0x0800
+	offset := uint32((opcode&0x07c0)>>6) << 1
+	baseReg := (opcode & 0x0038) >> 3
+	reg := opcode & 0x0007
+
+	addr := arm.registers[baseReg] + offset
+
+	if load {
+		arm.registers[reg] = uint32(arm.read16bit(addr))
+		arm.cycles += 2
+		return
+	}
+
+	arm.write16bit(addr, uint16(arm.registers[reg]))
+	arm.cycles++
+}
+
+// format 11
+func (arm *ARM) thumbSPRelativeLoadStore(opcode uint16) {
+	load := opcode&0x0800 == 0x0800
+	reg := (opcode & 0x0700) >> 8
+	offset := uint32(opcode&0x00ff) << 2
+
+	addr := arm.registers[rSP] + offset
+
+	if load {
+		arm.registers[reg] = arm.read32bit(addr)
+		arm.cycles += 2
+		return
+	}
+
+	arm.write32bit(addr, arm.registers[reg])
+	arm.cycles++
+}
+
+// format 12
+func (arm *ARM) thumbLoadAddress(opcode uint16) {
+	sp := opcode&0x0800 == 0x0800
+	destReg := (opcode & 0x0700) >> 8
+	offset := uint32(opcode&0x00ff) << 2
+
+	if sp {
+		arm.registers[destReg] = arm.registers[rSP] + offset
+	} else {
+		arm.registers[destReg] = (arm.registers[rPC] &^ 0x02) + offset
+	}
+}
+
+// format 13
+func (arm *ARM) thumbAddOffsetToSP(opcode uint16) {
+	offset := uint32(opcode&0x007f) << 2
+
+	if opcode&0x0080 == 0x0080 {
+		arm.registers[rSP] -= offset
+	} else {
+		arm.registers[rSP] += offset
+	}
+}
+
+// format 14
+func (arm *ARM) thumbPushPopRegisters(opcode uint16) {
+	load := opcode&0x0800 == 0x0800
+	pclr := opcode&0x0100 == 0x0100
+	rlist := opcode & 0x00ff
+
+	if load {
+		// POP
+		addr := arm.registers[rSP]
+		for i := 0; i <= 7; i++ {
+			if rlist&(0x01<<i) != 0 {
+				arm.registers[i] = arm.read32bit(addr)
+				addr += 4
+				arm.cycles++
+			}
+		}
+		arm.cycles += 2
+
+		if pclr {
+			v := arm.read32bit(addr)
+			addr += 4
+			arm.registers[rSP] = addr
+
+			// popping an even address into the program counter is handled
+			// in the same way as for the BX instruction
+			arm.interwork(v)
+			return
+		}
+
+		arm.registers[rSP] = addr
+		return
+	}
+
+	// PUSH
+	n := uint32(bits.OnesCount16(rlist))
+	if pclr {
+		n++
+	}
+
+	// lowest register is stored at the lowest address
+	addr := arm.registers[rSP] - n*4
+	arm.registers[rSP] = addr
+	for i := 0; i <= 7; i++ {
+		if rlist&(0x01<<i) != 0 {
+			arm.write32bit(addr, arm.registers[i])
+			addr += 4
+			arm.cycles++
+		}
+	}
+
+	if pclr {
+		arm.write32bit(addr, arm.registers[rLR])
+		arm.cycles++
+	}
+}
+
+// format 15
+func (arm *ARM) thumbMultipleLoadStore(opcode uint16) {
+	load := opcode&0x0800 == 0x0800
+	baseReg := (opcode & 0x0700) >> 8
+	rlist := opcode & 0x00ff
+
+	addr := arm.registers[baseReg]
+
+	if load {
+		// LDMIA
+		for i := 0; i <= 7; i++ {
+			if rlist&(0x01<<i) != 0 {
+				arm.registers[i] = arm.read32bit(addr)
+				addr += 4
+				arm.cycles++
+			}
+		}
+		arm.cycles += 2
+
+		// no writeback if the base register is in the list of registers
+		if rlist&(0x01<<baseReg) == 0 {
+			arm.registers[baseReg] = addr
+		}
+		return
+	}
+
+	// STMIA
+	for i := 0; i <= 7; i++ {
+		if rlist&(0x01<<i) != 0 {
+			arm.write32bit(addr, arm.registers[i])
+			addr += 4
+			arm.cycles++
+		}
+	}
+	arm.registers[baseReg] = addr
+}
+
+// format 16
+func (arm *ARM) thumbConditionalBranch(opcode uint16) {
+	cond := uint8((opcode & 0x0f00) >> 8)
+	offset := uint32(int32(int8(opcode&0x00ff))) << 1
+
+	// condition AL is undefined for this instruction format
+	if cond == 0b1110 {
+		arm.undefinedInstruction(opcode)
+		return
+	}
+
+	if arm.status.condition(cond) {
+		arm.branch(arm.registers[rPC] + offset)
+	}
+}
+
+// format 17
+func (arm *ARM) thumbSoftwareInterrupt(opcode uint16) {
+	// there is no operating system in the Harmony cartridge to service
+	// software interrupts
+	arm.executionError = errors.New(errors.ARMError, fmt.Sprintf("unsupported software interrupt (%02x) at %08x", opcode&0x00ff, arm.registers[rPC]-4))
+	arm.continueExecution = false
+}
+
+// format 18
+func (arm *ARM) thumbUnconditionalBranch(opcode uint16) {
+	// sign extend 11 bit offset and multiply by two
+	offset := uint32(int32(uint32(opcode&0x07ff)<<21) >> 20)
+	arm.branch(arm.registers[rPC] + offset)
+}
+
+// format 19
+func (arm *ARM) thumbLongBranchWithLink(opcode uint16) {
+	low := opcode&0x0800 == 0x0800
+	offset := uint32(opcode & 0x07ff)
+
+	if !low {
+		// first instruction. sign extend 11 bit offset and shift left by 12
+		arm.registers[rLR] = arm.registers[rPC] + uint32(int32(offset<<21)>>9)
+		return
+	}
+
+	// second instruction. the link register will point to the instruction
+	// following this one, with bit zero set to indicate thumb code
+	next := (arm.registers[rPC] - 2) | 0x01
+	arm.branch(arm.registers[rLR] + offset<<1)
+	arm.registers[rLR] = next
+}
@@ -0,0 +1,109 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+)
+
+// BankSwitch describes a change of bank in the attached cartridge.
+type BankSwitch struct {
+	// the range of cartridge addresses affected by the switch. for cartridges
+	// that switch the entire address space this will be $1000 to $1FFF
+	Origin uint16
+	Memtop uint16
+
+	OldBank int
+	NewBank int
+
+	// the address that caused the switch, as it appeared on the address bus
+	Address uint16
+}
+
+func (bs BankSwitch) String() string {
+	return fmt.Sprintf("$%04X-$%04X: bank %d -> %d (address $%04X)", bs.Origin, bs.Memtop,
+		bs.OldBank, bs.NewBank, bs.Address&memorymap.Memtop)
+}
+
+// BankSwitchListener implementations are notified of every change of bank.
+// See the SetBankSwitchListener() function.
+type BankSwitchListener interface {
+	BankSwitched(BankSwitch)
+}
+
+// bank switching is detected by checking the bank at regular intervals of the
+// cartridge address space. one kilobyte is the smallest bank size of any
+// supported format with segmented banks (parker bros)
+const bankSegmentSize = 0x0400
+
+type bankSnapshot [(memorymap.AddressMaskCart + 1) / bankSegmentSize]int
+
+func (cart Cartridge) bankSnapshot() bankSnapshot {
+	var s bankSnapshot
+	for i := range s {
+		s[i] = cart.mapper.getBank(uint16(i * bankSegmentSize))
+	}
+	return s
+}
+
+// SetBankSwitchListener sets the BankSwitchListener to be notified of bank
+// switches. A value of nil removes the current listener.
+//
+// Checking for bank switches adds overhead to every cartridge access so the
+// listener should only be set when necessary (ie. by the debugger).
+func (cart *Cartridge) SetBankSwitchListener(l BankSwitchListener) {
+	cart.bankSwitchListener = l
+}
+
+// BankSwitchListener returns the current BankSwitchListener. nil if there is
+// no listener.
+func (cart Cartridge) BankSwitchListener() BankSwitchListener {
+	return cart.bankSwitchListener
+}
+
+// notifyBankSwitch compares the current banks with the snapshot taken before
+// the access and notifies the listener of any change. neighbouring segments
+// that changed in the same way are notified as a single switch
+func (cart Cartridge) notifyBankSwitch(before bankSnapshot, addr uint16) {
+	after := cart.bankSnapshot()
+
+	for i := 0; i < len(before); i++ {
+		if before[i] == after[i] {
+			continue
+		}
+
+		j := i
+		for j+1 < len(before) && before[j+1] == before[i] && after[j+1] == after[i] {
+			j++
+		}
+
+		cart.bankSwitchListener.BankSwitched(BankSwitch{
+			Origin:  memorymap.OriginCart | uint16(i*bankSegmentSize),
+			Memtop:  memorymap.OriginCart | uint16((j+1)*bankSegmentSize-1),
+			OldBank: before[i],
+			NewBank: after[i],
+			Address: addr,
+		})
+
+		i = j
+	}
+}
@@ -63,6 +63,27 @@ type optionalSuperchip interface {
 	addSuperchip() bool
 }
 
+// optionalBusActivity is implemented by cartMappers that need to see every
+// access of the address bus. see the commentary for the BusActivity() function
+// in the Cartridge type
+type optionalBusActivity interface {
+	busActivity(addr uint16)
+}
+
+// optionalPeek is implemented by cartMappers for which reading has side effects
+// that should not happen when the cartridge is only being looked at. see the
+// Peek() function in the Cartridge type
+type optionalPeek interface {
+	peek(addr uint16) (uint8, error)
+}
+
+// optionalHotspots is implemented by cartMappers that can list the addresses
+// that cause a change of cartridge state. used by the debugger and the
+// disassembler. see the Hotspot type
+type optionalHotspots interface {
+	hotspots() []Hotspot
+}
+
 // RAMinfo details the read/write addresses for any cartridge ram
 type RAMinfo struct {
 	Label       string
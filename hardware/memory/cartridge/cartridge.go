@@ -41,6 +41,13 @@ type Cartridge struct {
 	// the specific cartridge data, mapped appropriately to the memory
 	// interfaces
 	mapper cartMapper
+
+	// the result of fingerprinting the cartridge data. the report is still
+	// prepared when the format is specified explicitly
+	fingerprintReport FingerprintReport
+
+	// notified of every bank switch. nil if nothing is listening
+	bankSwitchListener BankSwitchListener
 }
 
 // NewCartridge is the preferred method of initialisation for the cartridge
@@ -69,6 +76,9 @@ func (cart Cartridge) Format() string {
 
 // Peek is an implementation of memory.DebuggerBus. Address must be normalised.
 func (cart *Cartridge) Peek(addr uint16) (uint8, error) {
+	if pk, ok := cart.mapper.(optionalPeek); ok {
+		return pk.peek(addr ^ memorymap.OriginCart)
+	}
 	return cart.Read(addr)
 }
 
@@ -87,12 +97,26 @@ func (cart *Cartridge) Patch(offset uint16, data uint8) error {
 
 // Read is an implementation of memory.CPUBus. Address must be normalised.
 func (cart *Cartridge) Read(addr uint16) (uint8, error) {
-	return cart.mapper.read(addr ^ memorymap.OriginCart)
+	if cart.bankSwitchListener == nil {
+		return cart.mapper.read(addr ^ memorymap.OriginCart)
+	}
+
+	before := cart.bankSnapshot()
+	data, err := cart.mapper.read(addr ^ memorymap.OriginCart)
+	cart.notifyBankSwitch(before, addr)
+	return data, err
 }
 
 // Write is an implementation of memory.CPUBus. Address must be normalised.
 func (cart *Cartridge) Write(addr uint16, data uint8) error {
-	return cart.mapper.write(addr^memorymap.OriginCart, data)
+	if cart.bankSwitchListener == nil {
+		return cart.mapper.write(addr^memorymap.OriginCart, data)
+	}
+
+	before := cart.bankSnapshot()
+	err := cart.mapper.write(addr^memorymap.OriginCart, data)
+	cart.notifyBankSwitch(before, addr)
+	return err
 }
 
 // Eject removes memory from cartridge space and unlike the real hardware,
@@ -101,6 +125,7 @@ func (cart *Cartridge) Eject() {
 	cart.Filename = ejectedName
 	cart.Hash = ejectedHash
 	cart.mapper = newEjected()
+	cart.fingerprintReport = FingerprintReport{}
 }
 
 // IsEjected returns true if no cartridge is attached
@@ -119,6 +144,7 @@ func (cart *Cartridge) Attach(cartload cartridgeloader.Loader) error {
 	// note name of cartridge
 	cart.Filename = cartload.Filename
 	cart.mapper = newEjected()
+	cart.fingerprintReport = FingerprintReport{}
 
 	// generate hash
 	cart.Hash = fmt.Sprintf("%x", sha1.Sum(data))
@@ -140,51 +166,33 @@ func (cart *Cartridge) Attach(cartload cartridgeloader.Loader) error {
 
 	addSuperchip := false
 
-	switch cartload.Format {
-	case "2k":
-		cart.mapper, err = newAtari2k(data)
-	case "4k":
-		cart.mapper, err = newAtari4k(data)
-	case "F8":
-		cart.mapper, err = newAtari8k(data)
-	case "F6":
-		cart.mapper, err = newAtari16k(data)
-	case "F4":
-		cart.mapper, err = newAtari32k(data)
-
-	case "2k+SC":
-		cart.mapper, err = newAtari2k(data)
-		addSuperchip = true
-	case "4k+SC":
-		cart.mapper, err = newAtari4k(data)
-		addSuperchip = true
-	case "F8+SC":
-		cart.mapper, err = newAtari8k(data)
-		addSuperchip = true
-	case "F6+SC":
-		cart.mapper, err = newAtari16k(data)
-		addSuperchip = true
-	case "F4+SC":
-		cart.mapper, err = newAtari32k(data)
-		addSuperchip = true
-
-	case "FA":
-		cart.mapper, err = newCBS(data)
-	case "FE":
-		// !!TODO: FE cartridge format
-	case "E0":
-		cart.mapper, err = newparkerBros(data)
-	case "E7":
-		cart.mapper, err = newMnetwork(data)
-	case "3F":
-		cart.mapper, err = newTigervision(data)
-	case "AR":
-		// !!TODO: AR cartridge format
-
-	case "DPC":
-		cart.mapper, err = newDPC(data)
+	reg, ok := lookupMapper(cartload.Format)
+
+	// a superchip can be added to some formats by adding "+SC" or "SC" to
+	// the format ID
+	if !ok {
+		id := strings.TrimSuffix(cartload.Format, "SC")
+		id = strings.TrimSuffix(id, "+")
+		if id != cartload.Format {
+			reg, ok = lookupMapper(id)
+			addSuperchip = true
+		}
+	}
+
+	if !ok {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("unrecognised cartridge format (%s)", cartload.Format))
 	}
 
+	cart.fingerprintReport = FingerprintData(data)
+	cart.fingerprintReport.Specified = true
+
+	mapper, err := reg.create(data)
+	if err != nil {
+		return err
+	}
+	cart.mapper = mapper
+	cart.fingerprintReport.Chosen = reg.id
+
 	if addSuperchip {
 		if superchip, ok := cart.mapper.(optionalSuperchip); ok {
 			if !superchip.addSuperchip() {
@@ -250,7 +258,35 @@ func (cart *Cartridge) RestoreState(state interface{}) error {
 // address space. When this address is triggered, the tigervision cartridge
 // will use whatever is on the data bus to switch banks.
 func (cart Cartridge) Listen(addr uint16, data uint8) {
+	if cart.bankSwitchListener == nil {
+		cart.mapper.listen(addr, data)
+		return
+	}
+
+	before := cart.bankSnapshot()
 	cart.mapper.listen(addr, data)
+	cart.notifyBankSwitch(before, addr)
+}
+
+// BusActivity should be called for every access of the address bus,
+// regardless of which area of memory the address is mapped to. Compare to
+// Listen(), which is only concerned with writes outside of cartridge space.
+//
+// Very few cartridges care about this. The Supercharger, for example, needs to
+// count the number of address transitions between setting its data hold
+// register and the address being written to. The SB and X07 formats switch
+// banks on reads as well as writes to addresses outside of cartridge space.
+func (cart Cartridge) BusActivity(addr uint16) {
+	if m, ok := cart.mapper.(optionalBusActivity); ok {
+		if cart.bankSwitchListener == nil {
+			m.busActivity(addr)
+			return
+		}
+
+		before := cart.bankSnapshot()
+		m.busActivity(addr)
+		cart.notifyBankSwitch(before, addr)
+	}
 }
 
 // GetRAMinfo returns an instance of RAMinfo or nil if catridge contains no RAM
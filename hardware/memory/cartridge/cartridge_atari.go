@@ -57,6 +57,12 @@ import (
 // to use it- Fatal Run.  Like the F6 method, however there are 8 4K
 // banks instead of 4.  You use 1FF4 to 1FFB to select the desired bank.
 //
+// 64K:
+//
+// -EF: Not an Atari format but a homebrew extension of the F4 method. There
+// are 16 4K banks which are selected by accessing 1FE0 to 1FEF. The EFSC
+// variant adds a superchip.
+//
 //
 // Some carts have extra RAM; There are three known formats for this:
 //
@@ -343,6 +349,11 @@ func newAtari8k(data []uint8) (cartMapper, error) {
 	return cart, nil
 }
 
+// F8 cartridges select one of the two 4k banks by accessing $1FF8 or $1FF9
+func (cart atari8k) hotspots() []Hotspot {
+	return bankHotspots(0x0ff8, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
 func (cart atari8k) numBanks() int {
 	return 2
 }
@@ -410,6 +421,11 @@ func newAtari16k(data []byte) (cartMapper, error) {
 	return cart, nil
 }
 
+// F6 cartridges select one of the four 4k banks by accessing $1FF6 to $1FF9
+func (cart atari16k) hotspots() []Hotspot {
+	return bankHotspots(0x0ff6, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
 func (cart atari16k) numBanks() int {
 	return 4
 }
@@ -485,6 +501,11 @@ func newAtari32k(data []byte) (cartMapper, error) {
 	return cart, nil
 }
 
+// F4 cartridges select one of the eight 4k banks by accessing $1FF4 to $1FFB
+func (cart atari32k) hotspots() []Hotspot {
+	return bankHotspots(0x0ff4, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
 func (cart atari32k) numBanks() int {
 	return 8
 }
@@ -544,3 +565,82 @@ func (cart *atari32k) write(addr uint16, data uint8) error {
 
 	return nil
 }
+
+// fingerprint patterns taken from Stella CartDetector.cxx. it's likely that
+// the code will switch to bank 0 so that's what we look for
+var fingerprintPatternsEF = fingerprintPatterns{
+	{"NOP $FFE0", []uint8{0x0c, 0xe0, 0xff}},
+	{"LDA $FFE0", []uint8{0xad, 0xe0, 0xff}},
+	{"NOP $1FE0", []uint8{0x0c, 0xe0, 0x1f}},
+	{"LDA $1FE0", []uint8{0xad, 0xe0, 0x1f}},
+}
+
+func fingerprintEF(b []byte) bool {
+	return fingerprintPatternsEF.total(b) > 0
+}
+
+// atari64k (EF)
+// o homebrew only
+type atari64k struct {
+	atari
+}
+
+func newAtari64k(data []byte) (cartMapper, error) {
+	cart := &atari64k{}
+	cart.bankSize = 4096
+	cart.description = "homebrew 64k"
+	cart.formatID = "EF"
+	cart.banks = make([][]uint8, cart.numBanks())
+
+	if len(data) != cart.bankSize*cart.numBanks() {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, cart.bankSize)
+		offset := k * cart.bankSize
+		copy(cart.banks[k], data[offset:offset+cart.bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+// EF cartridges select one of the sixteen 4k banks by accessing $1FE0 to
+// $1FEF
+func (cart atari64k) hotspots() []Hotspot {
+	return bankHotspots(0x0fe0, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
+func (cart atari64k) numBanks() int {
+	return 16
+}
+
+func (cart *atari64k) read(addr uint16) (uint8, error) {
+	if data, ok := cart.atari.read(addr); ok {
+		return data, nil
+	}
+
+	data := cart.banks[cart.bank][addr]
+
+	if addr >= 0x0fe0 && addr <= 0x0fef {
+		cart.bank = int(addr - 0x0fe0)
+	}
+
+	return data, nil
+}
+
+func (cart *atari64k) write(addr uint16, data uint8) error {
+	if ok := cart.atari.write(addr, data); ok {
+		return nil
+	}
+
+	if addr >= 0x0fe0 && addr <= 0x0fef {
+		cart.bank = int(addr - 0x0fe0)
+	} else {
+		return errors.New(errors.BusError, addr)
+	}
+
+	return nil
+}
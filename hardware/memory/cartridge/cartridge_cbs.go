@@ -165,6 +165,13 @@ func (cart *cbs) numBanks() int {
 func (cart *cbs) listen(addr uint16, data uint8) {
 }
 
+// FA cartridges select one of the three 4k banks by accessing $1FF8 to $1FFA.
+// the RAM is accessed through fixed read and write addresses and has no
+// hotspot
+func (cart *cbs) hotspots() []Hotspot {
+	return bankHotspots(0x0ff8, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
 func (cart *cbs) poke(addr uint16, data uint8) error {
 	return errors.New(errors.UnpokeableAddress, addr)
 }
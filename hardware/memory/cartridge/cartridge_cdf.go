@@ -0,0 +1,472 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge/arm7tdmi"
+)
+
+// from the Stella source code (CartCDF.hxx):
+//
+// "Cartridge class used for CDF/CDFJ. There are seven 4K program banks, a 4K
+// Display Data RAM, 1K C Variable and Stack, and the CDF chip."
+//
+// the cartridge file is laid out as follows:
+//
+//	$0000 - $07ff	CDF driver (2K)
+//	$0800 - $0fff	custom ARM code (2K)
+//	$1000 - $7fff	seven 4K banks
+//
+// the driver is copied into the first 2K of the ARM's SRAM. the remainder of
+// the SRAM is display data. the data stream pointers and increments are kept
+// in the SRAM copy of the driver, at a location that differs for each version
+// of the CDF format
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsCDF = fingerprintPatterns{
+	{"CDF signature", []uint8("CDF")},
+}
+
+func fingerprintCDF(b []byte) bool {
+	return fingerprintPatternsCDF.total(b) >= 3
+}
+
+const (
+	cdfDriverSize = 0x0800
+	cdfBankSize   = 4096
+	cdfNumBanks   = 7
+	cdfFileSize   = 32768
+
+	cdfEntryPC = 0x00000808
+	cdfEntryLR = 0x00000800
+
+	// special data streams
+	cdfCommStream = 0x20
+	cdfJumpStream = 0x21
+)
+
+// cdfVersion describes the differences between the versions of the CDF format
+type cdfVersion struct {
+	id string
+
+	// location of data stream pointers, increments and waveforms in the SRAM
+	// copy of the driver
+	datastreamBase    uint32
+	incrementBase     uint32
+	waveformBase      uint32
+	amplitudeRegister uint8
+
+	// the addresses of the BX instructions in the driver functions used to
+	// control the music data fetchers
+	setNote     uint32
+	resetWave   uint32
+	getWavePtr  uint32
+	setWaveSize uint32
+}
+
+var cdfVersions = map[uint8]cdfVersion{
+	0x00: {id: "CDF0", datastreamBase: 0x06e0, incrementBase: 0x0768, waveformBase: 0x07f0, amplitudeRegister: 0x22,
+		setNote: 0x06da, resetWave: 0x06de, getWavePtr: 0x06e2, setWaveSize: 0x06e6},
+	0x01: {id: "CDF1", datastreamBase: 0x00a0, incrementBase: 0x0128, waveformBase: 0x01b0, amplitudeRegister: 0x22,
+		setNote: 0x0752, resetWave: 0x0756, getWavePtr: 0x075a, setWaveSize: 0x075e},
+	'J': {id: "CDFJ", datastreamBase: 0x0098, incrementBase: 0x0124, waveformBase: 0x01b0, amplitudeRegister: 0x23,
+		setNote: 0x0752, resetWave: 0x0756, getWavePtr: 0x075a, setWaveSize: 0x075e},
+}
+
+type cdf struct {
+	formatID    string
+	description string
+	version     cdfVersion
+
+	// the entire cartridge file. the ARM sees this as flash memory
+	data []uint8
+
+	banks [][]uint8
+	bank  int
+
+	// the ARM's SRAM. display is a slice of this memory
+	ram     []uint8
+	display []uint8
+
+	arm *arm7tdmi.ARM
+
+	registers cdfRegisters
+}
+
+// CDF registers are kept in their own type for the convenience of saveState()
+// and restoreState(). the data stream pointers and increments are in the
+// SRAM and are saved with it
+type cdfRegisters struct {
+	// the lower nibble of mode controls fast fetch mode, the upper nibble
+	// controls digital audio mode. both are active when the nibble is zero
+	mode uint8
+
+	// address of the operand of an LDA immediate instruction
+	ldaOperand uint16
+
+	// fast jump state. the number of operand bytes still to be read from the
+	// jump stream and the address of the next operand
+	fastJump        int
+	fastJumpOperand uint16
+
+	// music data fetchers
+	musicCounters     [3]uint32
+	musicFrequencies  [3]uint32
+	musicWaveformSize [3]uint32
+
+	beats          int
+	fractionalBeat int
+}
+
+func newCDF(data []byte) (cartMapper, error) {
+	cart := &cdf{}
+	cart.description = "CDF (Harmony)"
+
+	if len(data) != cdfFileSize {
+		return nil, errors.New(errors.CartridgeError, "CDF: wrong number of bytes in the cartridge file")
+	}
+
+	// find version of CDF driver
+	found := false
+	for i := 0; i < cdfDriverSize-3; i++ {
+		if data[i] == 'C' && data[i+1] == 'D' && data[i+2] == 'F' {
+			v, ok := cdfVersions[data[i+3]]
+			if !ok {
+				return nil, errors.New(errors.CartridgeError, fmt.Sprintf("CDF: unsupported version (%#02x)", data[i+3]))
+			}
+			cart.version = v
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New(errors.CartridgeError, "CDF: cannot find version of driver")
+	}
+
+	cart.formatID = cart.version.id
+
+	cart.data = make([]uint8, len(data))
+	copy(cart.data, data)
+
+	cart.banks = make([][]uint8, cdfNumBanks)
+	for k := 0; k < cdfNumBanks; k++ {
+		offset := cdfDriverSize*2 + k*cdfBankSize
+		cart.banks[k] = cart.data[offset : offset+cdfBankSize]
+	}
+
+	cart.ram = make([]uint8, 8192)
+	cart.display = cart.ram[cdfDriverSize:]
+
+	cart.arm = arm7tdmi.NewARM(cart.data, cart.ram, cdfEntryPC, cdfEntryLR, cart)
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart cdf) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart cdf) format() string {
+	return cart.formatID
+}
+
+func (cart *cdf) initialise() {
+	for i := range cart.ram {
+		cart.ram[i] = 0
+	}
+	copy(cart.ram, cart.data[:cdfDriverSize])
+
+	for i := range cart.registers.musicCounters {
+		cart.registers.musicCounters[i] = 0
+		cart.registers.musicFrequencies[i] = 0
+		cart.registers.musicWaveformSize[i] = 27
+	}
+
+	cart.registers.mode = 0xff
+	cart.registers.ldaOperand = 0
+	cart.registers.fastJump = 0
+	cart.registers.fastJumpOperand = 0
+	cart.registers.beats = 0
+	cart.registers.fractionalBeat = 0
+
+	cart.bank = len(cart.banks) - 1
+}
+
+func (cart cdf) fastFetch() bool {
+	return cart.registers.mode&0x0f == 0
+}
+
+func (cart cdf) digitalAudio() bool {
+	return cart.registers.mode&0xf0 == 0
+}
+
+func (cart *cdf) read(addr uint16) (uint8, error) {
+	data := cart.banks[cart.bank][addr]
+
+	// operand of a fast jump is read from the jump stream
+	if cart.registers.fastJump > 0 && cart.registers.fastJumpOperand == addr {
+		cart.registers.fastJump--
+		cart.registers.fastJumpOperand++
+		return cart.readStreamByte(cdfJumpStream, 1), nil
+	}
+
+	// JMP $0000 activates fast jump
+	if cart.fastFetch() && data == 0x4c && addr < cdfBankSize-2 &&
+		cart.banks[cart.bank][addr+1] == 0 && cart.banks[cart.bank][addr+2] == 0 {
+		cart.registers.fastJump = 2
+		cart.registers.fastJumpOperand = addr + 1
+		return data, nil
+	}
+	cart.registers.fastJump = 0
+
+	// operand of an LDA immediate instruction selects a data stream
+	if cart.fastFetch() && cart.registers.ldaOperand != 0 && cart.registers.ldaOperand == addr && data <= cart.version.amplitudeRegister {
+		cart.registers.ldaOperand = 0
+		if data == cart.version.amplitudeRegister {
+			return cart.amplitude(), nil
+		}
+		return cart.readDatastream(data), nil
+	}
+	cart.registers.ldaOperand = 0
+
+	cart.hotspot(addr)
+
+	if cart.fastFetch() && data == 0xa9 {
+		cart.registers.ldaOperand = addr + 1
+	}
+
+	return data, nil
+}
+
+func (cart *cdf) write(addr uint16, data uint8) error {
+	switch addr {
+	case 0x0ff0:
+		// DSWRITE
+		p := cart.datastreamPointer(cdfCommStream)
+		cart.display[(p>>20)%uint32(len(cart.display))] = data
+		cart.setDatastreamPointer(cdfCommStream, p+0x100000)
+
+	case 0x0ff1:
+		// DSPTR
+		p := cart.datastreamPointer(cdfCommStream)
+		p = ((p << 8) & 0xf0000000) | uint32(data)<<20
+		cart.setDatastreamPointer(cdfCommStream, p)
+
+	case 0x0ff2:
+		// SETMODE
+		cart.registers.mode = data
+
+	case 0x0ff3:
+		// CALLFN
+		if data == 254 || data == 255 {
+			_, err := cart.arm.Run()
+			if err != nil {
+				return errors.New(errors.CartridgeError, fmt.Sprintf("%s: %v", cart.formatID, err))
+			}
+		}
+
+	default:
+		if !cart.hotspot(addr) {
+			return errors.New(errors.BusError, addr)
+		}
+	}
+
+	return nil
+}
+
+// hotspot switches bank if the address is a hotspot address. returns true if
+// a bank switch has occurred
+func (cart *cdf) hotspot(addr uint16) bool {
+	if addr >= 0x0ff5 && addr <= 0x0ffb {
+		cart.bank = int(addr - 0x0ff5)
+		return true
+	}
+	return false
+}
+
+func (cart *cdf) read32(addr uint32) uint32 {
+	return uint32(cart.ram[addr]) | uint32(cart.ram[addr+1])<<8 | uint32(cart.ram[addr+2])<<16 | uint32(cart.ram[addr+3])<<24
+}
+
+func (cart *cdf) write32(addr uint32, val uint32) {
+	cart.ram[addr] = uint8(val)
+	cart.ram[addr+1] = uint8(val >> 8)
+	cart.ram[addr+2] = uint8(val >> 16)
+	cart.ram[addr+3] = uint8(val >> 24)
+}
+
+// data stream pointers are stored as PPPFF--- and increments as ----IIFF,
+// where P is the pointer, I is the increment and F is the fractional part
+func (cart *cdf) datastreamPointer(stream uint8) uint32 {
+	return cart.read32(cart.version.datastreamBase + uint32(stream)*4)
+}
+
+func (cart *cdf) setDatastreamPointer(stream uint8, p uint32) {
+	cart.write32(cart.version.datastreamBase+uint32(stream)*4, p)
+}
+
+func (cart *cdf) datastreamIncrement(stream uint8) uint32 {
+	return cart.read32(cart.version.incrementBase+uint32(stream)*4) & 0xffff
+}
+
+func (cart *cdf) readStreamByte(stream uint8, inc uint32) uint8 {
+	p := cart.datastreamPointer(stream)
+	data := cart.display[(p>>20)%uint32(len(cart.display))]
+	cart.setDatastreamPointer(stream, p+inc<<20)
+	return data
+}
+
+func (cart *cdf) readDatastream(stream uint8) uint8 {
+	p := cart.datastreamPointer(stream)
+	data := cart.display[(p>>20)%uint32(len(cart.display))]
+	cart.setDatastreamPointer(stream, p+cart.datastreamIncrement(stream)<<12)
+	return data
+}
+
+// waveform addresses are stored as ARM addresses
+func (cart *cdf) waveform(i int) uint32 {
+	return cart.read32(cart.version.waveformBase+uint32(i)*4) - (arm7tdmi.SRAMOrigin + cdfDriverSize)
+}
+
+func (cart *cdf) amplitude() uint8 {
+	cart.updateMusic()
+
+	if cart.digitalAudio() {
+		// the address of the sample is stored in the first waveform slot.
+		// samples are packed two to a byte
+		addr := cart.read32(cart.version.waveformBase) + (cart.registers.musicCounters[0] >> 21)
+
+		var data uint8
+		if addr < uint32(len(cart.data)) {
+			data = cart.data[addr]
+		} else if addr >= arm7tdmi.SRAMOrigin && addr-arm7tdmi.SRAMOrigin < uint32(len(cart.ram)) {
+			data = cart.ram[addr-arm7tdmi.SRAMOrigin]
+		}
+
+		if cart.registers.musicCounters[0]&(1<<20) == 0 {
+			data >>= 4
+		}
+		return data & 0x0f
+	}
+
+	var v uint32
+	for i := range cart.registers.musicCounters {
+		a := cart.waveform(i) + (cart.registers.musicCounters[i] >> cart.registers.musicWaveformSize[i])
+		v += uint32(cart.display[a%uint32(len(cart.display))])
+	}
+	return uint8(v)
+}
+
+func (cart *cdf) updateMusic() {
+	t := cart.registers.beats*harmonyMusicClock + cart.registers.fractionalBeat
+	clocks := uint32(t / harmonyVCSClock)
+	cart.registers.fractionalBeat = t % harmonyVCSClock
+	cart.registers.beats = 0
+
+	for i := range cart.registers.musicCounters {
+		cart.registers.musicCounters[i] += cart.registers.musicFrequencies[i] * clocks
+	}
+}
+
+// ARMinterrupt implements the arm7tdmi.CartridgeHook interface. the CDF driver
+// contains functions, written in 32bit ARM code, that control the music data
+// fetchers.
+func (cart *cdf) ARMinterrupt(addr uint32, r2 uint32, r3 uint32) (arm7tdmi.ARMinterruptReturn, error) {
+	var r arm7tdmi.ARMinterruptReturn
+
+	switch addr {
+	case cart.version.setNote:
+		cart.registers.musicFrequencies[r2%3] = r3
+		r.InterruptServiced = true
+	case cart.version.resetWave:
+		cart.registers.musicCounters[r2%3] = 0
+		r.InterruptServiced = true
+	case cart.version.getWavePtr:
+		r.SaveValue = cart.registers.musicCounters[r2%3]
+		r.SaveRegister = 2
+		r.SaveResult = true
+		r.InterruptServiced = true
+	case cart.version.setWaveSize:
+		cart.registers.musicWaveformSize[r2%3] = r3
+		r.InterruptServiced = true
+	}
+
+	return r, nil
+}
+
+func (cart cdf) numBanks() int {
+	return cdfNumBanks
+}
+
+func (cart *cdf) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart cdf) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *cdf) saveState() interface{} {
+	ram := make([]uint8, len(cart.ram))
+	copy(ram, cart.ram)
+	return []interface{}{cart.bank, cart.registers, ram, cart.arm.SaveState()}
+}
+
+func (cart *cdf) restoreState(state interface{}) error {
+	cart.bank = state.([]interface{})[0].(int)
+	cart.registers = state.([]interface{})[1].(cdfRegisters)
+	copy(cart.ram, state.([]interface{})[2].([]uint8))
+	cart.arm.RestoreState(state.([]interface{})[3])
+	return nil
+}
+
+func (cart *cdf) listen(addr uint16, data uint8) {
+}
+
+// only the bank switching addresses ($1FF5 to $1FFB) are reported. the
+// datastream registers do not change the memory map
+func (cart cdf) hotspots() []Hotspot {
+	return bankHotspots(0x0ff5, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
+func (cart *cdf) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *cdf) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.description)
+}
+
+func (cart cdf) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *cdf) step() {
+	cart.registers.beats++
+}
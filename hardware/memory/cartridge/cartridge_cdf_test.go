@@ -0,0 +1,71 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge_test
+
+import (
+	"testing"
+)
+
+func TestCDFState(t *testing.T) {
+	data := make([]uint8, 0x8000)
+
+	// version zero of the driver
+	copy(data[0x0010:], []uint8("CDF\x00"))
+
+	// LDA #<comm stream> at the start of the last bank, which is selected at
+	// power on
+	copy(data[0x7000:], []uint8{0xa9, 0x20})
+
+	cart := attachData(t, data, "CDF")
+
+	// SETMODE (fast fetch). the first two bytes of display data are written
+	// with DSWRITE and DSPTR is set to zero again
+	for _, w := range [][2]uint16{
+		{0x1ff2, 0x00},
+		{0x1ff1, 0x00}, {0x1ff1, 0x00},
+		{0x1ff0, 0x11}, {0x1ff0, 0x22},
+		{0x1ff1, 0x00}, {0x1ff1, 0x00},
+	} {
+		if err := cart.Write(w[0], uint8(w[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// DSWRITE changes the display data and the comm stream pointer
+	state := cart.SaveState()
+	if err := cart.Write(0x1ff0, 0x42); err != nil {
+		t.Fatal(err)
+	}
+	if err := cart.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	// read the comm stream with the fast fetch LDA
+	if _, err := cart.Read(0x1000); err != nil {
+		t.Fatal(err)
+	}
+	v, err := cart.Read(0x1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 0x11 {
+		t.Errorf("unexpected display data after restoring state (%#02x instead of 0x11)", v)
+	}
+}
@@ -0,0 +1,175 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from bankswitch_sizes.txt:
+//
+// -CV: Commavid. This cart has 2K of ROM and 1K of RAM. The ROM is mapped
+// into 1800-1FFF. The RAM is read through 1000-13FF and written through
+// 1400-17FF.
+//
+// from the Stella source code (CartCV.hxx):
+//
+// Some 4K images contain the initial contents of the RAM in the first 1K of
+// the image. The ROM is the last 2K of the image. This is useful for
+// Magicard program listings.
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsCommaVid = fingerprintPatterns{
+	{"STA $F3FF,X", []uint8{0x9d, 0xff, 0xf3}},
+	{"STA $F400,Y", []uint8{0x99, 0x00, 0xf4}},
+}
+
+func fingerprintCommaVid(b []byte) bool {
+	return fingerprintPatternsCommaVid.total(b) > 0
+}
+
+type commavid struct {
+	formatID    string
+	description string
+
+	rom []uint8
+
+	// ram and the initial state of the ram. the initial state is only
+	// non-zero for 4k images
+	ram        []uint8
+	initialRAM []uint8
+
+	// ram details
+	ramInfo []RAMinfo
+}
+
+func newCommaVid(data []byte) (cartMapper, error) {
+	const romSize = 2048
+	const ramSize = 1024
+
+	cart := &commavid{}
+	cart.description = "commavid"
+	cart.formatID = "CV"
+	cart.rom = make([]uint8, romSize)
+	cart.ram = make([]uint8, ramSize)
+	cart.initialRAM = make([]uint8, ramSize)
+
+	switch len(data) {
+	case romSize:
+		copy(cart.rom, data)
+	case romSize * 2:
+		copy(cart.initialRAM, data[:ramSize])
+		copy(cart.rom, data[romSize:])
+	default:
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	cart.ramInfo = make([]RAMinfo, 1)
+	cart.ramInfo[0] = RAMinfo{
+		Label:       "CommaVid",
+		Active:      true,
+		ReadOrigin:  0x1000,
+		ReadMemtop:  0x13ff,
+		WriteOrigin: 0x1400,
+		WriteMemtop: 0x17ff,
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart commavid) String() string {
+	return fmt.Sprintf("%s [%s]", cart.description, cart.formatID)
+}
+
+func (cart commavid) format() string {
+	return cart.formatID
+}
+
+func (cart *commavid) initialise() {
+	copy(cart.ram, cart.initialRAM)
+}
+
+func (cart *commavid) read(addr uint16) (uint8, error) {
+	if addr >= 0x0000 && addr <= 0x03ff {
+		return cart.ram[addr], nil
+	} else if addr >= 0x0800 && addr <= 0x0fff {
+		return cart.rom[addr&0x07ff], nil
+	}
+
+	// reading from the write addresses returns nothing meaningful
+	return 0, nil
+}
+
+func (cart *commavid) write(addr uint16, data uint8) error {
+	if addr >= 0x0400 && addr <= 0x07ff {
+		cart.ram[addr&0x03ff] = data
+		return nil
+	}
+
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart commavid) numBanks() int {
+	return 1
+}
+
+func (cart commavid) getBank(addr uint16) int {
+	return 0
+}
+
+func (cart *commavid) setBank(addr uint16, bank int) error {
+	if bank != 0 {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	return nil
+}
+
+func (cart *commavid) saveState() interface{} {
+	ram := make([]uint8, len(cart.ram))
+	copy(ram, cart.ram)
+	return ram
+}
+
+func (cart *commavid) restoreState(state interface{}) error {
+	copy(cart.ram, state.([]uint8))
+	return nil
+}
+
+func (cart *commavid) listen(addr uint16, data uint8) {
+}
+
+func (cart *commavid) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *commavid) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart commavid) getRAMinfo() []RAMinfo {
+	return cart.ramInfo
+}
+
+func (cart *commavid) step() {
+}
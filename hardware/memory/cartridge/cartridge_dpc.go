@@ -317,6 +317,13 @@ func (cart *dpc) restoreState(state interface{}) error {
 func (cart *dpc) listen(addr uint16, data uint8) {
 }
 
+// the DPC chip's data fetcher registers are not reported. only the two bank
+// switching addresses ($1FF8 and $1FF9) change what the CPU sees at a given
+// address
+func (cart dpc) hotspots() []Hotspot {
+	return bankHotspots(0x0ff8, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
 func (cart *dpc) poke(addr uint16, data uint8) error {
 	return errors.New(errors.UnpokeableAddress, addr)
 }
@@ -0,0 +1,532 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge/arm7tdmi"
+)
+
+// from the Stella source code (CartDPCPlus.hxx):
+//
+// "This is the cartridge class for Fred Quimby's DPC+ bankswitching scheme.
+// It is based on the Pitfall II DPC scheme. DPC+ adds 8 fractional data
+// fetchers, an ARM processor and up to 24K of ROM in six 4K banks."
+//
+// the cartridge file is laid out as follows:
+//
+//	$0000 - $0bff	Harmony driver (3K)
+//	$0c00 - $6bff	six 4K banks (custom ARM code starts in the first bank)
+//	$6c00 - $7bff	display data (4K)
+//	$7c00 - $7fff	frequency table (1K)
+//
+// the display data and frequency table are copied into the ARM's SRAM, where
+// they can be modified by both the ARM and the 6507
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsDPCplus = fingerprintPatterns{
+	{"DPC+ signature", []uint8("DPC+")},
+}
+
+func fingerprintDPCplus(b []byte) bool {
+	return fingerprintPatternsDPCplus.total(b) >= 2
+}
+
+const (
+	dpcPlusDriverSize  = 0x0c00
+	dpcPlusBankSize    = 4096
+	dpcPlusNumBanks    = 6
+	dpcPlusDisplaySize = 0x1000
+	dpcPlusFreqSize    = 0x0400
+	dpcPlusFileSize    = dpcPlusDriverSize + dpcPlusBankSize*dpcPlusNumBanks + dpcPlusDisplaySize + dpcPlusFreqSize
+
+	// the ARM begins execution after the eight byte header at the start of
+	// the custom code. returning to the link register address hands control
+	// back to the driver
+	dpcPlusEntryPC = 0x00000c08
+	dpcPlusEntryLR = 0x00000c00
+)
+
+// the music data fetchers in Harmony cartridges are clocked at 20KHz. the
+// number of clocks is calculated from the number of VCS cycles (NTSC rate)
+// that have elapsed since the previous update
+const (
+	harmonyMusicClock = 20000
+	harmonyVCSClock   = 1193182
+)
+
+type dpcPlus struct {
+	formatID    string
+	description string
+
+	// the entire cartridge file. the ARM sees this as flash memory
+	data []uint8
+
+	banks [][]uint8
+	bank  int
+
+	// the ARM's SRAM. display and frequency are slices of this memory
+	ram       []uint8
+	display   []uint8
+	frequency []uint8
+
+	arm *arm7tdmi.ARM
+
+	registers dpcPlusRegisters
+}
+
+// DPC+ registers are kept in their own type for the convenience of
+// saveState() and restoreState()
+type dpcPlusRegisters struct {
+	// data fetchers
+	counters            [8]uint16
+	fractionalCounters  [8]uint32
+	fractionalIncrement [8]uint8
+	tops                [8]uint8
+	bottoms             [8]uint8
+
+	// fast fetch mode and whether the previous byte read was an LDA immediate
+	// opcode
+	fastFetch    bool
+	ldaImmediate bool
+
+	// parameters for CALLFN
+	parameters       [8]uint8
+	parameterPointer int
+
+	rng uint32
+
+	// music data fetchers
+	musicCounters    [3]uint32
+	musicFrequencies [3]uint32
+	musicWaveforms   [3]uint8
+
+	// number of VCS cycles since the music data fetchers were last updated
+	// and the fractional part of the music clock
+	beats          int
+	fractionalBeat int
+}
+
+func newDPCplus(data []byte) (cartMapper, error) {
+	cart := &dpcPlus{}
+	cart.formatID = "DPC+"
+	cart.description = "DPC+ (Harmony)"
+
+	// some DPC+ files do not include the Harmony driver. we don't need the
+	// driver to run the cartridge so a blank area is created in its place
+	if len(data) == dpcPlusFileSize-dpcPlusDriverSize {
+		data = append(make([]uint8, dpcPlusDriverSize), data...)
+	}
+
+	if len(data) != dpcPlusFileSize {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	cart.data = make([]uint8, len(data))
+	copy(cart.data, data)
+
+	cart.banks = make([][]uint8, dpcPlusNumBanks)
+	for k := 0; k < dpcPlusNumBanks; k++ {
+		offset := dpcPlusDriverSize + k*dpcPlusBankSize
+		cart.banks[k] = cart.data[offset : offset+dpcPlusBankSize]
+	}
+
+	cart.ram = make([]uint8, 8192)
+	cart.display = cart.ram[dpcPlusDriverSize : dpcPlusDriverSize+dpcPlusDisplaySize]
+	cart.frequency = cart.ram[dpcPlusDriverSize+dpcPlusDisplaySize : dpcPlusDriverSize+dpcPlusDisplaySize+dpcPlusFreqSize]
+
+	cart.arm = arm7tdmi.NewARM(cart.data, cart.ram, dpcPlusEntryPC, dpcPlusEntryLR, cart)
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart dpcPlus) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart dpcPlus) format() string {
+	return cart.formatID
+}
+
+func (cart *dpcPlus) initialise() {
+	for i := range cart.ram {
+		cart.ram[i] = 0
+	}
+
+	// copy driver, display data and frequency table into SRAM
+	copy(cart.ram, cart.data[:dpcPlusDriverSize])
+	copy(cart.display, cart.data[dpcPlusDriverSize+dpcPlusBankSize*dpcPlusNumBanks:])
+
+	for i := range cart.registers.counters {
+		cart.registers.counters[i] = 0
+		cart.registers.fractionalCounters[i] = 0
+		cart.registers.fractionalIncrement[i] = 0
+		cart.registers.tops[i] = 0
+		cart.registers.bottoms[i] = 0
+	}
+
+	for i := range cart.registers.musicCounters {
+		cart.registers.musicCounters[i] = 0
+		cart.registers.musicFrequencies[i] = 0
+		cart.registers.musicWaveforms[i] = 0
+	}
+
+	cart.registers.fastFetch = false
+	cart.registers.ldaImmediate = false
+	cart.registers.parameterPointer = 0
+	cart.registers.rng = 0x2b435044
+	cart.registers.beats = 0
+	cart.registers.fractionalBeat = 0
+
+	cart.bank = len(cart.banks) - 1
+}
+
+func (cart *dpcPlus) read(addr uint16) (uint8, error) {
+	data := cart.banks[cart.bank][addr]
+
+	// in fast fetch mode, the operand of an LDA immediate instruction is
+	// treated as the address of a register
+	if cart.registers.fastFetch && cart.registers.ldaImmediate && data < 0x28 {
+		addr = uint16(data)
+	}
+	cart.registers.ldaImmediate = false
+
+	if addr < 0x0028 {
+		return cart.readRegister(addr), nil
+	}
+
+	if cart.hotspot(addr) {
+		return data, nil
+	}
+
+	if cart.registers.fastFetch {
+		cart.registers.ldaImmediate = data == 0xa9
+	}
+
+	return data, nil
+}
+
+func (cart *dpcPlus) readRegister(addr uint16) uint8 {
+	var data uint8
+
+	// the fetcher being accessed and the function
+	f := addr & 0x0007
+	function := (addr >> 3) & 0x0007
+
+	// flag for the data fetcher
+	var flag uint8
+	if (cart.registers.tops[f]-uint8(cart.registers.counters[f]&0x00ff))&0xff > (cart.registers.tops[f]-cart.registers.bottoms[f])&0xff {
+		flag = 0xff
+	}
+
+	switch function {
+	case 0x00:
+		switch f {
+		case 0x00:
+			// RANDOM0NEXT
+			cart.clockRNG()
+			data = uint8(cart.registers.rng)
+		case 0x01:
+			// RANDOM0PRIOR
+			cart.priorRNG()
+			data = uint8(cart.registers.rng)
+		case 0x02:
+			// RANDOM1
+			data = uint8(cart.registers.rng >> 8)
+		case 0x03:
+			// RANDOM2
+			data = uint8(cart.registers.rng >> 16)
+		case 0x04:
+			// RANDOM3
+			data = uint8(cart.registers.rng >> 24)
+		case 0x05:
+			// AMPLITUDE
+			cart.updateMusic()
+			var v uint32
+			for i := range cart.registers.musicWaveforms {
+				v += uint32(cart.display[(uint32(cart.registers.musicWaveforms[i])<<5)+(cart.registers.musicCounters[i]>>27)])
+			}
+			data = uint8(v)
+		}
+
+	case 0x01:
+		// DFxDATA
+		data = cart.display[cart.registers.counters[f]]
+		cart.registers.counters[f] = (cart.registers.counters[f] + 1) & 0x0fff
+
+	case 0x02:
+		// DFxDATAW (windowed)
+		data = cart.display[cart.registers.counters[f]] & flag
+		cart.registers.counters[f] = (cart.registers.counters[f] + 1) & 0x0fff
+
+	case 0x03:
+		// DFxFRACDATA
+		data = cart.display[cart.registers.fractionalCounters[f]>>8]
+		cart.registers.fractionalCounters[f] = (cart.registers.fractionalCounters[f] + uint32(cart.registers.fractionalIncrement[f])) & 0x0fffff
+
+	case 0x04:
+		// DFxFLAG (only the first four data fetchers)
+		if f <= 0x03 {
+			data = flag
+		}
+	}
+
+	return data
+}
+
+func (cart *dpcPlus) write(addr uint16, data uint8) error {
+	if addr < 0x0028 || addr >= 0x0080 {
+		if cart.hotspot(addr) {
+			return nil
+		}
+		return errors.New(errors.BusError, addr)
+	}
+
+	f := addr & 0x0007
+	function := ((addr - 0x28) >> 3) & 0x000f
+
+	switch function {
+	case 0x00:
+		// DFxFRACLOW
+		cart.registers.fractionalCounters[f] = (cart.registers.fractionalCounters[f] & 0x0f0000) | uint32(data)<<8
+	case 0x01:
+		// DFxFRACHI
+		cart.registers.fractionalCounters[f] = (uint32(data)&0x0f)<<16 | (cart.registers.fractionalCounters[f] & 0x00ffff)
+	case 0x02:
+		// DFxFRACINC
+		cart.registers.fractionalIncrement[f] = data
+		cart.registers.fractionalCounters[f] &= 0x0fff00
+	case 0x03:
+		// DFxTOP
+		cart.registers.tops[f] = data
+	case 0x04:
+		// DFxBOT
+		cart.registers.bottoms[f] = data
+	case 0x05:
+		// DFxLOW
+		cart.registers.counters[f] = (cart.registers.counters[f] & 0x0f00) | uint16(data)
+	case 0x06:
+		switch f {
+		case 0x00:
+			// FASTFETCH
+			cart.registers.fastFetch = data == 0
+		case 0x01:
+			// PARAMETER
+			if cart.registers.parameterPointer < len(cart.registers.parameters) {
+				cart.registers.parameters[cart.registers.parameterPointer] = data
+				cart.registers.parameterPointer++
+			}
+		case 0x02:
+			// CALLFN
+			return cart.callFunction(data)
+		case 0x05, 0x06, 0x07:
+			// WAVEFORMx
+			cart.registers.musicWaveforms[f-5] = data & 0x7f
+		}
+	case 0x07:
+		// DFxPUSH
+		cart.registers.counters[f] = (cart.registers.counters[f] - 1) & 0x0fff
+		cart.display[cart.registers.counters[f]] = data
+	case 0x08:
+		// DFxHI
+		cart.registers.counters[f] = (uint16(data)&0x0f)<<8 | (cart.registers.counters[f] & 0x00ff)
+	case 0x09:
+		switch f {
+		case 0x00:
+			// RRESET
+			cart.registers.rng = 0x2b435044
+		case 0x01:
+			// RWRITE0
+			cart.registers.rng = (cart.registers.rng & 0xffffff00) | uint32(data)
+		case 0x02:
+			// RWRITE1
+			cart.registers.rng = (cart.registers.rng & 0xffff00ff) | uint32(data)<<8
+		case 0x03:
+			// RWRITE2
+			cart.registers.rng = (cart.registers.rng & 0xff00ffff) | uint32(data)<<16
+		case 0x04:
+			// RWRITE3
+			cart.registers.rng = (cart.registers.rng & 0x00ffffff) | uint32(data)<<24
+		case 0x05, 0x06, 0x07:
+			// NOTEx
+			i := uint32(data) << 2
+			cart.registers.musicFrequencies[f-5] = uint32(cart.frequency[i]) |
+				uint32(cart.frequency[i+1])<<8 |
+				uint32(cart.frequency[i+2])<<16 |
+				uint32(cart.frequency[i+3])<<24
+		}
+	case 0x0a:
+		// DFxWRITE
+		cart.display[cart.registers.counters[f]] = data
+		cart.registers.counters[f] = (cart.registers.counters[f] + 1) & 0x0fff
+	}
+
+	return nil
+}
+
+// hotspot switches bank if the address is a hotspot address. returns true if
+// a bank switch has occurred
+func (cart *dpcPlus) hotspot(addr uint16) bool {
+	if addr >= 0x0ff6 && addr <= 0x0ffb {
+		cart.bank = int(addr - 0x0ff6)
+		return true
+	}
+	return false
+}
+
+func (cart *dpcPlus) callFunction(data uint8) error {
+	switch data {
+	case 0:
+		// reset parameter pointer
+		cart.registers.parameterPointer = 0
+
+	case 1:
+		// copy ROM to fetcher
+		romAddr := int(cart.registers.parameters[1])<<8 | int(cart.registers.parameters[0])
+		f := cart.registers.parameters[2] & 0x07
+		for i := 0; i < int(cart.registers.parameters[3]); i++ {
+			if romAddr+i >= dpcPlusBankSize*dpcPlusNumBanks {
+				break
+			}
+			cart.display[(int(cart.registers.counters[f])+i)&0x0fff] = cart.data[dpcPlusDriverSize+romAddr+i]
+		}
+		cart.registers.parameterPointer = 0
+
+	case 2:
+		// copy value to fetcher
+		f := cart.registers.parameters[2] & 0x07
+		for i := 0; i < int(cart.registers.parameters[3]); i++ {
+			cart.display[(int(cart.registers.counters[f])+i)&0x0fff] = cart.registers.parameters[0]
+		}
+		cart.registers.parameterPointer = 0
+
+	case 254:
+		// call ARM code with IRQ driven audio. the ARM program is considered
+		// to execute instantaneously so there is no difference between this
+		// and function 255
+		fallthrough
+
+	case 255:
+		// call ARM code
+		_, err := cart.arm.Run()
+		if err != nil {
+			return errors.New(errors.CartridgeError, fmt.Sprintf("%s: %v", cart.formatID, err))
+		}
+	}
+
+	return nil
+}
+
+// ARMinterrupt implements the arm7tdmi.CartridgeHook interface. there are no
+// driver functions in DPC+ cartridges available to the ARM program
+func (cart *dpcPlus) ARMinterrupt(addr uint32, r2 uint32, r3 uint32) (arm7tdmi.ARMinterruptReturn, error) {
+	return arm7tdmi.ARMinterruptReturn{}, nil
+}
+
+func (cart *dpcPlus) clockRNG() {
+	// 32bit LFSR
+	var v uint32
+	if cart.registers.rng&(1<<10) != 0 {
+		v = 0x10adab1e
+	}
+	cart.registers.rng = v ^ ((cart.registers.rng >> 11) | (cart.registers.rng << 21))
+}
+
+func (cart *dpcPlus) priorRNG() {
+	// 32bit LFSR reversed
+	if cart.registers.rng&(1<<31) != 0 {
+		v := 0x10adab1e ^ cart.registers.rng
+		cart.registers.rng = (v << 11) | (v >> 21)
+	} else {
+		cart.registers.rng = (cart.registers.rng << 11) | (cart.registers.rng >> 21)
+	}
+}
+
+func (cart *dpcPlus) updateMusic() {
+	t := cart.registers.beats*harmonyMusicClock + cart.registers.fractionalBeat
+	clocks := uint32(t / harmonyVCSClock)
+	cart.registers.fractionalBeat = t % harmonyVCSClock
+	cart.registers.beats = 0
+
+	for i := range cart.registers.musicCounters {
+		cart.registers.musicCounters[i] += cart.registers.musicFrequencies[i] * clocks
+	}
+}
+
+func (cart dpcPlus) numBanks() int {
+	return dpcPlusNumBanks
+}
+
+func (cart *dpcPlus) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart dpcPlus) getBank(addr uint16) int {
+	return cart.bank
+}
+
+// the display data and frequency table are part of the SRAM, which is saved
+// in its entirety. the cartridge data is never written to and is not saved
+func (cart *dpcPlus) saveState() interface{} {
+	ram := make([]uint8, len(cart.ram))
+	copy(ram, cart.ram)
+	return []interface{}{cart.bank, cart.registers, ram, cart.arm.SaveState()}
+}
+
+func (cart *dpcPlus) restoreState(state interface{}) error {
+	cart.bank = state.([]interface{})[0].(int)
+	cart.registers = state.([]interface{})[1].(dpcPlusRegisters)
+	copy(cart.ram, state.([]interface{})[2].([]uint8))
+	cart.arm.RestoreState(state.([]interface{})[3])
+	return nil
+}
+
+func (cart *dpcPlus) listen(addr uint16, data uint8) {
+}
+
+// DPC+ banks are selected by accessing $1FF6 to $1FFB. as with DPC, the data
+// fetcher registers are not reported
+func (cart dpcPlus) hotspots() []Hotspot {
+	return bankHotspots(0x0ff6, cart.numBanks(), HotspotReadWrite, "bank %d")
+}
+
+func (cart *dpcPlus) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *dpcPlus) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.description)
+}
+
+func (cart dpcPlus) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *dpcPlus) step() {
+	cart.registers.beats++
+}
@@ -0,0 +1,90 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+)
+
+// attachData writes the cartridge data to a temporary file and attaches it
+// to a new cartridge using the specified format
+func attachData(t *testing.T, data []uint8, format string) *cartridge.Cartridge {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "cartridge*.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	cart := cartridge.NewCartridge()
+	if err := cart.Attach(cartridgeloader.Loader{Filename: f.Name(), Format: format}); err != nil {
+		t.Fatal(err)
+	}
+
+	return cart
+}
+
+func TestDPCplusState(t *testing.T) {
+	// 32k cartridge file including the driver. the display data follows the
+	// six banks
+	data := make([]uint8, 0x8000)
+	copy(data[0x6c00:], []uint8{0x11, 0x22})
+
+	cart := attachData(t, data, "DPC+")
+
+	// DF0LOW and DF0HI
+	if err := cart.Write(0x1050, 0x00); err != nil {
+		t.Fatal(err)
+	}
+	if err := cart.Write(0x1068, 0x00); err != nil {
+		t.Fatal(err)
+	}
+
+	// DF0WRITE changes the display data and the data fetcher counter
+	state := cart.SaveState()
+	if err := cart.Write(0x1078, 0x42); err != nil {
+		t.Fatal(err)
+	}
+	if err := cart.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+
+	// DF0DATA
+	for _, expected := range []uint8{0x11, 0x22} {
+		v, err := cart.Read(0x1008)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != expected {
+			t.Errorf("unexpected display data after restoring state (%#02x instead of %#02x)", v, expected)
+		}
+	}
+}
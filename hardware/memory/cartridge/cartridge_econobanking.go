@@ -0,0 +1,164 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (Cart0840.hxx):
+//
+// -0840: Econobanking. 8K split into two 4K banks. Accessing 0800 selects the
+// first bank and accessing 0840 selects the second bank. The hotspots are
+// outside of cartridge space and are triggered by both reads and writes.
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsEconobanking = fingerprintPatterns{
+	{"LDA $0800", []uint8{0xad, 0x00, 0x08}},
+	{"LDA $0840", []uint8{0xad, 0x40, 0x08}},
+	{"BIT $0800", []uint8{0x2c, 0x00, 0x08}},
+	{"NOP $0800; JMP", []uint8{0x0c, 0x00, 0x08, 0x4c}},
+	{"NOP $0FFF; JMP", []uint8{0x0c, 0xff, 0x0f, 0x4c}},
+}
+
+func fingerprintEconobanking(b []byte) bool {
+	// each pattern must appear at least twice
+	return fingerprintPatternsEconobanking.most(b) >= 2
+}
+
+type econobanking struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// identifies the currently selected bank
+	bank int
+}
+
+func newEconobanking(data []byte) (cartMapper, error) {
+	const bankSize = 4096
+
+	cart := &econobanking{}
+	cart.description = "econobanking"
+	cart.formatID = "0840"
+	cart.banks = make([][]uint8, cart.numBanks())
+
+	if len(data) != bankSize*cart.numBanks() {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart econobanking) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart econobanking) format() string {
+	return cart.formatID
+}
+
+func (cart *econobanking) initialise() {
+	cart.bank = 0
+}
+
+func (cart *econobanking) read(addr uint16) (uint8, error) {
+	return cart.banks[cart.bank][addr], nil
+}
+
+func (cart *econobanking) write(addr uint16, data uint8) error {
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart econobanking) numBanks() int {
+	return 2
+}
+
+func (cart econobanking) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *econobanking) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart *econobanking) saveState() interface{} {
+	return cart.bank
+}
+
+func (cart *econobanking) restoreState(state interface{}) error {
+	cart.bank = state.(int)
+	return nil
+}
+
+func (cart *econobanking) listen(addr uint16, data uint8) {
+}
+
+// busActivity implements the optionalBusActivity interface
+func (cart *econobanking) busActivity(addr uint16) {
+	// the hotspots are outside of cartridge space. listen() would be enough
+	// if the hotspots were only triggered by writes but reads of the
+	// hotspots are just as common
+	switch addr & 0x1840 {
+	case 0x0800:
+		cart.bank = 0
+	case 0x0840:
+		cart.bank = 1
+	}
+}
+
+// only address lines A12, A11 and A6 are decoded by 0840 cartridges so every
+// mirror of $0800 and $0840 switches bank
+func (cart econobanking) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x0800, Mask: 0x1840, Access: HotspotReadWrite, Effect: "bank 0"},
+		{Address: 0x0840, Mask: 0x1840, Access: HotspotReadWrite, Effect: "bank 1"},
+	}
+}
+
+func (cart *econobanking) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *econobanking) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart econobanking) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *econobanking) step() {
+}
@@ -0,0 +1,155 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (CartF0.hxx):
+//
+// -F0: Dynacom Megaboy. There are 16 4K banks. Accessing 1FF0 switches to the
+// next bank in sequence, wrapping around to the first bank after the last.
+//
+// There is no way of jumping directly to a bank. Each bank must therefore
+// contain code that can step through the banks until the desired bank is
+// reached.
+
+type megaboy struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// identifies the currently selected bank
+	bank int
+}
+
+func newMegaboy(data []byte) (cartMapper, error) {
+	const bankSize = 4096
+
+	cart := &megaboy{}
+	cart.description = "megaboy"
+	cart.formatID = "F0"
+	cart.banks = make([][]uint8, cart.numBanks())
+
+	if len(data) != bankSize*cart.numBanks() {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart megaboy) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart megaboy) format() string {
+	return cart.formatID
+}
+
+func (cart *megaboy) initialise() {
+	cart.bank = len(cart.banks) - 1
+}
+
+func (cart *megaboy) read(addr uint16) (uint8, error) {
+	data := cart.banks[cart.bank][addr]
+	cart.bankSwitchOnAccess(addr)
+	return data, nil
+}
+
+func (cart *megaboy) write(addr uint16, data uint8) error {
+	if cart.bankSwitchOnAccess(addr) {
+		return nil
+	}
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart *megaboy) bankSwitchOnAccess(addr uint16) bool {
+	if addr == 0x0ff0 {
+		cart.bank++
+		if cart.bank >= cart.numBanks() {
+			cart.bank = 0
+		}
+		return true
+	}
+	return false
+}
+
+func (cart megaboy) numBanks() int {
+	return 16
+}
+
+func (cart megaboy) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *megaboy) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart *megaboy) saveState() interface{} {
+	return cart.bank
+}
+
+func (cart *megaboy) restoreState(state interface{}) error {
+	cart.bank = state.(int)
+	return nil
+}
+
+func (cart *megaboy) listen(addr uint16, data uint8) {
+}
+
+// F0 cartridges have a single hotspot that steps through the sixteen banks in
+// turn
+func (cart megaboy) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x1ff0, Access: HotspotReadWrite, Effect: "next bank"},
+	}
+}
+
+func (cart *megaboy) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *megaboy) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart megaboy) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *megaboy) step() {
+}
@@ -65,18 +65,12 @@ import (
 // Note that the 256-byte banks and the large 1K bank are seperate entities.
 // The M-Network carts are about as complex as it gets.
 
-func fingerprintMnetwork(b []byte) bool {
-	threshold := 2
-	for i := 0; i < len(b)-3; i++ {
-		if b[i] == 0x7e && b[i+1] == 0x66 && b[i+2] == 0x66 && b[i+3] == 0x66 {
-			threshold--
-		}
-		if threshold == 0 {
-			return true
-		}
-	}
+var fingerprintPatternsMnetwork = fingerprintPatterns{
+	{"7E 66 66 66 sequence", []uint8{0x7e, 0x66, 0x66, 0x66}},
+}
 
-	return false
+func fingerprintMnetwork(b []byte) bool {
+	return fingerprintPatternsMnetwork.total(b) >= 2
 }
 
 type mnetwork struct {
@@ -328,6 +322,29 @@ func (cart *mnetwork) restoreState(state interface{}) error {
 func (cart *mnetwork) listen(addr uint16, data uint8) {
 }
 
+// E7 cartridges have three types of hotspot: $1FE0 to $1FE6 select the ROM bank
+// for the first segment, $1FE7 maps the 1k RAM into the first segment and
+// $1FF8 to $1FFB select the 256 byte RAM bank
+func (cart mnetwork) hotspots() []Hotspot {
+	h := bankHotspots(0x0fe0, 7, HotspotReadWrite, "segment 0 -> bank %d")
+
+	h = append(h, Hotspot{
+		Address: 0x1fe7,
+		Access:  HotspotReadWrite,
+		Effect:  "1k RAM",
+	})
+
+	for i := 0; i < len(cart.ram256byte); i++ {
+		h = append(h, Hotspot{
+			Address: 0x1ff8 + uint16(i),
+			Access:  HotspotReadWrite,
+			Effect:  fmt.Sprintf("256 byte RAM [%d]", i),
+		})
+	}
+
+	return h
+}
+
 func (cart *mnetwork) poke(addr uint16, data uint8) error {
 	return errors.New(errors.UnpokeableAddress, addr)
 }
@@ -35,23 +35,20 @@ import (
 // for the third 1K.  The last 1K always points to the last 1K of the ROM image
 // so that the cart always starts up in the exact same place.
 
-func fingerprintParkerBros(b []byte) bool {
-	// fingerprint patterns taken from Stella CartDetector.cxx
-	for i := 0; i <= len(b)-3; i++ {
-		if (b[i] == 0x8d && b[i+1] == 0xe0 && b[i+2] == 0x1f) ||
-			(b[i] == 0x8d && b[i+1] == 0xe0 && b[i+2] == 0x5f) ||
-			(b[i] == 0x8d && b[i+1] == 0xe9 && b[i+2] == 0xff) ||
-			(b[i] == 0x0c && b[i+1] == 0xe0 && b[i+2] == 0x1f) ||
-			(b[i] == 0xad && b[i+1] == 0xe0 && b[i+2] == 0x1f) ||
-			(b[i] == 0xad && b[i+1] == 0xe9 && b[i+2] == 0xff) ||
-			(b[i] == 0xad && b[i+1] == 0xed && b[i+2] == 0xff) ||
-			(b[i] == 0xad && b[i+1] == 0xf3 && b[i+2] == 0xbf) {
-			return true
-		}
-
-	}
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsParkerBros = fingerprintPatterns{
+	{"STA $1FE0", []uint8{0x8d, 0xe0, 0x1f}},
+	{"STA $5FE0", []uint8{0x8d, 0xe0, 0x5f}},
+	{"STA $FFE9", []uint8{0x8d, 0xe9, 0xff}},
+	{"NOP $1FE0", []uint8{0x0c, 0xe0, 0x1f}},
+	{"LDA $1FE0", []uint8{0xad, 0xe0, 0x1f}},
+	{"LDA $FFE9", []uint8{0xad, 0xe9, 0xff}},
+	{"LDA $FFED", []uint8{0xad, 0xed, 0xff}},
+	{"LDA $BFF3", []uint8{0xad, 0xf3, 0xbf}},
+}
 
-	return false
+func fingerprintParkerBros(b []byte) bool {
+	return fingerprintPatternsParkerBros.total(b) > 0
 }
 
 // parkerBros implements the cartMapper interface.
@@ -245,6 +242,26 @@ func (cart *parkerBros) restoreState(state interface{}) error {
 func (cart *parkerBros) listen(addr uint16, data uint8) {
 }
 
+// E0 cartridges have a group of eight hotspots for each of the first three 1k
+// segments
+func (cart parkerBros) hotspots() []Hotspot {
+	h := make([]Hotspot, 0, 24)
+
+	// the last segment always points to the last bank so there are no
+	// hotspots for it
+	for s := 0; s < 3; s++ {
+		for b := 0; b < cart.numBanks(); b++ {
+			h = append(h, Hotspot{
+				Address: 0x1fe0 + uint16(s*8+b),
+				Access:  HotspotReadWrite,
+				Effect:  fmt.Sprintf("segment %d -> bank %d", s, b),
+			})
+		}
+	}
+
+	return h
+}
+
 func (cart *parkerBros) poke(addr uint16, data uint8) error {
 	return errors.New(errors.UnpokeableAddress, addr)
 }
@@ -0,0 +1,169 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (CartSB.hxx):
+//
+// -SB: SuperBanking. Supports 64K, 128K and 256K ROMs split into 4K banks.
+// Banks are selected by accessing an address in the 0800 to 0FFF range, which
+// is outside of cartridge space. The lower bits of the address give the bank
+// number. For example, accessing 0800 selects bank 0 and accessing 0805
+// selects bank 5.
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsSuperbank = fingerprintPatterns{
+	{"LDA $0800,X", []uint8{0xbd, 0x00, 0x08}},
+	{"LDA $0800", []uint8{0xad, 0x00, 0x08}},
+}
+
+func fingerprintSuperbank(b []byte) bool {
+	return fingerprintPatternsSuperbank.total(b) > 0
+}
+
+type superbank struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// identifies the currently selected bank
+	bank int
+}
+
+func newSuperbank(data []byte) (cartMapper, error) {
+	const bankSize = 4096
+
+	cart := &superbank{}
+	cart.description = "superbank"
+	cart.formatID = "SB"
+
+	switch len(data) {
+	case 65536:
+	case 131072:
+	case 262144:
+	default:
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	cart.banks = make([][]uint8, len(data)/bankSize)
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart superbank) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart superbank) format() string {
+	return cart.formatID
+}
+
+func (cart *superbank) initialise() {
+	cart.bank = len(cart.banks) - 1
+}
+
+func (cart *superbank) read(addr uint16) (uint8, error) {
+	return cart.banks[cart.bank][addr], nil
+}
+
+func (cart *superbank) write(addr uint16, data uint8) error {
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart superbank) numBanks() int {
+	return len(cart.banks)
+}
+
+func (cart superbank) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *superbank) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart *superbank) saveState() interface{} {
+	return cart.bank
+}
+
+func (cart *superbank) restoreState(state interface{}) error {
+	cart.bank = state.(int)
+	return nil
+}
+
+func (cart *superbank) listen(addr uint16, data uint8) {
+}
+
+// busActivity implements the optionalBusActivity interface
+func (cart *superbank) busActivity(addr uint16) {
+	// the hotspots are outside of cartridge space and are triggered by both
+	// reads and writes so listen() is not sufficient
+	if addr&0x1800 == 0x0800 {
+		cart.bank = int(addr) & (len(cart.banks) - 1)
+	}
+}
+
+// SB cartridges select a bank by accessing $0800 to $0FFF. only as many low
+// address bits as are needed for the number of banks are decoded
+func (cart superbank) hotspots() []Hotspot {
+	h := make([]Hotspot, len(cart.banks))
+	for i := range h {
+		h[i] = Hotspot{
+			Address: 0x0800 | uint16(i),
+			Mask:    0x1800 | uint16(len(cart.banks)-1),
+			Access:  HotspotReadWrite,
+			Effect:  fmt.Sprintf("bank %d", i),
+		}
+	}
+	return h
+}
+
+func (cart *superbank) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *superbank) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart superbank) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *superbank) step() {
+}
@@ -0,0 +1,538 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from bankswitch_sizes.txt:
+//
+// -AR: The Arcadia (aka Starpath) Supercharger uses 6K of RAM in three 2K
+// banks, plus a 2K BIOS ROM. The 4K cartridge space is split into two 2K
+// segments and a configuration byte decides which bank is visible in each
+// segment:
+//
+//	D7-D5 = write pulse delay (not needed for emulators)
+//	D4-D2 = RAM/ROM configuration:
+//
+//	         $F000-F7FF    $F800-FFFF
+//	  000        2            ROM
+//	  001        0             2
+//	  010        2             0
+//	  011        0            ROM
+//	  100        2            ROM
+//	  101        1             2
+//	  110        2             1
+//	  111        1            ROM
+//
+//	D1 = RAM write enable (1 = enabled)
+//	D0 = ROM power off (1 = off)
+//
+// There is no R/W line on the cartridge port so writing to RAM is done by
+// reading. Accessing $F000-$F0FF puts the low byte of the address into the
+// data hold register. The value is written to RAM if the address to write to
+// is accessed exactly five address bus transitions later. The configuration
+// byte is set by accessing $FFF8 immediately after setting the data hold
+// register.
+//
+// Supercharger games are distributed as "tape" images of one or more loads.
+// Each load is 8448 bytes: 32 pages of 256 bytes followed by a 256 byte
+// header.
+
+// the size of each load in a supercharger tape image
+const superchargerLoadSize = 8448
+
+// the bank number of the BIOS. RAM banks are numbered from zero
+const superchargerBIOSBank = 3
+
+func fingerprintSupercharger(b []byte) bool {
+	return len(b) > 0 && len(b)%superchargerLoadSize == 0
+}
+
+// the layout of the header that follows the 32 pages of data in each load
+const (
+	superchargerHeaderStartLo    = 0
+	superchargerHeaderStartHi    = 1
+	superchargerHeaderConfig     = 2
+	superchargerHeaderNumPages   = 3
+	superchargerHeaderLoadNumber = 5
+	superchargerHeaderPageTable  = 16
+)
+
+// we don't have the real supercharger BIOS so we use a stand-in that does just
+// enough to get the loaded program running. the tape is loaded instantly
+// when the CPU accesses the load hotspot ($F850). the load happens in step()
+// rather than read() so that only the CPU, and not the disassembler or any
+// other reader of cartridge memory, can start it.
+//
+// the program counter starts at $F80A (see the reset vector at the end of the
+// BIOS). zero page is cleared and the number of the first load in the tape is
+// written to $FA. multiload games select the next load by writing the load
+// number to $FA and jumping to $F800.
+//
+// after the load has completed, the configuration byte and start address from
+// the load header are patched into the trampoline by the emulator. the
+// trampoline is then copied to zero page so that it can set the new bank
+// configuration (which may well switch out the BIOS) before jumping to the
+// start address.
+var superchargerBIOS = []uint8{
+	// $F800 (multiload entry point)
+	0xa6, 0xfa, //       LDX $FA
+	0xdd, 0x00, 0xf0, // CMP $F000,X      ; load number in data hold register
+	0x4c, 0x50, 0xf8, // JMP $F850        ; load hotspot
+	0x00, 0x00,
+
+	// $F80A (reset entry point)
+	0x78,       //       SEI
+	0xd8,       //       CLD
+	0xa2, 0xff, //       LDX #$FF
+	0x9a,       //       TXS
+	0xe8,       //       INX
+	0x8a,       //       TXA
+	0x95, 0x00, //       STA $00,X        ; clear zero page
+	0xe8,       //       INX
+	0xd0, 0xfb, //       BNE $F811
+	0xa2, 0x00, //       LDX #<first load> ; patched on attach
+	0x86, 0xfa, //       STX $FA
+	0x4c, 0x00, 0xf8, // JMP $F800
+}
+
+// offset into BIOS of the operand of "LDX #<first load>"
+const superchargerBIOSFirstLoad = 0x0017
+
+// $F850 (load hotspot). code is executed after the tape has loaded
+var superchargerBIOSPostLoad = []uint8{
+	0xa2, 0x05, //       LDX #$05
+	0xbd, 0x80, 0xf8, // LDA $F880,X      ; copy trampoline to zero page
+	0x95, 0xf8, //       STA $F8,X
+	0xca,       //       DEX
+	0x10, 0xf8, //       BPL $F852
+	0xae, 0x86, 0xf8, // LDX $F886
+	0xdd, 0x00, 0xf0, // CMP $F000,X      ; configuration in data hold register
+	0x4c, 0xf8, 0x00, // JMP $00F8        ; run trampoline
+}
+
+// offset into BIOS of the load hotspot
+const superchargerBIOSLoadHotspot = 0x0050
+
+// $F880 (trampoline). copied to $00F8 by the BIOS
+var superchargerBIOSTrampoline = []uint8{
+	0xcd, 0xf8, 0xff, // CMP $FFF8        ; set configuration
+	0x4c, 0x00, 0x00, // JMP <start>      ; patched on load
+	0x00, //             <configuration>  ; patched on load
+}
+
+// offset into BIOS of the trampoline
+const superchargerBIOSTrampolineOffset = 0x0080
+
+// supercharger registers are kept in their own type for the convenience of
+// saveState() and restoreState()
+type superchargerRegisters struct {
+	// most recent configuration byte
+	config       uint8
+	writeEnabled bool
+	romPower     bool
+
+	// the value to be written to RAM
+	dataHold uint8
+
+	// whether the data hold register contains a value that has not yet been
+	// written
+	writePending bool
+
+	// the number of address bus transitions. a transition is an access to an
+	// address that is different to the previous access
+	transitions int
+
+	// the value of transitions when the data hold register was set
+	latchedAt int
+
+	// the most recent address on the address bus
+	lastAddr uint16
+
+	// the load hotspot has been read and the tape should be loaded on the
+	// next call to step()
+	loadPending bool
+
+	// the error from the most recent load, if any. it is returned by the next
+	// call to read() because step() cannot return an error
+	loadErr error
+}
+
+type supercharger struct {
+	formatID    string
+	description string
+
+	// the loads contained in the tape image
+	tape [][]uint8
+
+	// three banks of RAM and the BIOS
+	ram  [][]uint8
+	bios []uint8
+
+	// the bank mapped into each of the two 2k segments
+	segment [2]int
+
+	registers superchargerRegisters
+}
+
+func newSupercharger(data []byte) (cartMapper, error) {
+	const bankSize = 2048
+
+	cart := &supercharger{}
+	cart.description = "supercharger"
+	cart.formatID = "AR"
+
+	if !fingerprintSupercharger(data) {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: tape image size must be multiple of %d", cart.formatID, superchargerLoadSize))
+	}
+
+	numLoads := len(data) / superchargerLoadSize
+	cart.tape = make([][]uint8, numLoads)
+	for l := 0; l < numLoads; l++ {
+		cart.tape[l] = make([]uint8, superchargerLoadSize)
+		offset := l * superchargerLoadSize
+		copy(cart.tape[l], data[offset:offset+superchargerLoadSize])
+	}
+
+	cart.ram = make([][]uint8, superchargerBIOSBank)
+	for b := range cart.ram {
+		cart.ram[b] = make([]uint8, bankSize)
+	}
+
+	cart.bios = make([]uint8, bankSize)
+	copy(cart.bios, superchargerBIOS)
+	copy(cart.bios[superchargerBIOSLoadHotspot:], superchargerBIOSPostLoad)
+	copy(cart.bios[superchargerBIOSTrampolineOffset:], superchargerBIOSTrampoline)
+
+	// first load in the tape
+	cart.bios[superchargerBIOSFirstLoad] = cart.tape[0][8192+superchargerHeaderLoadNumber]
+
+	// NMI, reset and IRQ vectors all point to the reset entry point
+	for i := bankSize - 6; i < bankSize; i += 2 {
+		cart.bios[i] = 0x0a
+		cart.bios[i+1] = 0xf8
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart supercharger) String() string {
+	s := strings.Builder{}
+	s.WriteString(fmt.Sprintf("%s [%s] Banks: ", cart.description, cart.formatID))
+	for i, b := range cart.segment {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		if b == superchargerBIOSBank {
+			s.WriteString("BIOS")
+		} else {
+			s.WriteString(fmt.Sprintf("%d", b))
+		}
+	}
+	if cart.registers.writeEnabled {
+		s.WriteString(" [write]")
+	}
+	if len(cart.tape) > 1 {
+		s.WriteString(fmt.Sprintf(" Loads: %d", len(cart.tape)))
+	}
+	return s.String()
+}
+
+func (cart supercharger) format() string {
+	return cart.formatID
+}
+
+func (cart *supercharger) initialise() {
+	for b := range cart.ram {
+		for i := range cart.ram[b] {
+			cart.ram[b][i] = 0x00
+		}
+	}
+
+	cart.registers = superchargerRegisters{}
+	cart.setConfig(0x00)
+}
+
+// setConfig interprets the configuration byte as described in the commentary
+// at the head of this file
+func (cart *supercharger) setConfig(config uint8) {
+	cart.registers.config = config
+	cart.registers.writeEnabled = config&0x02 == 0x02
+	cart.registers.romPower = config&0x01 != 0x01
+
+	switch (config >> 2) & 0x07 {
+	case 0:
+		cart.segment = [2]int{2, superchargerBIOSBank}
+	case 1:
+		cart.segment = [2]int{0, 2}
+	case 2:
+		cart.segment = [2]int{2, 0}
+	case 3:
+		cart.segment = [2]int{0, superchargerBIOSBank}
+	case 4:
+		cart.segment = [2]int{2, superchargerBIOSBank}
+	case 5:
+		cart.segment = [2]int{1, 2}
+	case 6:
+		cart.segment = [2]int{2, 1}
+	case 7:
+		cart.segment = [2]int{1, superchargerBIOSBank}
+	}
+}
+
+// bankData returns the memory for the bank mapped to the segment that the
+// address falls into
+func (cart *supercharger) bankData(addr uint16) []uint8 {
+	b := cart.segment[0]
+	if addr >= 0x0800 {
+		b = cart.segment[1]
+	}
+	if b == superchargerBIOSBank {
+		return cart.bios
+	}
+	return cart.ram[b]
+}
+
+func (cart *supercharger) read(addr uint16) (uint8, error) {
+	// the BIOS load hotspot is only visible when the BIOS is mapped into the
+	// upper segment
+	if addr == 0x0800|superchargerBIOSLoadHotspot && cart.segment[1] == superchargerBIOSBank {
+		cart.registers.loadPending = true
+		return cart.bios[superchargerBIOSLoadHotspot], nil
+	}
+
+	if cart.registers.loadErr != nil {
+		err := cart.registers.loadErr
+		cart.registers.loadErr = nil
+		return 0, err
+	}
+
+	cart.access(addr, true)
+
+	return cart.bankData(addr)[addr&0x07ff], nil
+}
+
+func (cart *supercharger) write(addr uint16, data uint8) error {
+	// the data bus is not connected in any meaningful way so the value being
+	// written is ignored. however, the address is used in almost exactly the
+	// same way as for a read
+	cart.access(addr, false)
+	return nil
+}
+
+// setDataHold sets the data hold register if the address is in the
+// appropriate range. returns true if the data hold register has been set.
+func (cart *supercharger) setDataHold(addr uint16) bool {
+	// cancel any pending write if more than five transitions have occurred
+	if cart.registers.writePending && cart.registers.transitions > cart.registers.latchedAt+5 {
+		cart.registers.writePending = false
+	}
+
+	if addr&0x0f00 == 0x0000 && (!cart.registers.writeEnabled || !cart.registers.writePending) {
+		cart.registers.dataHold = uint8(addr)
+		cart.registers.latchedAt = cart.registers.transitions
+		cart.registers.writePending = true
+		return true
+	}
+
+	return false
+}
+
+// access handles the data hold register, the configuration register and any
+// pending write to RAM
+func (cart *supercharger) access(addr uint16, read bool) {
+	if cart.setDataHold(addr) {
+		return
+	}
+
+	if read && addr == 0x0ff8 {
+		cart.registers.writePending = false
+		cart.setConfig(cart.registers.dataHold)
+		return
+	}
+
+	if cart.registers.writeEnabled && cart.registers.writePending && cart.registers.transitions == cart.registers.latchedAt+5 {
+		// writes to the BIOS are ignored
+		b := cart.segment[0]
+		if addr >= 0x0800 {
+			b = cart.segment[1]
+		}
+		if b != superchargerBIOSBank {
+			cart.ram[b][addr&0x07ff] = cart.registers.dataHold
+		}
+		cart.registers.writePending = false
+	}
+}
+
+// load copies the load with the specified load number from the tape into RAM
+func (cart *supercharger) load(loadNumber uint8) error {
+	for _, l := range cart.tape {
+		header := l[8192:]
+
+		if header[superchargerHeaderLoadNumber] != loadNumber {
+			continue
+		}
+
+		numPages := int(header[superchargerHeaderNumPages])
+		if numPages > 32 {
+			numPages = 32
+		}
+
+		for p := 0; p < numPages; p++ {
+			// the page table entry says where the page should be copied to
+			pt := header[superchargerHeaderPageTable+p]
+			bank := int(pt & 0x03)
+			page := int(pt>>2) & 0x07
+
+			// pages cannot be loaded into the BIOS
+			if bank < superchargerBIOSBank {
+				copy(cart.ram[bank][page*256:(page+1)*256], l[p*256:(p+1)*256])
+			}
+		}
+
+		// the BIOS trampoline needs to know how to start the load
+		t := cart.bios[superchargerBIOSTrampolineOffset:]
+		t[4] = header[superchargerHeaderStartLo]
+		t[5] = header[superchargerHeaderStartHi]
+		t[6] = header[superchargerHeaderConfig]
+
+		return nil
+	}
+
+	return errors.New(errors.CartridgeError, fmt.Sprintf("%s: no load numbered %d in tape", cart.formatID, loadNumber))
+}
+
+func (cart supercharger) numBanks() int {
+	return len(cart.ram) + 1
+}
+
+func (cart *supercharger) getBank(addr uint16) int {
+	if addr >= 0x0000 && addr <= 0x07ff {
+		return cart.segment[0]
+	}
+	return cart.segment[1]
+}
+
+func (cart *supercharger) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= cart.numBanks() {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+
+	if addr >= 0x0000 && addr <= 0x07ff {
+		cart.segment[0] = bank
+	} else if addr >= 0x0800 && addr <= 0x0fff {
+		cart.segment[1] = bank
+	} else {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid address [%#04x bank %d]", cart.formatID, addr, bank))
+	}
+
+	return nil
+}
+
+func (cart *supercharger) saveState() interface{} {
+	ram := make([][]uint8, len(cart.ram))
+	for b := range ram {
+		ram[b] = make([]uint8, len(cart.ram[b]))
+		copy(ram[b], cart.ram[b])
+	}
+
+	bios := make([]uint8, len(cart.bios))
+	copy(bios, cart.bios)
+
+	return []interface{}{cart.segment, cart.registers, ram, bios}
+}
+
+func (cart *supercharger) restoreState(state interface{}) error {
+	cart.segment = state.([]interface{})[0].([2]int)
+	cart.registers = state.([]interface{})[1].(superchargerRegisters)
+
+	ram := state.([]interface{})[2].([][]uint8)
+	for b := range cart.ram {
+		copy(cart.ram[b], ram[b])
+	}
+
+	copy(cart.bios, state.([]interface{})[3].([]uint8))
+
+	// a load started by reading the hotspot after the state was saved (by the
+	// disassembler for example) should not happen
+	cart.registers.loadPending = false
+
+	return nil
+}
+
+func (cart *supercharger) listen(addr uint16, data uint8) {
+}
+
+// the supercharger configuration byte is set by reading $1FF8. the data hold
+// register is also set by accessing any address in the range $1000 to $10FF
+// but that is not reported as a hotspot
+func (cart supercharger) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x1ff8, Access: HotspotRead, Effect: "configuration (from data hold register)"},
+	}
+}
+
+// busActivity implements the optionalBusActivity interface
+func (cart *supercharger) busActivity(addr uint16) {
+	// the 6507 has thirteen address lines
+	addr &= 0x1fff
+	if addr != cart.registers.lastAddr {
+		cart.registers.transitions++
+		cart.registers.lastAddr = addr
+	}
+}
+
+// peek implements the optionalPeek interface. reading the load hotspot or any
+// other address has no effect
+func (cart *supercharger) peek(addr uint16) (uint8, error) {
+	return cart.bankData(addr)[addr&0x07ff], nil
+}
+
+func (cart *supercharger) poke(addr uint16, data uint8) error {
+	cart.bankData(addr)[addr&0x07ff] = data
+	return nil
+}
+
+func (cart *supercharger) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart supercharger) getRAMinfo() []RAMinfo {
+	// the supercharger RAM is the main memory of the cartridge and there are
+	// no separate read and write addresses. moreover, reading from cartridge
+	// space has side-effects for this cartridge type so it is not safe to
+	// present the RAM as RAMinfo
+	return nil
+}
+
+func (cart *supercharger) step() {
+	// the load hotspot was read by the CPU during this cycle
+	if cart.registers.loadPending {
+		cart.registers.loadPending = false
+		cart.registers.loadErr = cart.load(cart.registers.dataHold)
+	}
+}
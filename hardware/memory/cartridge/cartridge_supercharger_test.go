@@ -0,0 +1,174 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
+	"github.com/jetsetilly/gopher2600/disassembly"
+	"github.com/jetsetilly/gopher2600/hardware"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// superchargerTape returns a single load tape image. the load has the
+// specified load number and places the program at $F000
+func superchargerTape(loadNumber uint8, program []uint8) []uint8 {
+	tape := make([]uint8, 8448)
+	copy(tape, program)
+
+	header := tape[8192:]
+	header[0] = 0x00       // start address lo
+	header[1] = 0xf0       // start address hi
+	header[2] = 0x0c       // configuration: bank 0 at $F000 and BIOS at $F800
+	header[3] = 1          // number of pages
+	header[5] = loadNumber // load number
+	header[16] = 0x00      // page table: page 0 of bank 0
+
+	return tape
+}
+
+// writeTape writes the tape to a temporary file and returns the filename
+func writeTape(t *testing.T, tape []uint8) string {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "supercharger*.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(tape); err != nil {
+		t.Fatal(err)
+	}
+
+	return f.Name()
+}
+
+func TestSuperchargerNonZeroLoad(t *testing.T) {
+	program := []uint8{
+		0xa9, 0x42, //       LDA #$42
+		0x85, 0x80, //       STA $80
+		0x4c, 0x04, 0xf0, // JMP $F004
+	}
+
+	filename := writeTape(t, superchargerTape(7, program))
+	defer os.Remove(filename)
+
+	cartload := cartridgeloader.Loader{Filename: filename, Format: "AR"}
+
+	// the disassembler reads the load hotspot like any other address. this
+	// must not start a load
+	if _, err := disassembly.FromCartridge(cartload); err != nil {
+		t.Fatalf("disassembly failed: %v", err)
+	}
+
+	tv, err := television.NewTelevision("NTSC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vcs, err := hardware.NewVCS(tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vcs.AttachCartridge(cartload); err != nil {
+		t.Fatal(err)
+	}
+
+	// the BIOS clears zero page and then loads the first load in the tape.
+	// a few thousand instructions is plenty
+	for i := 0; i < 5000; i++ {
+		if err := vcs.Step(nil); err != nil {
+			t.Fatalf("step failed: %v", err)
+		}
+
+		// peek rather than read so that the supercharger does not see the
+		// access on the address bus
+		v, err := vcs.Mem.RAM.Peek(0x80)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == 0x42 && vcs.CPU.PC.Address() == 0xf004 {
+			return
+		}
+	}
+
+	t.Errorf("loaded program did not run")
+}
+
+func TestSuperchargerPeekLoadHotspot(t *testing.T) {
+	tape := superchargerTape(0, []uint8{0xa9, 0x42})
+
+	// the page is loaded into bank 2, which is mapped to $F000 at power on
+	tape[8192+16] = 0x02
+
+	filename := writeTape(t, tape)
+	defer os.Remove(filename)
+
+	tv, err := television.NewTelevision("NTSC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vcs, err := hardware.NewVCS(tv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vcs.AttachCartridge(cartridgeloader.Loader{Filename: filename, Format: "AR"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// peeking at the load hotspot does not start a load
+	if _, err := vcs.Mem.Cart.Peek(0x1850); err != nil {
+		t.Fatal(err)
+	}
+	vcs.Mem.Cart.Step()
+	if v, _ := vcs.Mem.Cart.Peek(0x1000); v != 0x00 {
+		t.Errorf("load started by peeking the load hotspot")
+	}
+
+	// a load started while the state was saved is forgotten when the state
+	// is restored
+	state := vcs.Mem.Cart.SaveState()
+	if _, err := vcs.Mem.Cart.Read(0x1850); err != nil {
+		t.Fatal(err)
+	}
+	if err := vcs.Mem.Cart.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+	vcs.Mem.Cart.Step()
+	if v, _ := vcs.Mem.Cart.Peek(0x1000); v != 0x00 {
+		t.Errorf("load started after restoring cartridge state")
+	}
+
+	// reading the load hotspot does
+	if _, err := vcs.Mem.Cart.Read(0x1850); err != nil {
+		t.Fatal(err)
+	}
+	vcs.Mem.Cart.Step()
+	if v, _ := vcs.Mem.Cart.Peek(0x1000); v != 0xa9 {
+		t.Errorf("load not started by reading the load hotspot")
+	}
+}
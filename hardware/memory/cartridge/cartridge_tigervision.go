@@ -39,21 +39,15 @@ import (
 // here has no effect other than switching banks.  Very clever; especially
 // since you can implement this with only one chip! (a 74LS173)
 
+// tigervision cartridges change banks by writing to memory address 0x3f. we
+// can hypothesize that these types of cartridges will have that instruction
+// sequence "85 3f" many times in a ROM whereas other cartridge types will not
+var fingerprintPatternsTigervision = fingerprintPatterns{
+	{"STA $3F", []uint8{0x85, 0x3f}},
+}
+
 func fingerprintTigervision(b []byte) bool {
-	// tigervision cartridges change banks by writing to memory address 0x3f. we
-	// can hypothesize that these types of cartridges will have that instruction
-	// sequence "85 3f" many times in a ROM whereas other cartridge types will not
-
-	threshold := 5
-	for i := 0; i < len(b)-1; i++ {
-		if b[i] == 0x85 && b[i+1] == 0x3f {
-			threshold--
-		}
-		if threshold == 0 {
-			return true
-		}
-	}
-	return false
+	return fingerprintPatternsTigervision.total(b) >= 5
 }
 
 type tigervision struct {
@@ -186,6 +180,14 @@ func (cart *tigervision) listen(addr uint16, data uint8) {
 	// tigervision cartridges use mirror addresses to write to the TIA.
 }
 
+// the 3F listen() function will switch banks on a write to any address in the
+// TIA range but only the conventional address is reported
+func (cart tigervision) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x003f, Access: HotspotWrite, Effect: "segment 0 -> bank (value written)"},
+	}
+}
+
 func (cart *tigervision) poke(addr uint16, data uint8) error {
 	return errors.New(errors.UnpokeableAddress, addr)
 }
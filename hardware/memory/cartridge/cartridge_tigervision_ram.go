@@ -0,0 +1,267 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (Cart3E.hxx):
+//
+// -3E: An extension of the Tigervision (3F) format that adds up to 32K of
+// RAM. As with 3F, the cartridge space is divided into two 2K segments and the
+// last segment always points to the last 2K of the ROM image. Writing to
+// address 3F selects a ROM bank for the first segment, as before.
+//
+// Writing to address 3E selects a 1K RAM bank for the first segment instead.
+// The RAM is read through 1000-13FF and written through 1400-17FF. The RAM
+// remains selected until a ROM bank is next selected with a write to 3F.
+//
+// Up to 256 2K ROM banks (512K) and 32 1K RAM banks (32K) are supported.
+
+// fingerprint pattern taken from Stella CartDetector.cxx. the sequence
+// "85 3e a9 00" (STA $3E; LDA #$00) will be present in any cartridge that
+// selects the first RAM bank
+var fingerprintPatternsTigervisionRAM = fingerprintPatterns{
+	{"STA $3E; LDA #$00", []uint8{0x85, 0x3e, 0xa9, 0x00}},
+}
+
+// the RAM sequence on its own is too short to be good evidence. like Stella,
+// we also require the cartridge to switch ROM banks in the same way as a 3F
+// cartridge
+func fingerprintTigervisionRAM(b []byte) bool {
+	return fingerprintPatternsTigervisionRAM.total(b) > 0 && fingerprintTigervision(b)
+}
+
+// evidence for 3E includes the evidence for 3F
+func evidenceTigervisionRAM(b []byte) []string {
+	return append(fingerprintPatternsTigervisionRAM.evidence(b), fingerprintPatternsTigervision.evidence(b)...)
+}
+
+type tigervisionRAM struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// the ROM bank pointed to by the first segment. the last segment always
+	// points to the last ROM bank
+	romBank int
+
+	// RAM is divided into 1k banks. ramBank is only meaningful when
+	// ramSelected is true
+	ram         [][]uint8
+	ramBank     int
+	ramSelected bool
+
+	// ram details
+	ramInfo []RAMinfo
+}
+
+func newTigervisionRAM(data []byte) (cartMapper, error) {
+	const bankSize = 2048
+	const ramBankSize = 1024
+	const numRAMBanks = 32
+
+	cart := &tigervisionRAM{}
+	cart.description = "tigervision (+ RAM)"
+	cart.formatID = "3E"
+
+	if len(data)%bankSize != 0 {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: cartridge size must be multiple of %d", cart.formatID, bankSize))
+	}
+
+	numBanks := len(data) / bankSize
+	if numBanks < 2 || numBanks > 256 {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	cart.banks = make([][]uint8, numBanks)
+	for k := 0; k < numBanks; k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	// there's no way of knowing how much RAM the cartridge actually has so
+	// we allocate the maximum
+	cart.ram = make([][]uint8, numRAMBanks)
+	for k := range cart.ram {
+		cart.ram[k] = make([]uint8, ramBankSize)
+	}
+
+	cart.ramInfo = make([]RAMinfo, 1)
+	cart.ramInfo[0] = RAMinfo{
+		// label and whether the segment is active depends on the RAM bank
+		ReadOrigin:  0x1000,
+		ReadMemtop:  0x13ff,
+		WriteOrigin: 0x1400,
+		WriteMemtop: 0x17ff,
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart tigervisionRAM) String() string {
+	s := strings.Builder{}
+	s.WriteString(fmt.Sprintf("%s [%s] Banks: ", cart.description, cart.formatID))
+	if cart.ramSelected {
+		s.WriteString(fmt.Sprintf("RAM %d", cart.ramBank))
+	} else {
+		s.WriteString(fmt.Sprintf("%d", cart.romBank))
+	}
+	s.WriteString(fmt.Sprintf(", %d", len(cart.banks)-1))
+	return s.String()
+}
+
+func (cart tigervisionRAM) format() string {
+	return cart.formatID
+}
+
+func (cart *tigervisionRAM) initialise() {
+	cart.romBank = cart.numBanks() - 2
+	cart.ramBank = 0
+	cart.ramSelected = false
+
+	for k := range cart.ram {
+		for i := range cart.ram[k] {
+			cart.ram[k][i] = 0x00
+		}
+	}
+}
+
+func (cart *tigervisionRAM) read(addr uint16) (uint8, error) {
+	var data uint8
+	if addr >= 0x0000 && addr <= 0x07ff {
+		if cart.ramSelected {
+			data = cart.ram[cart.ramBank][addr&0x03ff]
+		} else {
+			data = cart.banks[cart.romBank][addr&0x07ff]
+		}
+	} else if addr >= 0x0800 && addr <= 0x0fff {
+		data = cart.banks[len(cart.banks)-1][addr&0x07ff]
+	}
+	return data, nil
+}
+
+func (cart *tigervisionRAM) write(addr uint16, data uint8) error {
+	if cart.ramSelected && addr >= 0x0400 && addr <= 0x07ff {
+		cart.ram[cart.ramBank][addr&0x03ff] = data
+		return nil
+	}
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart tigervisionRAM) numBanks() int {
+	return len(cart.banks)
+}
+
+func (cart *tigervisionRAM) getBank(addr uint16) (bank int) {
+	if addr >= 0x0000 && addr <= 0x07ff {
+		// if RAM is selected then the ROM bank number is the most recently
+		// selected ROM bank. code executing from RAM will not be reported
+		// correctly
+		return cart.romBank
+	}
+	return len(cart.banks) - 1
+}
+
+func (cart *tigervisionRAM) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= cart.numBanks() {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+
+	if addr >= 0x0000 && addr <= 0x07ff {
+		cart.romBank = bank
+		cart.ramSelected = false
+	} else if addr >= 0x0800 && addr <= 0x0fff {
+		// last segment always points to the last bank
+	} else {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid address [%#04x bank %d]", cart.formatID, addr, bank))
+	}
+
+	return nil
+}
+
+func (cart *tigervisionRAM) saveState() interface{} {
+	ram := make([][]uint8, len(cart.ram))
+	for k := range ram {
+		ram[k] = make([]uint8, len(cart.ram[k]))
+		copy(ram[k], cart.ram[k])
+	}
+	return []interface{}{cart.romBank, cart.ramBank, cart.ramSelected, ram}
+}
+
+func (cart *tigervisionRAM) restoreState(state interface{}) error {
+	cart.romBank = state.([]interface{})[0].(int)
+	cart.ramBank = state.([]interface{})[1].(int)
+	cart.ramSelected = state.([]interface{})[2].(bool)
+
+	ram := state.([]interface{})[3].([][]uint8)
+	for k := range cart.ram {
+		copy(cart.ram[k], ram[k])
+	}
+
+	return nil
+}
+
+func (cart *tigervisionRAM) listen(addr uint16, data uint8) {
+	// unlike the 3F format, only the exact addresses 3E and 3F cause a bank
+	// switch. the TIA mirror addresses are used to write to the TIA for real
+	switch addr {
+	case 0x003f:
+		cart.romBank = int(data) % len(cart.banks)
+		cart.ramSelected = false
+	case 0x003e:
+		cart.ramBank = int(data) % len(cart.ram)
+		cart.ramSelected = true
+	}
+}
+
+// 3E cartridges add a RAM select hotspot at $3E to the 3F ROM select hotspot.
+// both select the bank for the first segment
+func (cart tigervisionRAM) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x003f, Access: HotspotWrite, Effect: "ROM bank (value written)"},
+		{Address: 0x003e, Access: HotspotWrite, Effect: "RAM bank (value written)"},
+	}
+}
+
+func (cart *tigervisionRAM) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *tigervisionRAM) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart tigervisionRAM) getRAMinfo() []RAMinfo {
+	cart.ramInfo[0].Active = cart.ramSelected
+	cart.ramInfo[0].Label = fmt.Sprintf("3E RAM [%d]", cart.ramBank)
+	return cart.ramInfo
+}
+
+func (cart *tigervisionRAM) step() {
+}
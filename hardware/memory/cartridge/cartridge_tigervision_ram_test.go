@@ -0,0 +1,68 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+)
+
+// tigervisionData returns 8k of cartridge data containing the 3E RAM select
+// sequence once and the 3F bank switch sequence the specified number of times
+func tigervisionData(bankSwitches int) []byte {
+	data := make([]byte, 8192)
+
+	copy(data[0x0100:], []byte{0x85, 0x3e, 0xa9, 0x00})
+	for i := 0; i < bankSwitches; i++ {
+		copy(data[0x0200+i*16:], []byte{0x85, 0x3f})
+	}
+
+	return data
+}
+
+func confidence(rep cartridge.FingerprintReport, id string) float64 {
+	for _, c := range rep.Candidates {
+		if c.ID == id {
+			return c.Confidence
+		}
+	}
+	return 0.0
+}
+
+func TestTigervisionRAMFingerprint(t *testing.T) {
+	// the RAM select sequence on its own is not enough
+	rep := cartridge.FingerprintData(tigervisionData(0))
+	if confidence(rep, "3E") > 0.0 {
+		t.Errorf("3E chosen without 3F bank switching")
+	}
+	if rep.Candidates[0].ID == "3E" {
+		t.Errorf("3E is the most likely format without 3F bank switching")
+	}
+
+	// with 3F bank switching the cartridge is 3E rather than 3F
+	rep = cartridge.FingerprintData(tigervisionData(5))
+	if rep.Candidates[0].ID != "3E" {
+		t.Errorf("3E not chosen with 3F bank switching (%s chosen instead)", rep.Candidates[0].ID)
+	}
+	if confidence(rep, "3F") == 0.0 {
+		t.Errorf("3F fingerprint not matched")
+	}
+}
@@ -0,0 +1,161 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (CartUA.hxx):
+//
+// -UA: UA Limited. 8K split into two 4K banks. Accessing 0220 selects the
+// first bank and accessing 0240 selects the second bank. The hotspots are
+// outside of cartridge space (in the RIOT mirrors) and are triggered by both
+// reads and writes.
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsUA = fingerprintPatterns{
+	{"STA $0240", []uint8{0x8d, 0x40, 0x02}},
+	{"LDA $0240", []uint8{0xad, 0x40, 0x02}},
+	{"LDA $021F,X", []uint8{0xbd, 0x1f, 0x02}},
+}
+
+func fingerprintUA(b []byte) bool {
+	return fingerprintPatternsUA.total(b) > 0
+}
+
+type ua struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// identifies the currently selected bank
+	bank int
+}
+
+func newUA(data []byte) (cartMapper, error) {
+	const bankSize = 4096
+
+	cart := &ua{}
+	cart.description = "ua limited"
+	cart.formatID = "UA"
+	cart.banks = make([][]uint8, cart.numBanks())
+
+	if len(data) != bankSize*cart.numBanks() {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart ua) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart ua) format() string {
+	return cart.formatID
+}
+
+func (cart *ua) initialise() {
+	cart.bank = 0
+}
+
+func (cart *ua) read(addr uint16) (uint8, error) {
+	return cart.banks[cart.bank][addr], nil
+}
+
+func (cart *ua) write(addr uint16, data uint8) error {
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart ua) numBanks() int {
+	return 2
+}
+
+func (cart ua) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *ua) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart *ua) saveState() interface{} {
+	return cart.bank
+}
+
+func (cart *ua) restoreState(state interface{}) error {
+	cart.bank = state.(int)
+	return nil
+}
+
+func (cart *ua) listen(addr uint16, data uint8) {
+}
+
+// busActivity implements the optionalBusActivity interface
+func (cart *ua) busActivity(addr uint16) {
+	// the hotspots are outside of cartridge space and are triggered by both
+	// reads and writes so listen() is not sufficient
+	switch addr & 0x1260 {
+	case 0x0220:
+		cart.bank = 0
+	case 0x0240:
+		cart.bank = 1
+	}
+}
+
+// UA cartridges decode A12, A9, A6 and A5 only so the bank switching
+// addresses $0220 and $0240 are heavily mirrored
+func (cart ua) hotspots() []Hotspot {
+	return []Hotspot{
+		{Address: 0x0220, Mask: 0x1260, Access: HotspotReadWrite, Effect: "bank 0"},
+		{Address: 0x0240, Mask: 0x1260, Access: HotspotReadWrite, Effect: "bank 1"},
+	}
+}
+
+func (cart *ua) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *ua) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart ua) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *ua) step() {
+}
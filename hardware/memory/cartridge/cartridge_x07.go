@@ -0,0 +1,191 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// from the Stella source code (CartX07.hxx):
+//
+// -X07: 64K split into 16 4K banks. Accessing an address matching the pattern
+// 0xxx 1xxx xxxx 1101 (ie. 080D, 081D ... 08FD and mirrors) selects the bank
+// given by bits 4 to 7 of the address.
+//
+// Additionally, when bank 14 or 15 is selected, accessing an address matching
+// the pattern 0xxx 0xxx 0xxx xxxx (ie. the TIA and its mirrors) selects bank
+// 14 or 15 depending on bit 6 of the address.
+//
+// The hotspots are all outside of cartridge space.
+
+// fingerprint patterns taken from Stella CartDetector.cxx
+var fingerprintPatternsX07 = fingerprintPatterns{
+	{"LDA $080D", []uint8{0xad, 0x0d, 0x08}},
+	{"LDA $081D", []uint8{0xad, 0x1d, 0x08}},
+	{"LDA $082D", []uint8{0xad, 0x2d, 0x08}},
+	{"NOP $080D", []uint8{0x0c, 0x0d, 0x08}},
+	{"NOP $081D", []uint8{0x0c, 0x1d, 0x08}},
+	{"NOP $082D", []uint8{0x0c, 0x2d, 0x08}},
+}
+
+func fingerprintX07(b []byte) bool {
+	return fingerprintPatternsX07.total(b) > 0
+}
+
+type x07 struct {
+	formatID    string
+	description string
+
+	banks [][]uint8
+
+	// identifies the currently selected bank
+	bank int
+}
+
+func newX07(data []byte) (cartMapper, error) {
+	const bankSize = 4096
+
+	cart := &x07{}
+	cart.description = "x07"
+	cart.formatID = "X07"
+	cart.banks = make([][]uint8, cart.numBanks())
+
+	if len(data) != bankSize*cart.numBanks() {
+		return nil, errors.New(errors.CartridgeError, fmt.Sprintf("%s: wrong number of bytes in the cartridge file", cart.formatID))
+	}
+
+	for k := 0; k < cart.numBanks(); k++ {
+		cart.banks[k] = make([]uint8, bankSize)
+		offset := k * bankSize
+		copy(cart.banks[k], data[offset:offset+bankSize])
+	}
+
+	cart.initialise()
+
+	return cart, nil
+}
+
+func (cart x07) String() string {
+	return fmt.Sprintf("%s [%s] Bank: %d", cart.description, cart.formatID, cart.bank)
+}
+
+func (cart x07) format() string {
+	return cart.formatID
+}
+
+func (cart *x07) initialise() {
+	// unlike most other formats, the X07 starts in the first bank
+	cart.bank = 0
+}
+
+func (cart *x07) read(addr uint16) (uint8, error) {
+	return cart.banks[cart.bank][addr], nil
+}
+
+func (cart *x07) write(addr uint16, data uint8) error {
+	return errors.New(errors.BusError, addr)
+}
+
+func (cart x07) numBanks() int {
+	return 16
+}
+
+func (cart x07) getBank(addr uint16) int {
+	return cart.bank
+}
+
+func (cart *x07) setBank(addr uint16, bank int) error {
+	if bank < 0 || bank >= len(cart.banks) {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("%s: invalid bank [%d]", cart.formatID, bank))
+	}
+	cart.bank = bank
+	return nil
+}
+
+func (cart *x07) saveState() interface{} {
+	return cart.bank
+}
+
+func (cart *x07) restoreState(state interface{}) error {
+	cart.bank = state.(int)
+	return nil
+}
+
+func (cart *x07) listen(addr uint16, data uint8) {
+}
+
+// busActivity implements the optionalBusActivity interface
+func (cart *x07) busActivity(addr uint16) {
+	// the hotspots are outside of cartridge space and are triggered by both
+	// reads and writes so listen() is not sufficient
+	if addr&0x180f == 0x080d {
+		cart.bank = int(addr&0x00f0) >> 4
+	} else if addr&0x1880 == 0x0000 && cart.bank >= 14 {
+		cart.bank = int(addr&0x0040)>>6 | 14
+	}
+}
+
+// X07 cartridges select a bank with an address of the form $080D | bank<<4.
+// the TIA addresses also switch between banks 14 and 15 but only when one of
+// those banks is already selected
+func (cart x07) hotspots() []Hotspot {
+	h := make([]Hotspot, 0, len(cart.banks)+2)
+	for i := range cart.banks {
+		h = append(h, Hotspot{
+			Address: 0x080d | uint16(i)<<4,
+			Mask:    0x18ff,
+			Access:  HotspotReadWrite,
+			Effect:  fmt.Sprintf("bank %d", i),
+		})
+	}
+
+	// the TIA addresses only have an effect if bank 14 or 15 is selected
+	h = append(h, Hotspot{
+		Address: 0x0000,
+		Mask:    0x18c0,
+		Access:  HotspotReadWrite,
+		Effect:  "bank 14 (if bank 14 or 15 selected)",
+	})
+	h = append(h, Hotspot{
+		Address: 0x0040,
+		Mask:    0x18c0,
+		Access:  HotspotReadWrite,
+		Effect:  "bank 15 (if bank 14 or 15 selected)",
+	})
+
+	return h
+}
+
+func (cart *x07) poke(addr uint16, data uint8) error {
+	return errors.New(errors.UnpokeableAddress, addr)
+}
+
+func (cart *x07) patch(addr uint16, data uint8) error {
+	return errors.New(errors.UnpatchableCartType, cart.formatID)
+}
+
+func (cart x07) getRAMinfo() []RAMinfo {
+	return nil
+}
+
+func (cart *x07) step() {
+}
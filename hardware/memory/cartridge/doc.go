@@ -34,8 +34,38 @@
 // Atari 8k			"F8"
 // Atari 16k		"F6"
 // Atari 32k		"F4"
+// Homebrew 64k		"EF"
+// Megaboy			"F0"
+// SuperBank		"SB"
+// X07			"X07"
 // CBS case			"FA"
 // M-Network		"E7"
 // Parker Bros		"E0"
 // Tigervision		"3F"
+// Tigervision (+ RAM)	"3E"
+// Supercharger		"AR"
+// CommaVid			"CV"
+// UA Limited		"UA"
+// Econobanking		"0840"
+// DPC+ (Harmony)	"DPC+"
+// CDF (Harmony)		"CDF" or "CDFJ"
+//
+// The DPC+ and CDF formats contain an ARM processor that runs code supplied
+// by the cartridge. The arm7tdmi sub-package emulates the ARM.
+//
+// Formats are held in a registry. Packages outside of the cartridge package
+// can add their own formats with the RegisterMapper() function and an
+// implementation of the Mapper interface. When the format of a cartridge is
+// not specified, every registered format is asked for its confidence that the
+// cartridge data is of that format. The format with the highest confidence is
+// chosen and the other candidates are available through the
+// FingerprintAlternatives() function. The FingerprintReport() function
+// describes every candidate along with the evidence found in the data.
+//
+// The Hotspots() function lists the addresses that cause the cartridge to
+// change state, usually by switching banks. Not every format reports its
+// hotspots.
+//
+// Bank switches can be monitored by setting a BankSwitchListener with the
+// SetBankSwitchListener() function.
 package cartridge
@@ -20,104 +20,184 @@
 package cartridge
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/jetsetilly/gopher2600/errors"
 )
 
-func (cart Cartridge) fingerprint8k(data []byte) func([]byte) (cartMapper, error) {
-	if fingerprintTigervision(data) {
-		return newTigervision
+// fingerprintPattern is a sequence of bytes that, when found in cartridge
+// data, is evidence of a particular cartridge format. the description is used
+// in the fingerprint report
+type fingerprintPattern struct {
+	description string
+	sequence    []uint8
+}
+
+func (p fingerprintPattern) count(b []byte) int {
+	return bytes.Count(b, p.sequence)
+}
+
+type fingerprintPatterns []fingerprintPattern
+
+// total returns the number of times any of the patterns appear in the data
+func (ps fingerprintPatterns) total(b []byte) int {
+	n := 0
+	for _, p := range ps {
+		n += p.count(b)
 	}
+	return n
+}
 
-	if fingerprintParkerBros(data) {
-		return newparkerBros
+// most returns the highest number of times a single pattern appears in the
+// data
+func (ps fingerprintPatterns) most(b []byte) int {
+	n := 0
+	for _, p := range ps {
+		if c := p.count(b); c > n {
+			n = c
+		}
 	}
+	return n
+}
 
-	return newAtari8k
+// evidence describes the patterns that appear in the data
+func (ps fingerprintPatterns) evidence(b []byte) []string {
+	e := make([]string, 0)
+	for _, p := range ps {
+		if c := p.count(b); c > 0 {
+			e = append(e, fmt.Sprintf("%d x %s", c, p.description))
+		}
+	}
+	return e
 }
 
-func (cart Cartridge) fingerprint16k(data []byte) func([]byte) (cartMapper, error) {
-	if fingerprintTigervision(data) {
-		return newTigervision
+// FingerprintReport describes the result of fingerprinting cartridge data.
+type FingerprintReport struct {
+	// the number of bytes in the cartridge data
+	Size int
+
+	// every registered format that supports data of this size, in order of
+	// confidence
+	Candidates []FingerprintResult
+
+	// the ID of the format used for the cartridge. empty if no format was
+	// suitable
+	Chosen string
+
+	// the format was specified when the cartridge was attached rather than
+	// chosen by fingerprinting
+	Specified bool
+}
+
+func (rep FingerprintReport) String() string {
+	s := strings.Builder{}
+
+	switch {
+	case rep.Chosen == "":
+		s.WriteString(fmt.Sprintf("%d bytes: no suitable format", rep.Size))
+	case rep.Specified:
+		s.WriteString(fmt.Sprintf("%d bytes: format %s specified", rep.Size, rep.Chosen))
+	default:
+		s.WriteString(fmt.Sprintf("%d bytes: format %s chosen by fingerprint", rep.Size, rep.Chosen))
 	}
 
-	if fingerprintMnetwork(data) {
-		return newMnetwork
+	for _, c := range rep.Candidates {
+		mark := ' '
+		if c.ID == rep.Chosen {
+			mark = '*'
+		}
+		s.WriteString(fmt.Sprintf("\n%c %-5s %.2f", mark, c.ID, c.Confidence))
+		for i, e := range c.Evidence {
+			if i > 0 {
+				s.WriteString(fmt.Sprintf("\n%14s", ""))
+			} else {
+				s.WriteString("  ")
+			}
+			s.WriteString(e)
+		}
 	}
 
-	return newAtari16k
+	return s.String()
 }
 
-func (cart Cartridge) fingerprint32k(data []byte) func([]byte) (cartMapper, error) {
-	if fingerprintTigervision(data) {
-		return newTigervision
+// FingerprintData returns the fingerprint report for the data without
+// attaching it to a cartridge.
+func FingerprintData(data []byte) FingerprintReport {
+	rep := FingerprintReport{Size: len(data)}
+
+	candidates := fingerprintCandidates(data)
+	rep.Candidates = make([]FingerprintResult, len(candidates))
+	for i := range candidates {
+		rep.Candidates[i] = candidates[i].FingerprintResult
 	}
 
-	return newAtari32k
+	return rep
 }
 
+// fingerprint the cartridge data and attach the most likely mapper. if the
+// mapper cannot be created then the next most likely mapper is tried
 func (cart *Cartridge) fingerprint(data []byte) error {
-	var err error
+	cart.fingerprintReport = FingerprintData(data)
 
-	switch len(data) {
-	case 2048:
-		cart.mapper, err = newAtari2k(data)
-		if err != nil {
-			return err
-		}
+	candidates := fingerprintCandidates(data)
 
-	case 4096:
-		cart.mapper, err = newAtari4k(data)
-		if err != nil {
-			return err
+	var firstErr error
+
+	for i := range candidates {
+		// formats with no confidence are never chosen
+		if candidates[i].Confidence <= 0.0 {
+			break
 		}
 
-	case 8192:
-		cart.mapper, err = cart.fingerprint8k(data)(data)
+		mapper, err := candidates[i].entry.create(data)
 		if err != nil {
-			return err
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
 		}
 
-	case 10240:
-		fallthrough
+		cart.mapper = mapper
+		cart.fingerprintReport.Chosen = candidates[i].ID
 
-	case 10495:
-		cart.mapper, err = newDPC(data)
-		if err != nil {
-			return err
+		// if cartridge mapper implements the optionalSuperChip interface then try
+		// to add the additional RAM
+		if superchip, ok := cart.mapper.(optionalSuperchip); ok {
+			superchip.addSuperchip()
 		}
 
-	case 12288:
-		cart.mapper, err = newCBS(data)
-		if err != nil {
-			return err
-		}
+		return nil
+	}
 
-	case 16384:
-		cart.mapper, err = cart.fingerprint16k(data)(data)
-		if err != nil {
-			return err
-		}
+	if firstErr != nil {
+		return firstErr
+	}
 
-	case 32768:
-		cart.mapper, err = cart.fingerprint32k(data)(data)
-		if err != nil {
-			return err
-		}
+	return errors.New(errors.CartridgeError, fmt.Sprintf("unrecognised cartridge size (%d bytes)", len(data)))
+}
 
-	case 65536:
-		return errors.New(errors.CartridgeError, "65536 bytes not yet supported")
+// FingerprintReport returns the fingerprint report for the attached
+// cartridge.
+func (cart Cartridge) FingerprintReport() FingerprintReport {
+	return cart.fingerprintReport
+}
 
-	default:
-		return errors.New(errors.CartridgeError, fmt.Sprintf("unrecognised cartridge size (%d bytes)", len(data)))
+// FingerprintAlternatives returns the formats that were considered but not
+// chosen when the cartridge was last attached, in order of confidence. Returns
+// nil if the format of the cartridge was specified rather than fingerprinted.
+func (cart Cartridge) FingerprintAlternatives() []FingerprintResult {
+	if cart.fingerprintReport.Specified || cart.fingerprintReport.Chosen == "" {
+		return nil
 	}
 
-	// if cartridge mapper implements the optionalSuperChip interface then try
-	// to add the additional RAM
-	if superchip, ok := cart.mapper.(optionalSuperchip); ok {
-		superchip.addSuperchip()
+	alt := make([]FingerprintResult, 0)
+	for _, r := range cart.fingerprintReport.Candidates {
+		if r.ID != cart.fingerprintReport.Chosen && r.Confidence > 0.0 {
+			alt = append(alt, r)
+		}
 	}
 
-	return nil
+	return alt
 }
@@ -0,0 +1,128 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/memorymap"
+)
+
+// HotspotAccess indicates the type of memory access that triggers a hotspot
+type HotspotAccess int
+
+// List of valid HotspotAccess values. HotspotReadWrite is a combination of
+// HotspotRead and HotspotWrite
+const (
+	HotspotRead HotspotAccess = 1 << iota
+	HotspotWrite
+
+	HotspotReadWrite = HotspotRead | HotspotWrite
+)
+
+func (acc HotspotAccess) String() string {
+	switch acc {
+	case HotspotRead:
+		return "read"
+	case HotspotWrite:
+		return "write"
+	case HotspotReadWrite:
+		return "read/write"
+	}
+	return "?"
+}
+
+// Hotspot describes an address that changes the state of the cartridge when
+// it is accessed. Most commonly, this means a change of bank.
+type Hotspot struct {
+	// the address of the hotspot, as it would usually be accessed by the
+	// cartridge program. addresses in cartridge space include the cartridge
+	// origin (ie. 0x1ff8 rather than 0x0ff8)
+	Address uint16
+
+	// the bits in an address that must match the bits in Address for the
+	// hotspot to be triggered. a value of zero means every bit of the address
+	// bus must match
+	Mask uint16
+
+	Access HotspotAccess
+
+	// short description of what happens when the hotspot is triggered. for
+	// example, "bank 0"
+	Effect string
+}
+
+func (h Hotspot) String() string {
+	return fmt.Sprintf("$%04X %s -> %s", h.Address, h.Access, h.Effect)
+}
+
+// Matches returns true if accessing the address triggers the hotspot. The
+// address does not need to be normalised.
+func (h Hotspot) Matches(addr uint16, write bool) bool {
+	if write && h.Access&HotspotWrite != HotspotWrite {
+		return false
+	}
+	if !write && h.Access&HotspotRead != HotspotRead {
+		return false
+	}
+
+	mask := h.Mask
+	if mask == 0 {
+		mask = memorymap.Memtop
+	}
+
+	return addr&mask == h.Address&mask
+}
+
+// bankHotspots is a convenience function that returns a hotspot for each bank
+// in a cartridge where the banks are selected by a consecutive range of
+// cartridge addresses. the origin is the hotspot for the first bank. the
+// effect is a format string for the bank number.
+func bankHotspots(origin uint16, numBanks int, access HotspotAccess, effect string) []Hotspot {
+	h := make([]Hotspot, numBanks)
+	for i := range h {
+		h[i] = Hotspot{
+			Address: memorymap.OriginCart | (origin + uint16(i)),
+			Access:  access,
+			Effect:  fmt.Sprintf(effect, i),
+		}
+	}
+	return h
+}
+
+// Hotspots returns every hotspot of the attached cartridge. Returns nil if the
+// cartridge format has no hotspots or if the mapper does not report them.
+func (cart Cartridge) Hotspots() []Hotspot {
+	if m, ok := cart.mapper.(optionalHotspots); ok {
+		return m.hotspots()
+	}
+	return nil
+}
+
+// HotspotAt returns the hotspot that is triggered by accessing the address.
+// The address does not need to be normalised.
+func (cart Cartridge) HotspotAt(addr uint16, write bool) (Hotspot, bool) {
+	for _, h := range cart.Hotspots() {
+		if h.Matches(addr, write) {
+			return h, true
+		}
+	}
+	return Hotspot{}, false
+}
@@ -0,0 +1,435 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+)
+
+// Mapper is the exported equivalent of the cartMapper interface. It allows
+// packages outside of the cartridge package to implement a cartridge format.
+// See the commentary for the cartMapper interface and the Cartridge type for
+// an explanation of each function.
+//
+// Implementations can optionally implement BusActivity(addr uint16),
+// AddSuperchip() bool and Hotspots() []Hotspot. These are the equivalent of
+// the optionalBusActivity, optionalSuperchip and optionalHotspots interfaces.
+type Mapper interface {
+	String() string
+	Initialise()
+	Format() string
+	Read(addr uint16) (data uint8, err error)
+	Write(addr uint16, data uint8) error
+	NumBanks() int
+	GetBank(addr uint16) (bank int)
+	SetBank(addr uint16, bank int) error
+	SaveState() interface{}
+	RestoreState(interface{}) error
+	Listen(addr uint16, data uint8)
+	Poke(addr uint16, data uint8) error
+	Patch(offset uint16, data uint8) error
+	GetRAMinfo() []RAMinfo
+	Step()
+}
+
+// MapperRegistration describes a cartridge format to the cartridge package.
+type MapperRegistration struct {
+	// the identifier used to select the format in the Format field of
+	// cartridgeloader.Loader. identifiers are not case sensitive
+	ID string
+
+	// returns true if the format supports cartridge data of length n
+	Size func(n int) bool
+
+	// returns the confidence, from 0.0 to 1.0, that the data is of this
+	// format. a value of zero means that the format will never be chosen
+	// automatically. can be nil, in which case the confidence is always zero
+	Fingerprint func(data []byte) float64
+
+	// returns a description of the evidence found in the data for this
+	// format. used in the fingerprint report. can be nil
+	Evidence func(data []byte) []string
+
+	// create a new instance of the mapper
+	New func(data []byte) (Mapper, error)
+}
+
+// RegisterMapper adds a cartridge format to the list of formats that can be
+// attached to the VCS. It should be called from an init() function and is not
+// safe to call at the same time as Cartridge.Attach().
+//
+// When the format of a cartridge is not specified, the Size() and
+// Fingerprint() functions of every registered format are consulted and the
+// format with the highest confidence is chosen. The built-in formats have a
+// confidence of 0.1 when there is no evidence for the format other than the
+// size of the data, and no more than 0.95 otherwise.
+func RegisterMapper(reg MapperRegistration) error {
+	if reg.ID == "" {
+		return errors.New(errors.CartridgeError, "cannot register mapper without an ID")
+	}
+
+	if reg.Size == nil || reg.New == nil {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("incomplete registration for mapper (%s)", reg.ID))
+	}
+
+	if _, ok := lookupMapper(reg.ID); ok {
+		return errors.New(errors.CartridgeError, fmt.Sprintf("mapper already registered (%s)", reg.ID))
+	}
+
+	fingerprint := reg.Fingerprint
+	if fingerprint == nil {
+		fingerprint = noFingerprint
+	}
+
+	evidence := reg.Evidence
+	if evidence == nil {
+		evidence = noEvidence
+	}
+
+	registry = append(registry, registryEntry{
+		id:          reg.ID,
+		size:        reg.Size,
+		fingerprint: fingerprint,
+		evidence:    evidence,
+		create: func(data []byte) (cartMapper, error) {
+			m, err := reg.New(data)
+			if err != nil {
+				return nil, err
+			}
+			return &externalMapper{m: m}, nil
+		},
+	})
+
+	return nil
+}
+
+// RegisteredMappers returns the IDs of all registered formats, in the order
+// in which they were registered.
+func RegisteredMappers() []string {
+	ids := make([]string, 0, len(registry))
+	for _, r := range registry {
+		ids = append(ids, r.id)
+	}
+	return ids
+}
+
+type registryEntry struct {
+	id          string
+	size        func(n int) bool
+	fingerprint func(data []byte) float64
+	evidence    func(data []byte) []string
+	create      func(data []byte) (cartMapper, error)
+}
+
+// the order of registration is significant. when two formats have the same
+// confidence for the same data, the one registered first is chosen
+var registry []registryEntry
+
+func lookupMapper(id string) (registryEntry, bool) {
+	id = strings.ToUpper(id)
+	for _, r := range registry {
+		if strings.ToUpper(r.id) == id {
+			return r, true
+		}
+	}
+	return registryEntry{}, false
+}
+
+// confidence values used by the built-in formats
+const (
+	// the format is the standard format for the size of the data
+	confidenceSizeOnly = 0.1
+
+	// the format is the only format for the size of the data
+	confidenceUniqueSize = 0.5
+)
+
+func sizes(s ...int) func(int) bool {
+	return func(n int) bool {
+		for _, v := range s {
+			if n == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// evidence converts a boolean fingerprint function to a confidence function
+func evidence(f func([]byte) bool, confidence float64) func([]byte) float64 {
+	return func(data []byte) float64 {
+		if f(data) {
+			return confidence
+		}
+		return 0.0
+	}
+}
+
+func fixedConfidence(confidence float64) func([]byte) float64 {
+	return func(_ []byte) float64 {
+		return confidence
+	}
+}
+
+func noFingerprint(_ []byte) float64 {
+	return 0.0
+}
+
+// describe returns an evidence function that always returns the same
+// description
+func describe(description string) func([]byte) []string {
+	return func(_ []byte) []string {
+		return []string{description}
+	}
+}
+
+func noEvidence(_ []byte) []string {
+	return nil
+}
+
+func registerBuiltin(id string, size func(int) bool, fingerprint func([]byte) float64, evidence func([]byte) []string, create func([]byte) (cartMapper, error)) {
+	registry = append(registry, registryEntry{
+		id:          id,
+		size:        size,
+		fingerprint: fingerprint,
+		evidence:    evidence,
+		create:      create,
+	})
+}
+
+func init() {
+	bankswitched := sizes(8192, 16384, 32768, 65536, 131072, 262144, 524288)
+	standard := describe("standard format for size")
+	unique := describe("only format for size")
+
+	registerBuiltin("2k", sizes(2048), fixedConfidence(confidenceSizeOnly), standard, newAtari2k)
+	registerBuiltin("4k", sizes(4096), fixedConfidence(confidenceSizeOnly), standard, newAtari4k)
+	registerBuiltin("F8", sizes(8192), fixedConfidence(confidenceSizeOnly), standard, newAtari8k)
+	registerBuiltin("F6", sizes(16384), fixedConfidence(confidenceSizeOnly), standard, newAtari16k)
+	registerBuiltin("F4", sizes(32768), fixedConfidence(confidenceSizeOnly), standard, newAtari32k)
+	registerBuiltin("EF", sizes(65536), fingerprintEFConfidence, evidenceEF, newAtari64k)
+	registerBuiltin("FA", sizes(12288), fixedConfidence(confidenceUniqueSize), unique, newCBS)
+	registerBuiltin("E0", sizes(8192), evidence(fingerprintParkerBros, 0.8), fingerprintPatternsParkerBros.evidence, newparkerBros)
+	registerBuiltin("E7", sizes(16384), evidence(fingerprintMnetwork, 0.8), fingerprintPatternsMnetwork.evidence, newMnetwork)
+	registerBuiltin("3F", bankswitched, evidence(fingerprintTigervision, 0.85), fingerprintPatternsTigervision.evidence, newTigervision)
+	registerBuiltin("3E", bankswitched, evidence(fingerprintTigervisionRAM, 0.9), evidenceTigervisionRAM, newTigervisionRAM)
+	registerBuiltin("CV", sizes(2048, 4096), evidence(fingerprintCommaVid, 0.7), fingerprintPatternsCommaVid.evidence, newCommaVid)
+	registerBuiltin("UA", sizes(8192), evidence(fingerprintUA, 0.75), fingerprintPatternsUA.evidence, newUA)
+	registerBuiltin("0840", sizes(8192), evidence(fingerprintEconobanking, 0.7), fingerprintPatternsEconobanking.evidence, newEconobanking)
+	registerBuiltin("AR", func(n int) bool { return n > 0 && n%superchargerLoadSize == 0 }, fixedConfidence(confidenceUniqueSize), superchargerEvidence, newSupercharger)
+	registerBuiltin("F0", sizes(65536), fixedConfidence(confidenceSizeOnly), describe("single title format for size"), newMegaboy)
+	registerBuiltin("SB", sizes(65536, 131072, 262144, 524288), fingerprintSuperbankConfidence, fingerprintPatternsSuperbank.evidence, newSuperbank)
+	registerBuiltin("X07", sizes(65536), evidence(fingerprintX07, 0.7), fingerprintPatternsX07.evidence, newX07)
+	registerBuiltin("DPC", sizes(10240, 10495), fixedConfidence(confidenceUniqueSize), unique, newDPCmapper)
+	registerBuiltin("DPC+", sizes(dpcPlusFileSize-dpcPlusDriverSize, dpcPlusFileSize), fingerprintDPCplusConfidence, fingerprintPatternsDPCplus.evidence, newDPCplus)
+	registerBuiltin("CDF", sizes(cdfFileSize), evidence(fingerprintCDF, 0.95), fingerprintPatternsCDF.evidence, newCDF)
+
+	// CDFJ is a synonym for CDF. the version of the format is detected by the
+	// CDF mapper
+	registerBuiltin("CDFJ", sizes(cdfFileSize), noFingerprint, noEvidence, newCDF)
+}
+
+func superchargerEvidence(data []byte) []string {
+	return []string{fmt.Sprintf("%d loads of %d bytes", len(data)/superchargerLoadSize, superchargerLoadSize)}
+}
+
+// newDPC() returns the concrete type rather than the cartMapper interface
+func newDPCmapper(data []byte) (cartMapper, error) {
+	cart, err := newDPC(data)
+	if err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// EF is the standard format for 64k cartridges. F0 has the same size-only
+// confidence but is registered after EF and so is ranked below it. F0 is only
+// used by the Megaboy cartridge
+func fingerprintEFConfidence(data []byte) float64 {
+	if fingerprintEF(data) {
+		return 0.75
+	}
+	return confidenceSizeOnly
+}
+
+func evidenceEF(data []byte) []string {
+	if ev := fingerprintPatternsEF.evidence(data); len(ev) > 0 {
+		return ev
+	}
+	return []string{"standard format for size"}
+}
+
+// superbank is the standard format for cartridges larger than 64k
+func fingerprintSuperbankConfidence(data []byte) float64 {
+	if fingerprintSuperbank(data) {
+		return 0.65
+	}
+	if len(data) > 65536 {
+		return confidenceSizeOnly
+	}
+	return 0.0
+}
+
+// DPC+ files without the Harmony driver have a unique size
+func fingerprintDPCplusConfidence(data []byte) float64 {
+	if fingerprintDPCplus(data) {
+		return 0.95
+	}
+	if len(data) == dpcPlusFileSize-dpcPlusDriverSize {
+		return confidenceUniqueSize
+	}
+	return 0.0
+}
+
+// FingerprintResult is the confidence with which a format matches the
+// cartridge data.
+type FingerprintResult struct {
+	ID         string
+	Confidence float64
+
+	// description of the evidence found in the data for the format
+	Evidence []string
+}
+
+func (r FingerprintResult) String() string {
+	return fmt.Sprintf("%s (%.2f)", r.ID, r.Confidence)
+}
+
+type fingerprintCandidate struct {
+	FingerprintResult
+	entry registryEntry
+}
+
+// fingerprintCandidates returns every registered format that supports the
+// size of the data, ordered by confidence.
+func fingerprintCandidates(data []byte) []fingerprintCandidate {
+	candidates := make([]fingerprintCandidate, 0)
+
+	for _, r := range registry {
+		if !r.size(len(data)) {
+			continue
+		}
+		candidates = append(candidates, fingerprintCandidate{
+			FingerprintResult: FingerprintResult{
+				ID:         r.id,
+				Confidence: r.fingerprint(data),
+				Evidence:   r.evidence(data),
+			},
+			entry: r,
+		})
+	}
+
+	// stable sort preserves the order of registration for candidates of
+	// equal confidence
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+
+	return candidates
+}
+
+// externalMapper adapts the Mapper interface to the cartMapper interface
+type externalMapper struct {
+	m Mapper
+}
+
+func (em *externalMapper) String() string {
+	return em.m.String()
+}
+
+func (em *externalMapper) initialise() {
+	em.m.Initialise()
+}
+
+func (em *externalMapper) format() string {
+	return em.m.Format()
+}
+
+func (em *externalMapper) read(addr uint16) (uint8, error) {
+	return em.m.Read(addr)
+}
+
+func (em *externalMapper) write(addr uint16, data uint8) error {
+	return em.m.Write(addr, data)
+}
+
+func (em *externalMapper) numBanks() int {
+	return em.m.NumBanks()
+}
+
+func (em *externalMapper) getBank(addr uint16) int {
+	return em.m.GetBank(addr)
+}
+
+func (em *externalMapper) setBank(addr uint16, bank int) error {
+	return em.m.SetBank(addr, bank)
+}
+
+func (em *externalMapper) saveState() interface{} {
+	return em.m.SaveState()
+}
+
+func (em *externalMapper) restoreState(state interface{}) error {
+	return em.m.RestoreState(state)
+}
+
+func (em *externalMapper) listen(addr uint16, data uint8) {
+	em.m.Listen(addr, data)
+}
+
+func (em *externalMapper) poke(addr uint16, data uint8) error {
+	return em.m.Poke(addr, data)
+}
+
+func (em *externalMapper) patch(offset uint16, data uint8) error {
+	return em.m.Patch(offset, data)
+}
+
+func (em *externalMapper) getRAMinfo() []RAMinfo {
+	return em.m.GetRAMinfo()
+}
+
+func (em *externalMapper) step() {
+	em.m.Step()
+}
+
+func (em *externalMapper) busActivity(addr uint16) {
+	if m, ok := em.m.(interface{ BusActivity(addr uint16) }); ok {
+		m.BusActivity(addr)
+	}
+}
+
+func (em *externalMapper) addSuperchip() bool {
+	if m, ok := em.m.(interface{ AddSuperchip() bool }); ok {
+		return m.AddSuperchip()
+	}
+	return false
+}
+
+func (em *externalMapper) hotspots() []Hotspot {
+	if m, ok := em.m.(interface{ Hotspots() []Hotspot }); ok {
+		return m.Hotspots()
+	}
+	return nil
+}
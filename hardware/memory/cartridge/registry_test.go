@@ -0,0 +1,38 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package cartridge_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+)
+
+func TestBlank64kFingerprint(t *testing.T) {
+	// a 64k cartridge without any evidence of a format is EF and not the
+	// single title F0 format
+	rep := cartridge.FingerprintData(make([]byte, 65536))
+	if rep.Candidates[0].ID != "EF" {
+		t.Errorf("EF not chosen for blank 64k cartridge (%s chosen instead)", rep.Candidates[0].ID)
+	}
+	if confidence(rep, "EF") < 0.1 {
+		t.Errorf("EF confidence too low for blank 64k cartridge (%.2f)", confidence(rep, "EF"))
+	}
+}
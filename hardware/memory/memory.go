@@ -46,13 +46,15 @@ type VCSMemory struct {
 	// impact on performance so they can stay for now:
 	//
 	//  . a note of the last (mapped) memory address to be accessed
+	//  . the same address before it was mapped (as it appeared on the bus)
 	//  . the value that was written/read from the last address accessed
 	//  . whether the last addres accessed was written or read
 	//  . the ID of the last memory access (currently a timestamp)
-	LastAccessAddress uint16
-	LastAccessValue   uint8
-	LastAccessWrite   bool
-	LastAccessID      int
+	LastAccessAddress  uint16
+	LastAccessUnmapped uint16
+	LastAccessValue    uint8
+	LastAccessWrite    bool
+	LastAccessID       int
 
 	// accessCount is incremented every time memory is read or written to.  the
 	// current value of accessCount is noted every read and write and
@@ -61,6 +63,10 @@ type VCSMemory struct {
 	// for practical purposes, the cycle period of type int is sufficiently
 	// large as to allow us to consider LastAccessID to be unique.
 	accessCount int
+
+	// the bus tracer is notified of every access of the address bus. nil if
+	// there is no tracer. see SetBusTracer()
+	tracer BusTracer
 }
 
 // NewVCSMemory is the preferred method of initialisation for VCSMemory
@@ -125,6 +131,16 @@ func (mem *VCSMemory) read(address uint16, zeroPage bool) (uint8, error) {
 		return 0, err
 	}
 
+	// the bank is noted for the bus tracer before the access has a chance to
+	// change it
+	bank := -1
+	if mem.tracer != nil && ar == memorymap.Cartridge {
+		bank = mem.Cart.GetBank(ma)
+	}
+
+	// some cartridges need to see every access of the address bus
+	mem.Cart.BusActivity(address)
+
 	data, err := area.(bus.CPUBus).Read(ma)
 
 	// some memory areas do not change all the bits on the data bus, leaving
@@ -146,11 +162,16 @@ func (mem *VCSMemory) read(address uint16, zeroPage bool) (uint8, error) {
 	}
 
 	mem.LastAccessAddress = ma
+	mem.LastAccessUnmapped = address
 	mem.LastAccessWrite = false
 	mem.LastAccessValue = data
 	mem.LastAccessID = mem.accessCount
 	mem.accessCount++
 
+	if mem.tracer != nil {
+		mem.tracer.TraceAccess(address, ar, bank, data, false)
+	}
+
 	return data, err
 }
 
@@ -175,12 +196,27 @@ func (mem *VCSMemory) Write(address uint16, data uint8) error {
 		return err
 	}
 
+	// the bank is noted for the bus tracer before the access has a chance to
+	// change it
+	bank := -1
+	if mem.tracer != nil && ar == memorymap.Cartridge {
+		bank = mem.Cart.GetBank(ma)
+	}
+
+	// some cartridges need to see every access of the address bus
+	mem.Cart.BusActivity(address)
+
 	mem.LastAccessAddress = ma
+	mem.LastAccessUnmapped = address
 	mem.LastAccessWrite = true
 	mem.LastAccessValue = data
 	mem.LastAccessID = mem.accessCount
 	mem.accessCount++
 
+	if mem.tracer != nil {
+		mem.tracer.TraceAccess(address, ar, bank, data, true)
+	}
+
 	// see the commentary for the Listen() function in the Cartridge interface
 	// for an explanation for what is going on here. more to the point, we only
 	// need to "listen" if the mapped address is not in Cartridge space
@@ -0,0 +1,204 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/addresses"
+)
+
+// the AtariVox is connected to the SpeakJet through pins 1 and 2 of the
+// controller port. in terms of the SWCHA register these are the bits for
+// joystick up and down. the values are normalised as described in the
+// HandController type
+const (
+	speakJetData  = uint8(0x10)
+	speakJetReady = uint8(0x20)
+)
+
+// the SpeakJet receives data at 19200 baud. the CPU runs at approximately
+// 1.19MHz so each bit lasts for about 62 CPU cycles
+const speakJetCyclesPerBit = 62
+
+// SpeakJetCommand is a single byte received by the SpeakJet
+type SpeakJetCommand struct {
+	// the CPU cycle, counted from when the AtariVox was attached, on which the
+	// byte was received
+	Cycle uint64
+
+	Data uint8
+}
+
+func (cmd SpeakJetCommand) String() string {
+	var s string
+	switch {
+	case cmd.Data < 128:
+		s = "control"
+	case cmd.Data < 200:
+		s = "phoneme"
+	case cmd.Data < 255:
+		s = "sound effect"
+	default:
+		s = "end of phrase"
+	}
+	return fmt.Sprintf("%d: $%02x (%s)", cmd.Cycle, cmd.Data, s)
+}
+
+// SpeakJetListener is notified of every byte received by the SpeakJet
+type SpeakJetListener interface {
+	SpeakJet(SpeakJetCommand)
+}
+
+// AtariVox emulates the serial connection to the SpeakJet in the AtariVox
+// device. Speech is not produced but the command bytes sent by the CPU are
+// decoded and sent to the SpeakJetListener, if there is one.
+//
+// The EEPROM in the AtariVox is emulated by the SaveKey type.
+type AtariVox struct {
+	listener SpeakJetListener
+
+	// the number of CPU cycles since the AtariVox was attached
+	cycles uint64
+
+	// the current state of the serial line
+	line bool
+
+	// serial decoding. bit zero is the start bit, bits one to eight are the
+	// data bits and bit nine is the stop bit
+	receiving  bool
+	nextSample uint64
+	bit        int
+	data       uint8
+
+	// the number of bytes that were not terminated by a valid stop bit
+	FramingErrors int
+}
+
+func (vox *AtariVox) String() string {
+	s := fmt.Sprintf("AtariVox: cycle %d", vox.cycles)
+	if vox.receiving {
+		s = fmt.Sprintf("%s, receiving bit %d", s, vox.bit)
+	}
+	if vox.FramingErrors > 0 {
+		s = fmt.Sprintf("%s, %d framing errors", s, vox.FramingErrors)
+	}
+	return s
+}
+
+// serial is called whenever the state of the serial line might have changed
+func (vox *AtariVox) serial(line bool) {
+	// a falling edge while idle is the beginning of the start bit. the line
+	// is sampled in the middle of each bit
+	if !vox.receiving && vox.line && !line {
+		vox.receiving = true
+		vox.bit = 0
+		vox.data = 0
+		vox.nextSample = vox.cycles + speakJetCyclesPerBit/2
+	}
+	vox.line = line
+}
+
+// step is called once every CPU cycle
+func (vox *AtariVox) step() {
+	vox.cycles++
+
+	if !vox.receiving || vox.cycles < vox.nextSample {
+		return
+	}
+
+	switch {
+	case vox.bit == 0:
+		// the start bit was a glitch
+		if vox.line {
+			vox.receiving = false
+			return
+		}
+
+	case vox.bit < 9:
+		// data is sent least significant bit first
+		vox.data >>= 1
+		if vox.line {
+			vox.data |= 0x80
+		}
+
+	default:
+		vox.receiving = false
+		if !vox.line {
+			vox.FramingErrors++
+			return
+		}
+		if vox.listener != nil {
+			vox.listener.SpeakJet(SpeakJetCommand{Cycle: vox.cycles, Data: vox.data})
+		}
+		return
+	}
+
+	vox.bit++
+	vox.nextSample += speakJetCyclesPerBit
+}
+
+// SetSpeakJetListener sets the listener for any AtariVox attached to the
+// HandController, now or in the future. A value of nil removes the listener.
+func (hc *HandController) SetSpeakJetListener(l SpeakJetListener) {
+	hc.speakJetListener = l
+	if hc.AtariVox != nil {
+		hc.AtariVox.listener = l
+	}
+}
+
+// attachAtariVox creates an AtariVox for the HandController if one has not
+// already been created. the AtariVox shares the SaveKey with the SaveKeyType
+func (hc *HandController) attachAtariVox() error {
+	if err := hc.attachSaveKey(); err != nil {
+		return err
+	}
+
+	if hc.AtariVox == nil {
+		hc.AtariVox = &AtariVox{
+			listener: hc.speakJetListener,
+			line:     true,
+		}
+	}
+
+	return nil
+}
+
+// updateAtariVox is called whenever the CPU writes to SWCHA or SWACNT
+func (hc *HandController) updateAtariVox() {
+	if hc.ControllerType != AtariVoxType {
+		return
+	}
+
+	// the serial line is pulled high if it is not being driven by the CPU
+	hc.AtariVox.serial(hc.ddr&speakJetData == 0x00 || hc.swcha&speakJetData == speakJetData)
+
+	// speech isn't emulated so the SpeakJet is always ready to receive
+	bit := hc.normaliseOnWrite(speakJetReady)
+	hc.mem.riot.InputDeviceWrite(addresses.SWCHA, bit, ^bit)
+}
+
+// stepAtariVox is called every CPU cycle via Input.Step()
+func (hc *HandController) stepAtariVox() {
+	if hc.ControllerType != AtariVoxType {
+		return
+	}
+	hc.AtariVox.step()
+}
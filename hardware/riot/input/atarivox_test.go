@@ -0,0 +1,118 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/memory/bus"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+type speakJet struct {
+	data []uint8
+}
+
+func (sj *speakJet) SpeakJet(cmd input.SpeakJetCommand) {
+	sj.data = append(sj.data, cmd.Data)
+}
+
+// serial sends bits to the SpeakJet on hand controller one
+func serial(inp *input.Input, bits ...bool) {
+	for _, b := range bits {
+		v := uint8(0xfe)
+		if b {
+			v = 0xff
+		}
+		inp.Update(bus.ChipData{Name: "SWCHA", Value: v})
+
+		// 19200 baud
+		for i := 0; i < 62; i++ {
+			inp.Step()
+		}
+	}
+}
+
+// serialByte sends the start bit, the data bits (least significant bit first)
+// and the stop bit
+func serialByte(inp *input.Input, v uint8, stop bool) {
+	bits := []bool{false}
+	for i := 0; i < 8; i++ {
+		bits = append(bits, v&(0x01<<i) != 0x00)
+	}
+	bits = append(bits, stop)
+	serial(inp, bits...)
+}
+
+func TestAtariVox(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sj := &speakJet{}
+	inp.HandController1.SetSpeakJetListener(sj)
+	inp.HandController1.SaveKey = input.NewSaveKey("")
+	if err := inp.HandController1.SwitchType(input.AtariVoxType); err != nil {
+		t.Fatal(err)
+	}
+
+	// serial data line is an output
+	inp.Update(bus.ChipData{Name: "SWACNT", Value: 0x01})
+
+	expected := []uint8{0x14, 0x80, 0xff, 0x00}
+	for _, v := range expected {
+		serialByte(inp, v, true)
+		serial(inp, true, true)
+	}
+
+	if len(sj.data) != len(expected) {
+		t.Fatalf("expected %d bytes to be received, got %d", len(expected), len(sj.data))
+	}
+	for i := range expected {
+		if sj.data[i] != expected[i] {
+			t.Errorf("expected %#02x, got %#02x", expected[i], sj.data[i])
+		}
+	}
+
+	// byte without a stop bit is a framing error
+	serialByte(inp, 0x55, false)
+	serial(inp, true, true)
+	if inp.HandController1.AtariVox.FramingErrors != 1 {
+		t.Errorf("expected framing error")
+	}
+	if len(sj.data) != len(expected) {
+		t.Errorf("unexpected byte received after framing error")
+	}
+
+	// speakjet is always ready
+	v, err := mem.Read(0x0280)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v&0x02 != 0x02 {
+		t.Errorf("expected SpeakJet to be ready")
+	}
+}
@@ -40,6 +40,20 @@
 // AttachPlayback() function. The CheckInput function of the Playback interface
 // can then be used to check for Events.
 //
+// Macros can be attached to a port through the port's Macros() function.
+// Events from the user that are sent to the Handle() function of the Macros
+// type, rather than the Handle() function of the port, can trigger sequences of
+// timed events. This is how turbo fire is implemented. Macro definitions can
+// be loaded from a file with the Load() function of the Macros type. Each line
+// of the file is a single macro in the format accepted by ParseMacro().
+// Empty lines and lines beginning with # are ignored. For example:
+//
+//	# press fire and up together with the Genesis pad's C button
+//	chord, GenesisButtonC, once, Fire:true, Up:true
+//
+//	# turbo fire, changing state every three frames
+//	turbo, Fire, repeat, Fire:true/3, Fire:false/3
+//
 // The Playback interface is intended as the counterpart to the EventRecorder
 // interface, but it could theoretically be used in other contexts.
 //
@@ -0,0 +1,60 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import "math"
+
+// the driving controller produces a two-bit gray code on pins 1 and 2 of the
+// controller port. in terms of the SWCHA register these are the bits for
+// joystick up and down. the values are normalised as described in the
+// HandController type
+var drivingGrayCode = [4]uint8{0x30, 0x10, 0x00, 0x20}
+
+// the number of distinct positions in one revolution of the driving controller
+const drivingPositions = 16
+
+// the driving type implements the driving controller (as used by Indy 500)
+type driving struct {
+	// the current position of the controller. only the bottom two bits are
+	// significant to the VCS
+	position int
+}
+
+// gray returns the value that should be written to SWCHA for the current
+// position. the bits that are not used by the controller are set high.
+func (d driving) gray() uint8 {
+	return 0xc0 | drivingGrayCode[d.position&0x03]
+}
+
+// step the controller by the number of positions. positive values turn the
+// controller clockwise
+func (d *driving) step(n int) {
+	d.position = (d.position + n) % drivingPositions
+	if d.position < 0 {
+		d.position += drivingPositions
+	}
+}
+
+// setAngle sets the position of the controller from a fraction of a complete
+// revolution. values outside of the range 0.0 to 1.0 are wrapped
+func (d *driving) setAngle(f float32) {
+	f -= float32(math.Floor(float64(f)))
+	d.position = int(f*drivingPositions) % drivingPositions
+}
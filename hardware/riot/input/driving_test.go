@@ -0,0 +1,96 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+func TestDriving(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc := inp.HandController0
+	if err := hc.SwitchType(input.DrivingType); err != nil {
+		t.Fatal(err)
+	}
+
+	swcha := func(expected uint8) {
+		t.Helper()
+		v, err := mem.Read(0x0280)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v&0xf0 != expected {
+			t.Errorf("expected SWCHA of %#02x, got %#02x", expected, v&0xf0)
+		}
+	}
+
+	swcha(0xf0)
+
+	// clockwise
+	for _, e := range []uint8{0xd0, 0xc0, 0xe0, 0xf0, 0xd0} {
+		if err := hc.Handle(input.DrivingStep, float32(1)); err != nil {
+			t.Fatal(err)
+		}
+		swcha(e)
+	}
+
+	// anti-clockwise
+	for _, e := range []uint8{0xf0, 0xe0} {
+		if err := hc.Handle(input.DrivingStep, float32(-1)); err != nil {
+			t.Fatal(err)
+		}
+		swcha(e)
+	}
+
+	// absolute angles
+	if err := hc.Handle(input.DrivingAngle, float32(0.0625)); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0xd0)
+	if err := hc.Handle(input.DrivingAngle, float32(-0.0625)); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0xe0)
+
+	// wrong value type
+	if err := hc.Handle(input.DrivingStep, 1); err == nil {
+		t.Errorf("expected error for bad event value")
+	}
+
+	// driving controller does not switch away to the joystick when the fire
+	// button is pressed
+	if err := hc.Handle(input.Fire, true); err != nil {
+		t.Fatal(err)
+	}
+	if hc.ControllerType != input.DrivingType {
+		t.Errorf("unexpected switch from driving controller")
+	}
+}
@@ -19,6 +19,13 @@
 
 package input
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
 // Event represents the possible actions that can be performed by the user
 // when interacting with the console
 type Event string
@@ -49,9 +56,38 @@ const (
 	PanelTogglePlayer0Pro Event = "PanelTogglePlayer0Pro" // nil
 	PanelTogglePlayer1Pro Event = "PanelTogglePlayer1Pro" // nil
 
-	// paddles
-	PaddleFire Event = "PaddleFire" // bool
-	PaddleSet  Event = "PaddleSet"  // float64
+	// paddles. each controller port has a pair of paddles. the B events are
+	// for the second paddle in the pair
+	PaddleFire  Event = "PaddleFire"  // bool
+	PaddleSet   Event = "PaddleSet"   // float32
+	PaddleFireB Event = "PaddleFireB" // bool
+	PaddleSetB  Event = "PaddleSetB"  // float32
+
+	// driving controller. steps are relative movements, positive values being
+	// clockwise. angles are absolute and are a fraction of one revolution.
+	// both values are float32 so that they survive a recording unchanged
+	DrivingStep  Event = "DrivingStep"  // float32
+	DrivingAngle Event = "DrivingAngle" // float32
+
+	// booster grip and genesis pad buttons that are in addition to the
+	// joystick fire button
+	BoosterGripTrigger Event = "BoosterGripTrigger" // bool
+	BoosterGripBooster Event = "BoosterGripBooster" // bool
+	GenesisButtonC     Event = "GenesisButtonC"     // bool
+
+	// trak-ball and mice. values are relative movements on the horizontal (X)
+	// or vertical (Y) axis. positive values are to the right and down
+	TrakBallX   Event = "TrakBallX"   // float32
+	TrakBallY   Event = "TrakBallY"   // float32
+	AmigaMouseX Event = "AmigaMouseX" // float32
+	AmigaMouseY Event = "AmigaMouseY" // float32
+	STMouseX    Event = "STMouseX"    // float32
+	STMouseY    Event = "STMouseY"    // float32
+
+	// light gun aim point. values are absolute and are a fraction of the
+	// visible screen. the trigger is operated with the Fire event
+	LightGunX Event = "LightGunX" // float32
+	LightGunY Event = "LightGunY" // float32
 
 	// keypad (only need down event)
 	KeypadDown Event = "KeypadDown" // rune
@@ -60,8 +96,74 @@ const (
 	PanelPowerOff Event = "PanelPowerOff" // nil
 )
 
+// EventList is a list of all the events defined above
+var EventList = []Event{
+	NoEvent, Unplug,
+	Fire, Up, Down, Left, Right,
+	PanelSelect, PanelReset,
+	PanelSetColor, PanelSetPlayer0Pro, PanelSetPlayer1Pro,
+	PanelToggleColor, PanelTogglePlayer0Pro, PanelTogglePlayer1Pro,
+	PaddleFire, PaddleSet, PaddleFireB, PaddleSetB,
+	DrivingStep, DrivingAngle,
+	BoosterGripTrigger, BoosterGripBooster, GenesisButtonC,
+	TrakBallX, TrakBallY, AmigaMouseX, AmigaMouseY, STMouseX, STMouseY,
+	LightGunX, LightGunY,
+	KeypadDown, KeypadUp,
+	PanelPowerOff,
+}
+
 // EventData is the value associated with the event. The underlying type
 // should be restricted to bool, float32, or int. string is also acceptable but
 // for simplicity of playback parsers, "true" or "false" should not be used and
 // numbers should be represented by float32 or int.
 type EventData interface{}
+
+// ParseEvent parses a string of the form <event>[:<data>]. The event name must
+// be one of those in EventList, although the case is not important. The data
+// is optional and is interpreted according to the event. KeypadDown events
+// require a single character, other data is either a number (returned as
+// float32) or a bool.
+func ParseEvent(s string) (Event, EventData, error) {
+	// note that we only split on the first separator because the data can
+	// contain the separator (eg. KeypadDown::)
+	parts := strings.SplitN(s, ":", 2)
+
+	event := NoEvent
+	name := strings.TrimSpace(parts[0])
+	for _, e := range EventList {
+		if strings.EqualFold(string(e), name) {
+			event = e
+			break // for loop
+		}
+	}
+	if event == NoEvent && !strings.EqualFold(string(NoEvent), name) {
+		return NoEvent, nil, fmt.Errorf("unrecognised event (%s)", name)
+	}
+
+	if len(parts) == 1 {
+		return event, nil, nil
+	}
+
+	data := strings.TrimSpace(parts[1])
+
+	// the keypad requires a rune rather than a number
+	if event == KeypadDown {
+		r, n := utf8.DecodeRuneInString(data)
+		if n == 0 || n != len(data) {
+			return event, nil, fmt.Errorf("KeypadDown requires a single character")
+		}
+		return event, r, nil
+	}
+
+	// as with the recorder package, the order of these conversions is
+	// important. try numbers before bools
+	if f, err := strconv.ParseFloat(data, 32); err == nil {
+		return event, float32(f), nil
+	}
+
+	if b, err := strconv.ParseBool(data); err == nil {
+		return event, b, nil
+	}
+
+	return event, nil, fmt.Errorf("unrecognised data for %s (%s)", event, data)
+}
@@ -0,0 +1,76 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import "github.com/jetsetilly/gopher2600/hardware/memory/addresses"
+
+// some controllers have more buttons than the joystick. these extra buttons
+// are connected to the paddle lines (pins 5 and 9) of the controller port.
+//
+// pressing a button connects the paddle line directly to the supply voltage
+// so the capacitor is charged immediately. the button will still read as
+// unpressed while the paddle lines are grounded however.
+type extraButtons struct {
+	// the TIA registers for pin 9 and pin 5 respectively
+	lines [2]addresses.ChipRegister
+
+	pressed [2]bool
+}
+
+// indexes into the extraButtons arrays
+const (
+	pin9 = iota
+	pin5
+)
+
+// usesExtraButtons returns true if the controller type has buttons connected
+// to the paddle lines
+func (c ControllerType) usesExtraButtons() bool {
+	return c == BoosterGripType || c == GenesisType
+}
+
+// usesStickAxis returns true if the controller type has the same directional
+// control as the joystick
+func (c ControllerType) usesStickAxis() bool {
+	return c == JoystickType || c.usesExtraButtons()
+}
+
+// pressExtraButton sets the state of the button on the specified pin
+func (hc *HandController) pressExtraButton(pin int, pressed bool) {
+	hc.extra.pressed[pin] = pressed
+	hc.writeExtraButtons()
+}
+
+// writeExtraButtons writes the state of the extra buttons to the TIA. called
+// whenever the state of a button changes or when the paddle lines are
+// grounded or released
+func (hc *HandController) writeExtraButtons() {
+	if !hc.ControllerType.usesExtraButtons() {
+		return
+	}
+
+	for i, l := range hc.extra.lines {
+		if hc.extra.pressed[i] && !hc.control.groundPaddles {
+			hc.mem.tia.InputDeviceWrite(l, 0x80, 0x00)
+		} else {
+			hc.mem.tia.InputDeviceWrite(l, 0x00, 0x00)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+func TestBoosterGrip(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inpt := func(address uint16, expected uint8) {
+		t.Helper()
+		v, err := mem.Read(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v&0x80 != expected {
+			t.Errorf("expected %#02x in bit 7 of address %#02x, got %#02x", expected, address, v&0x80)
+		}
+	}
+
+	hc := inp.HandController1
+	if err := hc.SwitchType(input.BoosterGripType); err != nil {
+		t.Fatal(err)
+	}
+
+	// INPT2 and INPT3
+	inpt(0x0a, 0x00)
+	inpt(0x0b, 0x00)
+
+	if err := hc.Handle(input.BoosterGripTrigger, true); err != nil {
+		t.Fatal(err)
+	}
+	inpt(0x0a, 0x00)
+	inpt(0x0b, 0x80)
+
+	if err := hc.Handle(input.BoosterGripBooster, true); err != nil {
+		t.Fatal(err)
+	}
+	inpt(0x0a, 0x80)
+	inpt(0x0b, 0x80)
+
+	// buttons read as unpressed while the paddle lines are grounded
+	inp.VBlankBits.SetGroundPaddles(true)
+	inpt(0x0a, 0x00)
+	inpt(0x0b, 0x00)
+	inp.VBlankBits.SetGroundPaddles(false)
+	inpt(0x0a, 0x80)
+	inpt(0x0b, 0x80)
+
+	if err := hc.Handle(input.BoosterGripTrigger, false); err != nil {
+		t.Fatal(err)
+	}
+	inpt(0x0b, 0x00)
+
+	// joystick events do not switch away from the booster grip
+	if err := hc.Handle(input.Left, true); err != nil {
+		t.Fatal(err)
+	}
+	if hc.ControllerType != input.BoosterGripType {
+		t.Errorf("unexpected switch from booster grip")
+	}
+
+	// genesis pad button C uses the same line as the booster grip trigger
+	if err := hc.SwitchType(input.GenesisType); err != nil {
+		t.Fatal(err)
+	}
+	if err := hc.Handle(input.GenesisButtonC, true); err != nil {
+		t.Fatal(err)
+	}
+	inpt(0x0b, 0x80)
+}
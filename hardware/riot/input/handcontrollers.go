@@ -32,9 +32,9 @@ import (
 // games, the paddle/keypad will be activated once the user starts using the
 // corresponding controls.
 //
-// if a paddle/keypad ROM requires paddle/keypad probing from the instant
-// the machine starts (are there any examples of this?) then we will need to
-// initialise the hand controller accordingly, using the setup system.
+// paddle/keypad ROMs that require paddle/keypad probing from the instant the
+// machine starts are initialised by the setup system, with the help of the
+// disassembly.ControllerTypes() analysis.
 type ControllerType int
 
 // List of allowed ControllerTypes
@@ -42,10 +42,19 @@ const (
 	JoystickType ControllerType = iota
 	PaddleType
 	KeypadType
+	SaveKeyType
+	AtariVoxType
+	DrivingType
+	TrakBallType
+	AmigaMouseType
+	STMouseType
+	BoosterGripType
+	GenesisType
+	LightGunType
 )
 
 // ControllerTypeList is a list of all possible string representations of the Interval type
-var ControllerTypeList = []string{"Joystick", "Paddle", "Keypad"}
+var ControllerTypeList = []string{"Joystick", "Paddle", "Keypad", "SaveKey", "AtariVox", "Driving", "TrakBall", "AmigaMouse", "STMouse", "BoosterGrip", "Genesis", "LightGun"}
 
 func (c ControllerType) String() string {
 	switch c {
@@ -55,10 +64,41 @@ func (c ControllerType) String() string {
 		return "Paddle"
 	case KeypadType:
 		return "Keypad"
+	case SaveKeyType:
+		return "SaveKey"
+	case AtariVoxType:
+		return "AtariVox"
+	case DrivingType:
+		return "Driving"
+	case TrakBallType:
+		return "TrakBall"
+	case AmigaMouseType:
+		return "AmigaMouse"
+	case STMouseType:
+		return "STMouse"
+	case BoosterGripType:
+		return "BoosterGrip"
+	case GenesisType:
+		return "Genesis"
+	case LightGunType:
+		return "LightGun"
 	}
 	panic("unknown controller type")
 }
 
+// usesStickButton returns true if the controller type uses the same fire
+// button as the joystick. the light gun uses the fire button line for its
+// light sensor
+func (c ControllerType) usesStickButton() bool {
+	return c == JoystickType || c == DrivingType || c.isPointer() || c.usesExtraButtons() || c == LightGunType
+}
+
+// autoDetectable returns true if the controller type can be detected by
+// watching how the port is used
+func (c ControllerType) autoDetectable() bool {
+	return c == JoystickType || c == PaddleType || c == KeypadType
+}
+
 // HandController represents the "joystick" port on the VCS. The different
 // devices (joysticks, paddles, etc.) send events to the Handle() function.
 //
@@ -76,9 +116,26 @@ type HandController struct {
 	AutoControllerType bool
 
 	// controller types
-	stick  stick
-	paddle paddle
-	keypad keypad
+	stick   stick
+	paddles [2]paddle
+	keypad  keypad
+	driving driving
+
+	// state of the pseudo-random number generator used to add jitter to the
+	// paddles
+	paddleNoise uint32
+
+	pointer  pointer
+	extra    extraButtons
+	lightGun lightGun
+
+	// the SaveKey is created the first time the SaveKeyType is selected
+	SaveKey *SaveKey
+
+	// the AtariVox is created the first time the AtariVoxType is selected.
+	// the AtariVox also uses the SaveKey field for its EEPROM
+	AtariVox         *AtariVox
+	speakJetListener SpeakJetListener
 
 	// data direction register. for simplicity, the bits should be normalised
 	// such that only the upper nibble is used. in reality, player 0
@@ -86,6 +143,10 @@ type HandController struct {
 	// the lower nibble.
 	ddr uint8
 
+	// the most recent value written to SWCHA by the CPU. normalised in the
+	// same way as the ddr field
+	swcha uint8
+
 	// the two hand controllers, for both joysticks and keypads, share
 	// registers for certain values. In each instance where this is the case,
 	// HandController0 uses the upper nibble and HandControll1er1 uses the
@@ -120,49 +181,13 @@ type stick struct {
 // the controller mode switches to paddle type
 const paddleTouchReq = 3
 
-// the value used to write to the paddle fire button. the value is mased by the
-// paddle.buttonMask value before writing to SWCHA
+// the value used to write to the paddle fire button. the value is masked by the
+// paddle.fireBit value before writing to SWCHA
 const paddleFire = 0xff
 
 // as above but for when the first button is released
 const paddleNoFire = 0x00
 
-// !!TODO: accurate paddle timings and sensitivity
-//
-// for now, our best guess is 0.01. no idea if this value is correct but it
-// feels good during play so I'm going to go with it.
-//
-// justification: if the paddle resistor can take a value between 0.0 and 1.0
-// then the maximum number of ticks required to increase the capacitor charge
-// by 1 is 100. The maximum charge is 255 so it takes a maximum of 25500 ticks
-// to fill the capacitor.
-const paddleSensitivity = 0.01
-
-// the paddle type implements the "paddle" hand controller
-type paddle struct {
-	puckReg addresses.ChipRegister
-
-	buttonMask uint8
-
-	// values indicating paddle state
-	charge     uint8
-	resistance float32
-
-	// sensitivity governs the rate at which the controller capacitor fills.
-	// the tick value is increased by the sensitivity value every cycle; once
-	// it reaches or exceeds the resistance value, the charge value is
-	// increased.
-	sensitivity float32
-	ticks       float32
-
-	// count of how many times the paddle has touched the extreme values. we
-	// use this to help decide whether to switch controller types
-	touchLeft     int
-	touchRight    int
-	touchingLeft  bool
-	touchingRight bool
-}
-
 // the keypad type implements the keypad or "keyboard" controller
 type keypad struct {
 	column [3]addresses.ChipRegister
@@ -185,16 +210,18 @@ func NewHandController0(mem *inputMemory, control *VBlankBits) *HandController {
 			axis:      0xf0,
 			button:    stickButtonOff,
 		},
-		paddle: paddle{
-			puckReg:     addresses.INPT0,
-			buttonMask:  0x7f,
-			resistance:  0.0,
-			sensitivity: paddleSensitivity,
+		paddles: [2]paddle{
+			newPaddle(addresses.INPT0, 0x80),
+			newPaddle(addresses.INPT1, 0x40),
 		},
+		paddleNoise: 0x2600,
 		keypad: keypad{
 			column: [3]addresses.ChipRegister{addresses.INPT0, addresses.INPT1, addresses.INPT4},
 			key:    noKey,
 		},
+		extra: extraButtons{
+			lines: [2]addresses.ChipRegister{addresses.INPT0, addresses.INPT1},
+		},
 		normaliseOnRead:  func(n uint8) uint8 { return n & 0xf0 },
 		normaliseOnWrite: func(n uint8) uint8 { return n },
 		writeMask:        0x0f,
@@ -205,6 +232,7 @@ func NewHandController0(mem *inputMemory, control *VBlankBits) *HandController {
 		id:     HandControllerZeroID,
 		handle: hc.Handle,
 	}
+	hc.port.macros = newMacros(&hc.port)
 
 	// write initial joystick values
 	hc.writeSWCHA(hc.stick.axis, hc.writeMask)
@@ -226,16 +254,18 @@ func NewHandController1(mem *inputMemory, control *VBlankBits) *HandController {
 			axis:      0xf0,
 			button:    stickButtonOff,
 		},
-		paddle: paddle{
-			puckReg:     addresses.INPT1,
-			buttonMask:  0xbf,
-			resistance:  0.0,
-			sensitivity: paddleSensitivity,
+		paddles: [2]paddle{
+			newPaddle(addresses.INPT2, 0x80),
+			newPaddle(addresses.INPT3, 0x40),
 		},
+		paddleNoise: 0x2601,
 		keypad: keypad{
 			column: [3]addresses.ChipRegister{addresses.INPT2, addresses.INPT3, addresses.INPT5},
 			key:    noKey,
 		},
+		extra: extraButtons{
+			lines: [2]addresses.ChipRegister{addresses.INPT2, addresses.INPT3},
+		},
 		normaliseOnRead:  func(n uint8) uint8 { return (n & 0x0f) << 4 },
 		normaliseOnWrite: func(n uint8) uint8 { return n >> 4 },
 		writeMask:        0xf0,
@@ -246,6 +276,7 @@ func NewHandController1(mem *inputMemory, control *VBlankBits) *HandController {
 		id:     HandControllerOneID,
 		handle: hc.Handle,
 	}
+	hc.port.macros = newMacros(&hc.port)
 
 	// write initial joystick values
 	hc.writeSWCHA(hc.stick.axis, hc.writeMask)
@@ -271,8 +302,8 @@ func (hc *HandController) SetAuto(auto bool) {
 	hc.AutoControllerType = auto
 
 	// reset detection variables
-	hc.paddle.touchLeft = 0
-	hc.paddle.touchRight = 0
+	hc.paddles[0].touchLeft = 0
+	hc.paddles[0].touchRight = 0
 }
 
 // SwitchType causes the HandController to swich controller type. If the type
@@ -280,9 +311,12 @@ func (hc *HandController) SetAuto(auto bool) {
 // returned.
 func (hc *HandController) SwitchType(newType ControllerType) error {
 	// reset detection variables
-	hc.paddle.touchLeft = 0
-	hc.paddle.touchRight = 0
+	hc.paddles[0].touchLeft = 0
+	hc.paddles[0].touchRight = 0
 
+	// controller types that can't be detected automatically would be switched
+	// out immediately if auto-switching was left on. auto-switching is turned
+	// off for those types once the switch has succeeded
 	switch newType {
 	case JoystickType:
 		hc.ControllerType = JoystickType
@@ -293,11 +327,65 @@ func (hc *HandController) SwitchType(newType ControllerType) error {
 		hc.writeSWCHA(paddleFire, hc.writeMask)
 	case KeypadType:
 		hc.ControllerType = KeypadType
+	case SaveKeyType:
+		if err := hc.attachSaveKey(); err != nil {
+			return err
+		}
+		hc.ControllerType = SaveKeyType
+
+		hc.writeSWCHA(0xf0, hc.writeMask)
+		hc.updateSaveKey()
+	case AtariVoxType:
+		if err := hc.attachAtariVox(); err != nil {
+			return err
+		}
+		hc.ControllerType = AtariVoxType
+
+		hc.writeSWCHA(0xf0, hc.writeMask)
+		hc.updateSaveKey()
+		hc.updateAtariVox()
+	case DrivingType:
+		hc.ControllerType = DrivingType
+
+		hc.writeSWCHA(hc.driving.gray(), hc.writeMask)
+		hc.mem.tia.InputDeviceWrite(hc.stick.buttonReg, hc.stick.button, 0x00)
+	case BoosterGripType, GenesisType:
+		if hc.ControllerType != newType {
+			hc.extra.pressed = [2]bool{}
+		}
+		hc.ControllerType = newType
+
+		hc.writeSWCHA(hc.stick.axis, hc.writeMask)
+		hc.mem.tia.InputDeviceWrite(hc.stick.buttonReg, hc.stick.button, 0x00)
+		hc.writeExtraButtons()
+	case TrakBallType, AmigaMouseType, STMouseType:
+		// a different pointer device starts from scratch
+		if hc.ControllerType != newType {
+			hc.pointer = pointer{}
+		}
+		hc.ControllerType = newType
+
+		hc.writeSWCHA(hc.pointer.swcha(newType), hc.writeMask)
+		hc.mem.tia.InputDeviceWrite(hc.stick.buttonReg, hc.stick.button, 0x00)
+	case LightGunType:
+		if hc.ControllerType != LightGunType {
+			hc.lightGun.trigger = false
+			hc.lightGun.sense = false
+			hc.stick.button = stickButtonOff
+		}
+		hc.ControllerType = LightGunType
+
+		hc.writeSWCHA(hc.lightGun.swcha(), hc.writeMask)
+		hc.mem.tia.InputDeviceWrite(hc.stick.buttonReg, hc.stick.button, 0x00)
 
 	default:
 		return errors.New(errors.UnknownControllerType, newType)
 	}
 
+	if !newType.autoDetectable() {
+		hc.AutoControllerType = false
+	}
+
 	return nil
 }
 
@@ -316,7 +404,7 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 		}
 
 		// smart switch to joystick type
-		if hc.ControllerType != JoystickType {
+		if !hc.ControllerType.usesStickAxis() {
 			if hc.AutoControllerType {
 				if err := hc.SwitchType(JoystickType); err != nil {
 					return err
@@ -340,7 +428,7 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 		}
 
 		// smart switch to joystick type
-		if hc.ControllerType != JoystickType {
+		if !hc.ControllerType.usesStickAxis() {
 			if hc.AutoControllerType {
 				if err := hc.SwitchType(JoystickType); err != nil {
 					return err
@@ -364,7 +452,7 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 		}
 
 		// smart switch to joystick type
-		if hc.ControllerType != JoystickType {
+		if !hc.ControllerType.usesStickAxis() {
 			if hc.AutoControllerType {
 				if err := hc.SwitchType(JoystickType); err != nil {
 					return err
@@ -388,7 +476,7 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 		}
 
 		// smart switch to joystick type
-		if hc.ControllerType != JoystickType {
+		if !hc.ControllerType.usesStickAxis() {
 			if hc.AutoControllerType {
 				if err := hc.SwitchType(JoystickType); err != nil {
 					return err
@@ -411,8 +499,9 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 			return errors.New(errors.BadInputEventType, event, "bool")
 		}
 
-		// smart switch to joystick type
-		if hc.ControllerType != JoystickType {
+		// smart switch to joystick type. the driving controller uses the same
+		// fire button as the joystick
+		if !hc.ControllerType.usesStickButton() {
 			if hc.AutoControllerType {
 				if err := hc.SwitchType(JoystickType); err != nil {
 					return err
@@ -422,6 +511,13 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 			}
 		}
 
+		// the light gun trigger is on the joystick up line. the fire button
+		// line is driven by the light sensor (see stepLightGun())
+		if hc.ControllerType == LightGunType {
+			hc.fireLightGun(b)
+			break // switch
+		}
+
 		// record state of fire button regardless of latch bit. we need to know
 		// the physical state for when the latch bit is unset
 		if b {
@@ -447,14 +543,20 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 			return nil
 		}
 
-		var v uint8
+		hc.firePaddle(0, b)
 
-		if b {
-			v = paddleNoFire
-		} else {
-			v = paddleFire
+	case PaddleFireB:
+		b, ok := value.(bool)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "bool")
+		}
+
+		// no smart switch on paddle fire
+		if hc.ControllerType != PaddleType {
+			return nil
 		}
-		hc.writeSWCHA(v, hc.paddle.buttonMask)
+
+		hc.firePaddle(1, b)
 
 	case PaddleSet:
 		f, ok := value.(float32)
@@ -467,27 +569,27 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 		// lot more careful than with joystick smart-switching
 		if hc.ControllerType != PaddleType {
 			if hc.AutoControllerType {
-				if hc.paddle.touchLeft < paddleTouchReq {
+				if hc.paddles[0].touchLeft < paddleTouchReq {
 					if f < 0.1 {
-						if !hc.paddle.touchingLeft {
-							hc.paddle.touchLeft++
+						if !hc.paddles[0].touchingLeft {
+							hc.paddles[0].touchLeft++
 						}
-						hc.paddle.touchingLeft = true
+						hc.paddles[0].touchingLeft = true
 					} else {
-						hc.paddle.touchingLeft = false
+						hc.paddles[0].touchingLeft = false
 					}
 				}
-				if hc.paddle.touchRight < paddleTouchReq {
+				if hc.paddles[0].touchRight < paddleTouchReq {
 					if f > 0.9 {
-						if !hc.paddle.touchingRight {
-							hc.paddle.touchRight++
+						if !hc.paddles[0].touchingRight {
+							hc.paddles[0].touchRight++
 						}
-						hc.paddle.touchingRight = true
+						hc.paddles[0].touchingRight = true
 					} else {
-						hc.paddle.touchingRight = false
+						hc.paddles[0].touchingRight = false
 					}
 				}
-				if hc.paddle.touchLeft >= paddleTouchReq && hc.paddle.touchRight >= paddleTouchReq {
+				if hc.paddles[0].touchLeft >= paddleTouchReq && hc.paddles[0].touchRight >= paddleTouchReq {
 					if err := hc.SwitchType(PaddleType); err != nil {
 						return err
 					}
@@ -497,7 +599,133 @@ func (hc *HandController) Handle(event Event, value EventData) error {
 			}
 		}
 
-		hc.paddle.resistance = 1.0 - f
+		hc.paddles[0].set(f)
+
+	case PaddleSetB:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// smart switching is decided by the first paddle only
+		if hc.ControllerType != PaddleType {
+			return nil
+		}
+
+		hc.paddles[1].set(f)
+
+	case DrivingStep:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for the driving controller. it is too easily
+		// confused with the paddle
+		if hc.ControllerType != DrivingType {
+			return nil
+		}
+
+		hc.driving.step(int(f))
+		hc.writeSWCHA(hc.driving.gray(), hc.writeMask)
+
+	case DrivingAngle:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for the driving controller
+		if hc.ControllerType != DrivingType {
+			return nil
+		}
+
+		hc.driving.setAngle(f)
+		hc.writeSWCHA(hc.driving.gray(), hc.writeMask)
+
+	case BoosterGripTrigger, BoosterGripBooster:
+		b, ok := value.(bool)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "bool")
+		}
+
+		// no smart switch for the booster grip. it is indistinguishable from
+		// the joystick until the extra buttons are used
+		if hc.ControllerType != BoosterGripType {
+			return nil
+		}
+
+		if event == BoosterGripTrigger {
+			hc.pressExtraButton(pin5, b)
+		} else {
+			hc.pressExtraButton(pin9, b)
+		}
+
+	case GenesisButtonC:
+		b, ok := value.(bool)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "bool")
+		}
+
+		// no smart switch for the genesis pad
+		if hc.ControllerType != GenesisType {
+			return nil
+		}
+
+		hc.pressExtraButton(pin5, b)
+
+	case TrakBallX, TrakBallY:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for pointer devices. they are too easily confused
+		// with the paddle
+		if hc.ControllerType != TrakBallType {
+			return nil
+		}
+
+		hc.movePointer(event == TrakBallX, f)
+
+	case AmigaMouseX, AmigaMouseY:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for pointer devices
+		if hc.ControllerType != AmigaMouseType {
+			return nil
+		}
+
+		hc.movePointer(event == AmigaMouseX, f)
+
+	case STMouseX, STMouseY:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for pointer devices
+		if hc.ControllerType != STMouseType {
+			return nil
+		}
+
+		hc.movePointer(event == STMouseX, f)
+
+	case LightGunX, LightGunY:
+		f, ok := value.(float32)
+		if !ok {
+			return errors.New(errors.BadInputEventType, event, "float32")
+		}
+
+		// no smart switch for the light gun
+		if hc.ControllerType != LightGunType {
+			return nil
+		}
+
+		hc.aimLightGun(event == LightGunX, f)
 
 	case KeypadDown:
 		v, ok := value.(rune)
@@ -563,6 +791,17 @@ func (hc *HandController) setDDR(data uint8) {
 			hc.SwitchType(JoystickType)
 		}
 	}
+
+	hc.updateSaveKey()
+	hc.updateAtariVox()
+}
+
+// setSWCHA is called whenever SWCHA is written to by the CPU, after the value
+// has been written to memory.
+func (hc *HandController) setSWCHA(data uint8) {
+	hc.swcha = hc.normaliseOnRead(data)
+	hc.updateSaveKey()
+	hc.updateAtariVox()
 }
 
 // readKeypad() is called whenever SWCHA is tickled by the CPU. the state of
@@ -664,7 +903,7 @@ func (hc *HandController) readKeypad(data uint8) {
 // VBLANK bit 6 has been set. joystick button will latch, meaning that
 // releasing the fire button has no immediate effect
 func (hc *HandController) unlatch() {
-	if hc.ControllerType != JoystickType {
+	if !hc.ControllerType.usesStickButton() {
 		return
 	}
 
@@ -674,8 +913,13 @@ func (hc *HandController) unlatch() {
 	}
 }
 
-// VBLANK bit 7 has been set. input capacitor is grounded.
+// VBLANK bit 7 has been set or cleared. input capacitors are grounded or
+// released.
 func (hc *HandController) ground() {
+	// controllers with extra buttons on the paddle lines need to know about
+	// changes to the grounding state
+	hc.writeExtraButtons()
+
 	// don't allow grounding unless controller type is paddle type. if we don't
 	// then it will play havoc with keyboard controllers.
 	//
@@ -687,11 +931,20 @@ func (hc *HandController) ground() {
 		return
 	}
 
-	hc.paddle.charge = 0
-	hc.mem.riot.InputDeviceWrite(hc.paddle.puckReg, hc.paddle.charge, 0x00)
+	for i := range hc.paddles {
+		p := &hc.paddles[i]
+		if hc.control.groundPaddles {
+			p.charge = 0
+			p.triggered = false
+			hc.mem.tia.InputDeviceWrite(p.puckReg, 0x00, 0x00)
+		} else {
+			// the capacitor is about to start charging
+			p.updateRate(hc.jitter())
+		}
+	}
 }
 
-// recharge() is called every video step via Input.Step()
+// recharge() is called every CPU cycle via Input.Step()
 func (hc *HandController) recharge() {
 	// as in the case of ground() I'm not sure if restricting recharge() events
 	// to the paddle type is strictly necessary.
@@ -710,12 +963,21 @@ func (hc *HandController) recharge() {
 	// VBLANK. When this control bit is cleared the potentiometers begin to
 	// recharge the capacitors and the microprocessor measures the time required
 	// to detect a logic 1 at each input port."
-	if hc.paddle.charge < 255 {
-		hc.paddle.ticks += hc.paddle.sensitivity
-		if hc.paddle.ticks >= hc.paddle.resistance {
-			hc.paddle.ticks = 0
-			hc.paddle.charge++
-			hc.mem.tia.InputDeviceWrite(hc.paddle.puckReg, hc.paddle.charge, 0x00)
+	//
+	// the capacitors do not charge while they are grounded
+	if hc.control.groundPaddles {
+		return
+	}
+
+	for i := range hc.paddles {
+		p := &hc.paddles[i]
+		if p.triggered {
+			continue // for loop
+		}
+		p.charge += (1 - p.charge) * p.rate
+		if p.charge >= paddleThreshold {
+			p.triggered = true
+			hc.mem.tia.InputDeviceWrite(p.puckReg, 0x80, 0x00)
 		}
 	}
 }
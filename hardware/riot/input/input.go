@@ -122,6 +122,11 @@ func (inp *Input) Update(data bus.ChipData) bool {
 		// write data back to memory
 		inp.mem.riot.InputDeviceWrite(addresses.SWCHA, data.Value, 0x00)
 
+		// devices that communicate through SWCHA need to see the data after
+		// it has been written to memory
+		inp.HandController0.setSWCHA(data.Value)
+		inp.HandController1.setSWCHA(data.Value)
+
 	case "SWACNT":
 		inp.HandController0.setDDR(data.Value)
 		inp.HandController1.setDDR(data.Value)
@@ -154,4 +159,17 @@ func (inp *Input) Step() {
 	// step.
 	inp.HandController0.recharge()
 	inp.HandController1.recharge()
+
+	// the AtariVox needs to count cycles in order to decode the serial data
+	// sent to the SpeakJet
+	inp.HandController0.stepAtariVox()
+	inp.HandController1.stepAtariVox()
+
+	// movement of pointer devices is spread out over time
+	inp.HandController0.stepPointer()
+	inp.HandController1.stepPointer()
+
+	// the light gun senses the television's electron beam
+	inp.HandController0.stepLightGun()
+	inp.HandController1.stepLightGun()
 }
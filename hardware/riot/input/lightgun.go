@@ -0,0 +1,147 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import "github.com/jetsetilly/gopher2600/television"
+
+// the XG-1 light gun has a trigger connected to pin 1 of the controller port
+// (the joystick up direction) and a light sensor connected to pin 6 (the
+// joystick fire button). both lines are active low.
+//
+// the sensor is a phototransistor behind a lens. it sees a small area of the
+// screen just below and to the right of where the gun is pointing and it
+// reacts when the electron beam passes through that area, provided that the
+// pixel being drawn is bright enough.
+type lightGun struct {
+	// the aim point as a fraction of the visible screen
+	x float32
+	y float32
+
+	// whether the trigger is being pulled
+	trigger bool
+
+	// whether the sensor saw the beam on the most recent cycle
+	sense bool
+}
+
+// the number of pixels, to the right of the aim point, that can be seen by the
+// light sensor. the value is the same as used by the Stella emulator.
+const lightGunWidth = 15
+
+// the number of scanlines, below the aim point, that can be seen by the light
+// sensor
+const lightGunHeight = 2
+
+// the minimum luminance (in the range 0 to 7) of a pixel that the light sensor
+// will react to
+const lightGunLuminance = 4
+
+// the value written to SWCHA for the state of the trigger. only the pin 1
+// (up) bit is significant
+func (lg lightGun) swcha() uint8 {
+	if lg.trigger {
+		return 0xe0
+	}
+	return 0xf0
+}
+
+// aimLightGun sets the horizontal or vertical aim point of the light gun
+func (hc *HandController) aimLightGun(horiz bool, f float32) {
+	if f < 0.0 {
+		f = 0.0
+	} else if f > 1.0 {
+		f = 1.0
+	}
+
+	if horiz {
+		hc.lightGun.x = f
+	} else {
+		hc.lightGun.y = f
+	}
+}
+
+// fireLightGun sets the state of the light gun trigger
+func (hc *HandController) fireLightGun(pressed bool) {
+	hc.lightGun.trigger = pressed
+	hc.writeSWCHA(hc.lightGun.swcha(), hc.writeMask)
+}
+
+// senseLightGun returns true if the light sensor can see the pixel that has
+// just been drawn by the television
+func (hc *HandController) senseLightGun() bool {
+	if hc.tv == nil {
+		return false
+	}
+
+	sig := hc.tv.GetLastSignal()
+	if sig.VBlank || sig.Pixel == television.VideoBlack {
+		return false
+	}
+
+	// the luminance of a VCS color is in bits 1 to 3
+	if (int(sig.Pixel)&0x0e)>>1 < lightGunLuminance {
+		return false
+	}
+
+	scanline, err := hc.tv.GetState(television.ReqScanline)
+	if err != nil {
+		return false
+	}
+	horizPos, err := hc.tv.GetState(television.ReqHorizPos)
+	if err != nil {
+		return false
+	}
+
+	// convert aim point to television coordinates. the visible portion of
+	// the screen is assumed to be as described by the specification
+	spec := hc.tv.GetSpec()
+	x := int(hc.lightGun.x * television.HorizClksVisible)
+	y := spec.ScanlineTop + int(hc.lightGun.y*float32(spec.ScanlinesVisible))
+
+	dx := horizPos - x
+	dy := scanline - y
+
+	return dx >= 0 && dx < lightGunWidth && dy >= 0 && dy < lightGunHeight
+}
+
+// stepLightGun is called every CPU cycle via Input.Step(). the state of the
+// light sensor is checked against the television and written to the TIA
+func (hc *HandController) stepLightGun() {
+	if hc.ControllerType != LightGunType {
+		return
+	}
+
+	sense := hc.senseLightGun()
+	if sense == hc.lightGun.sense {
+		return
+	}
+	hc.lightGun.sense = sense
+
+	// the light sensor shares the fire button line with the joystick and so
+	// respects the latch bit in the same way
+	if sense {
+		hc.stick.button = stickButtonOn
+	} else {
+		hc.stick.button = stickButtonOff
+	}
+	if hc.stick.button == stickButtonOn || !hc.control.latchFireButton {
+		hc.mem.tia.InputDeviceWrite(hc.stick.buttonReg, hc.stick.button, 0x00)
+	}
+}
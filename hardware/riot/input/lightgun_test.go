@@ -0,0 +1,175 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// beam is a minimal implementation of the television.Television interface.
+// only the functions required by the light gun are implemented
+type beam struct {
+	television.Television
+	scanline int
+	horizPos int
+	pixel    television.ColorSignal
+}
+
+func (b *beam) GetState(request television.StateReq) (int, error) {
+	switch request {
+	case television.ReqScanline:
+		return b.scanline, nil
+	case television.ReqHorizPos:
+		return b.horizPos, nil
+	}
+	return 0, nil
+}
+
+func (b *beam) GetLastSignal() television.SignalAttributes {
+	return television.SignalAttributes{Pixel: b.pixel}
+}
+
+func (b *beam) GetSpec() *television.Specification {
+	return television.SpecNTSC
+}
+
+func TestLightGun(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tv := &beam{pixel: 0x0e}
+	hc := inp.HandController0
+	hc.AttachTV(tv)
+
+	swcha := func(expected uint8) {
+		t.Helper()
+		v, err := mem.Read(0x0280)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v&0xf0 != expected {
+			t.Errorf("expected SWCHA of %#02x, got %#02x", expected, v&0xf0)
+		}
+	}
+
+	inpt4 := func(expected uint8) {
+		t.Helper()
+		v, err := mem.Read(0x0c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v&0x80 != expected {
+			t.Errorf("expected INPT4 bit 7 of %#02x, got %#02x", expected, v&0x80)
+		}
+	}
+
+	if err := hc.SwitchType(input.LightGunType); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0xf0)
+	inpt4(0x80)
+
+	// trigger is on the joystick up line
+	if err := hc.Handle(input.Fire, true); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0xe0)
+	inpt4(0x80)
+	if err := hc.Handle(input.Fire, false); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0xf0)
+
+	// aim at the centre of the screen
+	if err := hc.Handle(input.LightGunX, float32(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := hc.Handle(input.LightGunY, float32(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	x := television.HorizClksVisible / 2
+	y := television.SpecNTSC.ScanlineTop + television.SpecNTSC.ScanlinesVisible/2
+
+	// beam away from the aim point
+	tv.scanline = y - 1
+	tv.horizPos = x
+	inp.Step()
+	inpt4(0x80)
+
+	// beam just before the aim point
+	tv.scanline = y
+	tv.horizPos = x - 1
+	inp.Step()
+	inpt4(0x80)
+
+	// beam at the aim point
+	tv.horizPos = x
+	inp.Step()
+	inpt4(0x00)
+
+	// beam moved on
+	tv.horizPos = x + 15
+	inp.Step()
+	inpt4(0x80)
+
+	// beam at aim point but pixel too dark for the sensor
+	tv.horizPos = x + 3
+	tv.pixel = 0x02
+	inp.Step()
+	inpt4(0x80)
+	tv.pixel = television.VideoBlack
+	inp.Step()
+	inpt4(0x80)
+
+	// next scanline is still seen by the sensor
+	tv.scanline = y + 1
+	tv.pixel = 0x4a
+	inp.Step()
+	inpt4(0x00)
+
+	// light sensor respects the fire button latch
+	inp.VBlankBits.SetLatchFireButton(true)
+	tv.scanline = y + 2
+	inp.Step()
+	inpt4(0x00)
+	inp.VBlankBits.SetLatchFireButton(false)
+	inpt4(0x80)
+
+	// light gun events are ignored by other controller types
+	if err := hc.SwitchType(input.JoystickType); err != nil {
+		t.Fatal(err)
+	}
+	tv.scanline = y
+	if err := hc.Handle(input.LightGunX, float32(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	inp.Step()
+	inpt4(0x80)
+}
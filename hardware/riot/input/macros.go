@@ -0,0 +1,496 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// MacroStep is a single event in a Macro. The next step in the macro is not
+// taken until Frames number of frames have passed.
+type MacroStep struct {
+	Event  Event
+	Data   EventData
+	Frames int
+}
+
+func (s MacroStep) String() string {
+	str := string(s.Event)
+	switch d := s.Data.(type) {
+	case nil:
+	case rune:
+		str = fmt.Sprintf("%s:%c", str, d)
+	default:
+		str = fmt.Sprintf("%s:%v", str, d)
+	}
+	if s.Frames > 0 {
+		str = fmt.Sprintf("%s/%d", str, s.Frames)
+	}
+	return str
+}
+
+// Macro is a named sequence of MacroSteps.
+//
+// A macro with a Trigger is started when the trigger event is sent to the
+// Macros.Handle() function with any data other than false. It is stopped when
+// the trigger event is sent with data of false. A macro with a Trigger of
+// NoEvent can only be started and stopped with the Start() and Stop()
+// functions.
+//
+// When a macro is stopped, any bool events that the macro has set to true and
+// not yet set to false are released (ie. sent again with false). A triggered
+// macro that has run to completion holds on to its events until the trigger
+// is released, which means that a macro of zero-frame steps works as a chord.
+//
+// A macro that repeats starts again from the first step after the last step
+// has completed. A repeating macro must wait for at least one frame in the
+// course of its steps.
+type Macro struct {
+	Name    string
+	Trigger Event
+	Repeat  bool
+	Steps   []MacroStep
+}
+
+// String returns the macro in the format accepted by ParseMacro().
+func (mac Macro) String() string {
+	s := strings.Builder{}
+	s.WriteString(fmt.Sprintf("%s, %s, ", mac.Name, mac.Trigger))
+	if mac.Repeat {
+		s.WriteString("repeat")
+	} else {
+		s.WriteString("once")
+	}
+	for _, st := range mac.Steps {
+		s.WriteString(fmt.Sprintf(", %s", st))
+	}
+	return s.String()
+}
+
+// check that the macro can be run
+func (mac Macro) validate() error {
+	if mac.Name == "" {
+		return fmt.Errorf("macro has no name")
+	}
+
+	if len(mac.Steps) == 0 {
+		return fmt.Errorf("%s has no steps", mac.Name)
+	}
+
+	if mac.Repeat {
+		for _, st := range mac.Steps {
+			if st.Frames > 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s repeats but never waits", mac.Name)
+	}
+
+	return nil
+}
+
+// ParseMacro parses a single macro definition of the form:
+//
+//	<name>, <trigger>, <once|repeat>, <step> {, <step>}
+//
+// Each step is of the form <event>[:<data>][/<frames>]. The event and data
+// are as described for ParseEvent(). The number of frames is optional and
+// defaults to zero. For example, a macro that holds the joystick to the right
+// for thirty frames before firing:
+//
+//	jump, Up, once, Right:true/30, Fire:true/2, Fire:false, Right:false
+func ParseMacro(s string) (*Macro, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("too few fields in macro definition")
+	}
+
+	mac := &Macro{
+		Name: strings.TrimSpace(fields[0]),
+	}
+
+	var err error
+
+	mac.Trigger, _, err = ParseEvent(fields[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(fields[2])) {
+	case "once":
+	case "repeat":
+		mac.Repeat = true
+	default:
+		return nil, fmt.Errorf("macro must be once or repeat (%s)", strings.TrimSpace(fields[2]))
+	}
+
+	for _, f := range fields[3:] {
+		st := MacroStep{}
+
+		// the frames field is optional
+		if i := strings.LastIndex(f, "/"); i >= 0 {
+			st.Frames, err = strconv.Atoi(strings.TrimSpace(f[i+1:]))
+			if err != nil || st.Frames < 0 {
+				return nil, fmt.Errorf("number of frames must be a positive number (%s)", strings.TrimSpace(f[i+1:]))
+			}
+			f = f[:i]
+		}
+
+		st.Event, st.Data, err = ParseEvent(f)
+		if err != nil {
+			return nil, err
+		}
+
+		mac.Steps = append(mac.Steps, st)
+	}
+
+	return mac, mac.validate()
+}
+
+// TurboFire returns a macro that repeatedly presses and releases the fire
+// button while the fire button is held. The button is pressed for the number
+// of frames specified and then released for the same number of frames.
+func TurboFire(frames int) *Macro {
+	if frames < 1 {
+		frames = 1
+	}
+
+	return &Macro{
+		Name:    "turbo",
+		Trigger: Fire,
+		Repeat:  true,
+		Steps: []MacroStep{
+			{Event: Fire, Data: true, Frames: frames},
+			{Event: Fire, Data: false, Frames: frames},
+		},
+	}
+}
+
+// a macro that has been started
+type runningMacro struct {
+	macro *Macro
+
+	// the macro was started by the trigger event rather than by a call to
+	// Start()
+	triggered bool
+
+	// the next step and the number of frames to wait before it is taken
+	step int
+	wait int
+
+	// all steps have been taken
+	done bool
+
+	// bool events that have been set to true by the macro but not released.
+	// in the order they were pressed so that stop() releases them in a
+	// predictable order
+	held []Event
+}
+
+// hold adds the event to the list of held events if it is not already there
+func (r *runningMacro) hold(ev Event) {
+	for _, h := range r.held {
+		if h == ev {
+			return
+		}
+	}
+	r.held = append(r.held, ev)
+}
+
+// release removes the event from the list of held events
+func (r *runningMacro) release(ev Event) {
+	for i, h := range r.held {
+		if h == ev {
+			r.held = append(r.held[:i], r.held[i+1:]...)
+			return
+		}
+	}
+}
+
+// Macros sits between the user and a port's Handle() function. Events sent
+// to the Handle() function of the Macros type start and stop macros as
+// required. Events that are not the trigger for any macro are forwarded to the
+// port unchanged.
+//
+// The events generated by a macro are sent to the port from the port's
+// CheckInput() function, which means they are passed to any attached
+// EventRecorder at the same point in the emulation that a Playback will
+// later reproduce them. Macros do not run while a Playback is attached to the
+// port.
+type Macros struct {
+	port *port
+
+	defined []*Macro
+	running []*runningMacro
+
+	// events waiting to be sent to the port. only one event is sent each time
+	// step() is called. this prevents more than one event being recorded at
+	// exactly the same point in the emulation, which would confuse playback.
+	queue []MacroStep
+
+	// the last frame number seen by step()
+	frameNum int
+}
+
+func newMacros(p *port) *Macros {
+	return &Macros{port: p}
+}
+
+// Define adds the macro to the list of defined macros, replacing any existing
+// macro with the same name.
+func (m *Macros) Define(mac *Macro) error {
+	if err := mac.validate(); err != nil {
+		return errors.New(errors.MacroError, err)
+	}
+
+	for i, d := range m.defined {
+		if d.Name == mac.Name {
+			m.stop(d)
+			m.defined[i] = mac
+			return nil
+		}
+	}
+
+	m.defined = append(m.defined, mac)
+	return nil
+}
+
+// Drop removes the named macro, stopping it if necessary.
+func (m *Macros) Drop(name string) error {
+	for i, d := range m.defined {
+		if d.Name == name {
+			m.stop(d)
+			m.defined = append(m.defined[:i], m.defined[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New(errors.MacroError, fmt.Sprintf("no macro named %s", name))
+}
+
+// Load the macro definitions in the named file. Each line in the file is
+// parsed with ParseMacro(). Empty lines and lines beginning with # are
+// ignored.
+func (m *Macros) Load(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return errors.New(errors.MacroError, err)
+	}
+
+	for i, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimSpace(l)
+		if len(l) == 0 || l[0] == '#' {
+			continue // for loop
+		}
+
+		mac, err := ParseMacro(l)
+		if err != nil {
+			return errors.New(errors.MacroError, fmt.Sprintf("%v [line %d]", err, i+1))
+		}
+
+		if err := m.Define(mac); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Defined returns the list of defined macros.
+func (m *Macros) Defined() []*Macro {
+	return m.defined
+}
+
+// IsRunning returns true if the named macro has been started and not yet
+// stopped.
+func (m *Macros) IsRunning(name string) bool {
+	for _, r := range m.running {
+		if r.macro.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Start the named macro.
+func (m *Macros) Start(name string) error {
+	for _, d := range m.defined {
+		if d.Name == name {
+			m.start(d, false)
+			return nil
+		}
+	}
+	return errors.New(errors.MacroError, fmt.Sprintf("no macro named %s", name))
+}
+
+// Stop the named macro.
+func (m *Macros) Stop(name string) error {
+	for _, d := range m.defined {
+		if d.Name == name {
+			m.stop(d)
+			return nil
+		}
+	}
+	return errors.New(errors.MacroError, fmt.Sprintf("no macro named %s", name))
+}
+
+// StopAll stops all running macros.
+func (m *Macros) StopAll() {
+	for len(m.running) > 0 {
+		m.stop(m.running[0].macro)
+	}
+}
+
+// Handle an event from the user. If the event is the trigger for any defined
+// macro then the macro is started or stopped as appropriate and the event is
+// consumed. Otherwise, the event is forwarded to the port.
+func (m *Macros) Handle(event Event, data EventData) error {
+	var trigger bool
+
+	for _, d := range m.defined {
+		if d.Trigger == NoEvent || d.Trigger != event {
+			continue // for loop
+		}
+
+		trigger = true
+
+		if b, ok := data.(bool); ok && !b {
+			m.stop(d)
+		} else {
+			m.start(d, true)
+		}
+	}
+
+	if trigger {
+		return nil
+	}
+
+	// make sure events from the user don't jump ahead of any events generated
+	// by a macro
+	if len(m.queue) > 0 {
+		m.queue = append(m.queue, MacroStep{Event: event, Data: data})
+		return nil
+	}
+
+	return m.port.handle(event, data)
+}
+
+func (m *Macros) start(mac *Macro, triggered bool) {
+	for _, r := range m.running {
+		if r.macro == mac {
+			return
+		}
+	}
+
+	// frames are counted from the moment the first macro is started
+	if len(m.running) == 0 && m.port.tv != nil {
+		m.frameNum, _ = m.port.tv.GetState(television.ReqFramenum)
+	}
+
+	m.running = append(m.running, &runningMacro{
+		macro:     mac,
+		triggered: triggered,
+	})
+}
+
+func (m *Macros) stop(mac *Macro) {
+	for i, r := range m.running {
+		if r.macro == mac {
+			for _, ev := range r.held {
+				m.queue = append(m.queue, MacroStep{Event: ev, Data: false})
+			}
+			m.running = append(m.running[:i], m.running[i+1:]...)
+			return
+		}
+	}
+}
+
+// step is called by the port's CheckInput() function. it advances the running
+// macros and sends the next queued event to the port
+func (m *Macros) step() error {
+	if len(m.running) == 0 && len(m.queue) == 0 {
+		return nil
+	}
+
+	if m.port.tv != nil {
+		fn, err := m.port.tv.GetState(television.ReqFramenum)
+		if err != nil {
+			return err
+		}
+		if fn != m.frameNum {
+			m.frameNum = fn
+			for _, r := range m.running {
+				if r.wait > 0 {
+					r.wait--
+				}
+			}
+		}
+	}
+
+	i := 0
+	for i < len(m.running) {
+		r := m.running[i]
+
+		for !r.done && r.wait == 0 {
+			st := r.macro.Steps[r.step]
+			m.queue = append(m.queue, st)
+
+			if b, ok := st.Data.(bool); ok {
+				if b {
+					r.hold(st.Event)
+				} else {
+					r.release(st.Event)
+				}
+			}
+
+			r.wait = st.Frames
+			r.step++
+			if r.step >= len(r.macro.Steps) {
+				if r.macro.Repeat {
+					r.step = 0
+				} else {
+					r.done = true
+				}
+			}
+		}
+
+		// a triggered macro that is holding events waits for the trigger to
+		// be released. see Macro type commentary
+		if r.done && !(r.triggered && len(r.held) > 0) {
+			m.running = append(m.running[:i], m.running[i+1:]...)
+		} else {
+			i++
+		}
+	}
+
+	if len(m.queue) == 0 {
+		return nil
+	}
+
+	st := m.queue[0]
+	m.queue = m.queue[1:]
+
+	return m.port.handle(st.Event, st.Data)
+}
@@ -0,0 +1,240 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+	"github.com/jetsetilly/gopher2600/television"
+)
+
+// clock is a minimal implementation of the television.Television interface.
+// only the frame number is required by the macros
+type clock struct {
+	television.Television
+	frameNum int
+}
+
+func (c *clock) GetState(request television.StateReq) (int, error) {
+	if request == television.ReqFramenum {
+		return c.frameNum, nil
+	}
+	return 0, nil
+}
+
+// scribe is an implementation of the input.EventRecorder interface that
+// collects events as strings
+type scribe struct {
+	events []string
+}
+
+func (s *scribe) RecordEvent(id input.ID, ev input.Event, data input.EventData) error {
+	s.events = append(s.events, fmt.Sprintf("%d %s %v", id, ev, data))
+	return nil
+}
+
+// expect checks the recorded events and clears the list
+func (s *scribe) expect(t *testing.T, events ...string) {
+	t.Helper()
+	if len(s.events) != len(events) {
+		t.Fatalf("expected %d events, got %d %v", len(events), len(s.events), s.events)
+	}
+	for i := range events {
+		if s.events[i] != events[i] {
+			t.Errorf("expected event %q, got %q", events[i], s.events[i])
+		}
+	}
+	s.events = s.events[:0]
+}
+
+func TestMacros(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tv := &clock{}
+	rec := &scribe{}
+	hc := inp.HandController0
+	hc.AttachTV(tv)
+	hc.AttachEventRecorder(rec)
+
+	// run for a number of frames, checking input several times each frame
+	run := func(frames int) {
+		t.Helper()
+		for i := 0; i < frames; i++ {
+			tv.frameNum++
+			for j := 0; j < 4; j++ {
+				if err := hc.CheckInput(); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	mcr := hc.Macros()
+
+	// events that are not triggers pass straight through
+	if err := mcr.Handle(input.Fire, true); err != nil {
+		t.Fatal(err)
+	}
+	rec.expect(t, "0 Fire true")
+	if err := mcr.Handle(input.Fire, false); err != nil {
+		t.Fatal(err)
+	}
+	rec.expect(t, "0 Fire false")
+
+	// turbo fire
+	if err := mcr.Define(input.TurboFire(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Handle(input.Fire, true); err != nil {
+		t.Fatal(err)
+	}
+	rec.expect(t)
+	run(6)
+	rec.expect(t, "0 Fire true", "0 Fire false", "0 Fire true")
+	if err := mcr.Handle(input.Fire, false); err != nil {
+		t.Fatal(err)
+	}
+	run(2)
+	rec.expect(t, "0 Fire false")
+	if err := mcr.Drop("turbo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// timed sequence started explicitly
+	mac, err := input.ParseMacro("jump, NoEvent, once, Right:true/3, Fire:true/1, Fire:false, Right:false")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Define(mac); err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Start("jump"); err != nil {
+		t.Fatal(err)
+	}
+	run(2)
+	rec.expect(t, "0 Right true")
+	run(2)
+	rec.expect(t, "0 Fire true")
+	run(1)
+	rec.expect(t, "0 Fire false", "0 Right false")
+	if mcr.IsRunning("jump") {
+		t.Errorf("jump macro should have finished")
+	}
+
+	// chord held until the trigger is released
+	mac, err = input.ParseMacro("chord, GenesisButtonC, once, Fire:true, Up:true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Define(mac); err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Handle(input.GenesisButtonC, true); err != nil {
+		t.Fatal(err)
+	}
+	run(3)
+	rec.expect(t, "0 Fire true", "0 Up true")
+	if err := mcr.Handle(input.GenesisButtonC, false); err != nil {
+		t.Fatal(err)
+	}
+	run(1)
+	rec.expect(t, "0 Fire false", "0 Up false")
+
+	// stopping a macro releases held events in the order they were pressed.
+	// repeated because a random release order might match by chance
+	mac, err = input.ParseMacro("hold, NoEvent, once, Left:true, Down:true, Fire:true, Fire:false, Fire:true, Up:true/10, Left:false")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mcr.Define(mac); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := mcr.Start("hold"); err != nil {
+			t.Fatal(err)
+		}
+		run(2)
+		rec.expect(t, "0 Left true", "0 Down true", "0 Fire true", "0 Fire false", "0 Fire true", "0 Up true")
+		if err := mcr.Stop("hold"); err != nil {
+			t.Fatal(err)
+		}
+		run(2)
+		rec.expect(t, "0 Left false", "0 Down false", "0 Fire false", "0 Up false")
+	}
+	if err := mcr.Drop("hold"); err != nil {
+		t.Fatal(err)
+	}
+
+	// macros do not run while a playback is attached
+	if err := mcr.Start("jump"); err != nil {
+		t.Fatal(err)
+	}
+	hc.AttachPlayback(&nothing{})
+	run(2)
+	rec.expect(t)
+	hc.AttachPlayback(nil)
+	run(1)
+	rec.expect(t, "0 Right true")
+	mcr.StopAll()
+	run(1)
+	rec.expect(t, "0 Right false")
+}
+
+// nothing is an implementation of the input.Playback interface that never
+// has any input
+type nothing struct{}
+
+func (n *nothing) CheckInput(id input.ID) (input.Event, input.EventData, error) {
+	return input.NoEvent, nil, nil
+}
+
+func TestParseMacro(t *testing.T) {
+	mac, err := input.ParseMacro("test, fire, repeat, Left:true/10, KeypadDown:#, Left:false/5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := "test, Fire, repeat, Left:true/10, KeypadDown:#, Left:false/5"
+	if mac.String() != s {
+		t.Errorf("expected %q, got %q", s, mac.String())
+	}
+
+	for _, s := range []string{
+		"test, Fire, once",
+		"test, Fire, sometimes, Fire:true",
+		"test, Fire, repeat, Fire:true, Fire:false",
+		"test, Fire, once, Fire:true/x",
+		"test, Jump, once, Fire:true",
+		", Fire, once, Fire:true",
+	} {
+		if _, err := input.ParseMacro(s); err == nil {
+			t.Errorf("expected error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import (
+	"math"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/addresses"
+)
+
+// the paddle circuit is a potentiometer in series with a small fixed resistor,
+// charging a capacitor in the TIA input circuit. the TIA reads the input as a
+// logic 1 once the capacitor has charged beyond a threshold voltage.
+//
+// the values are taken from the schematics and the threshold voltage is
+// calibrated against observed charging times. the time taken to reach the
+// threshold with the potentiometer at maximum resistance is more than a frame,
+// which is why games never use the entire range of the paddle.
+const (
+	paddleCapacitance      = 68e-9 // farads
+	paddlePotResistance    = 1e6   // ohms
+	paddleSeriesResistance = 1.8e3 // ohms
+
+	// the duration of one CPU cycle in seconds
+	paddleCycleTime = 1.0 / 1193182
+
+	// the number of scanlines taken to reach the threshold voltage with the
+	// potentiometer at maximum resistance
+	paddleMaxScanlines = 379
+
+	// the potentiometers in real paddles are noisy. the charge rate is
+	// varied by up to this fraction every time the capacitor is discharged
+	paddleJitter = 0.005
+)
+
+// the charge, as a fraction of the supply voltage, at which the TIA reads the
+// input as a logic 1
+var paddleThreshold = 1 - math.Exp(-paddleMaxScanlines*76*paddleCycleTime/
+	((paddlePotResistance+paddleSeriesResistance)*paddleCapacitance))
+
+// the paddle type implements one of the pair of paddles that can be attached
+// to a single controller port
+type paddle struct {
+	puckReg addresses.ChipRegister
+
+	// the bit in SWCHA (normalised) used by the fire button
+	fireBit uint8
+
+	// the position of the potentiometer. 0.0 is minimum resistance and 1.0 is
+	// maximum resistance
+	resistance float64
+
+	// the charge of the capacitor as a fraction of the supply voltage and the
+	// fraction of the remaining charge that is added every CPU cycle
+	charge float64
+	rate   float64
+
+	// the charge has reached the threshold voltage
+	triggered bool
+
+	// count of how many times the paddle has touched the extreme values. we
+	// use this to help decide whether to switch controller types
+	touchLeft     int
+	touchRight    int
+	touchingLeft  bool
+	touchingRight bool
+}
+
+func newPaddle(puckReg addresses.ChipRegister, fireBit uint8) paddle {
+	p := paddle{
+		puckReg: puckReg,
+		fireBit: fireBit,
+	}
+	p.updateRate(0)
+	return p
+}
+
+// updateRate recalculates the charge rate of the capacitor. the jitter value
+// is the fractional variation in the rate
+func (p *paddle) updateRate(jitter float64) {
+	rc := (p.resistance*paddlePotResistance + paddleSeriesResistance) * paddleCapacitance
+	p.rate = (1 - math.Exp(-paddleCycleTime/rc)) * (1 + jitter)
+}
+
+// set the position of the paddle. the value should be between 0.0 and 1.0
+func (p *paddle) set(f float32) {
+	if f < 0.0 {
+		f = 0.0
+	} else if f > 1.0 {
+		f = 1.0
+	}
+	p.resistance = 1.0 - float64(f)
+	p.updateRate(0)
+}
+
+// jitter returns a pseudo-random value in the range -paddleJitter to
+// +paddleJitter. the sequence is deterministic so that recordings and
+// regression tests are reproducible
+func (hc *HandController) jitter() float64 {
+	// xorshift
+	hc.paddleNoise ^= hc.paddleNoise << 13
+	hc.paddleNoise ^= hc.paddleNoise >> 17
+	hc.paddleNoise ^= hc.paddleNoise << 5
+	return (float64(hc.paddleNoise)/math.MaxUint32*2 - 1) * paddleJitter
+}
+
+// firePaddle sets the state of the fire button for the specified paddle
+func (hc *HandController) firePaddle(i int, pressed bool) {
+	var v uint8
+
+	if pressed {
+		v = paddleNoFire
+	} else {
+		v = paddleFire
+	}
+
+	bit := hc.paddles[i].fireBit
+	hc.writeSWCHA(v&bit, ^hc.normaliseOnWrite(bit))
+}
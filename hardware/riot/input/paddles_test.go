@@ -0,0 +1,103 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+func TestPaddles(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc := inp.HandController0
+	if err := hc.SwitchType(input.PaddleType); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hc.Handle(input.PaddleSet, float32(0.5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := hc.Handle(input.PaddleSetB, float32(0.75)); err != nil {
+		t.Fatal(err)
+	}
+
+	// measure the number of scanlines each paddle takes to charge. INPT0 and
+	// INPT1
+	measure := func() (int, int) {
+		inp.VBlankBits.SetGroundPaddles(true)
+		inp.VBlankBits.SetGroundPaddles(false)
+
+		a := -1
+		b := -1
+		for cycle := 0; cycle < 400*76 && (a == -1 || b == -1); cycle++ {
+			inp.Step()
+			if v, _ := mem.Read(0x08); a == -1 && v&0x80 == 0x80 {
+				a = cycle / 76
+			}
+			if v, _ := mem.Read(0x09); b == -1 && v&0x80 == 0x80 {
+				b = cycle / 76
+			}
+		}
+		return a, b
+	}
+
+	// half of the maximum resistance takes approximately half the maximum
+	// time, allowing for jitter
+	a, b := measure()
+	if a < 185 || a > 194 {
+		t.Errorf("unexpected charge time for first paddle (%d scanlines)", a)
+	}
+	if b < 92 || b > 98 {
+		t.Errorf("unexpected charge time for second paddle (%d scanlines)", b)
+	}
+
+	// paddle does not charge while it is grounded
+	inp.VBlankBits.SetGroundPaddles(true)
+	for cycle := 0; cycle < 300*76; cycle++ {
+		inp.Step()
+	}
+	if v, _ := mem.Read(0x08); v&0x80 == 0x80 {
+		t.Errorf("paddle charged while grounded")
+	}
+
+	// fire buttons for each paddle
+	if err := hc.Handle(input.PaddleFireB, true); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mem.Read(0x0280); v&0xc0 != 0x80 {
+		t.Errorf("expected second paddle fire in SWCHA, got %#02x", v)
+	}
+	if err := hc.Handle(input.PaddleFire, true); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mem.Read(0x0280); v&0xc0 != 0x00 {
+		t.Errorf("expected both paddle fire buttons in SWCHA, got %#02x", v)
+	}
+}
@@ -53,6 +53,7 @@ func NewPanel(mem *inputMemory) *Panel {
 		id:     PanelID,
 		handle: pan.Handle,
 	}
+	pan.port.macros = newMacros(&pan.port)
 
 	pan.write()
 
@@ -88,6 +89,11 @@ func (pan *Panel) String() string {
 	return s.String()
 }
 
+// IsResetPressed returns true if the reset switch is being held down
+func (pan *Panel) IsResetPressed() bool {
+	return pan.resetPressed
+}
+
 func (pan *Panel) write() {
 	// commit changes to RIOT memory
 	v := uint8(0)
@@ -0,0 +1,183 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+// the trak-ball and mouse controllers are collectively referred to as
+// pointers. each pointer encodes movement on the four direction pins of the
+// controller port but in different ways.
+//
+// the tables below are indexed by the position of the axis and give the state
+// of the pins in pin order. ie. bit 0 is pin 1 (joystick up) and bit 3 is pin
+// 4 (joystick right). the values are normalised as described in the
+// HandController type by pointer.swcha()
+
+// the amiga mouse produces a quadrature signal for each axis
+var (
+	amigaMouseH = [4]uint8{0x00, 0x08, 0x0a, 0x02}
+	amigaMouseV = [4]uint8{0x00, 0x04, 0x05, 0x01}
+)
+
+// the atari st mouse is similar to the amiga mouse but the pins are assigned
+// differently
+var (
+	stMouseH = [4]uint8{0x00, 0x01, 0x03, 0x02}
+	stMouseV = [4]uint8{0x00, 0x04, 0x0c, 0x08}
+)
+
+// the trak-ball (in trak-ball mode) produces a motion pulse and a direction
+// flag for each axis:
+//
+//	pin 1: horizontal motion
+//	pin 2: horizontal direction (high for left)
+//	pin 3: vertical direction (low for down)
+//	pin 4: vertical motion
+const (
+	trakBallMotionH = uint8(0x01)
+	trakBallLeft    = uint8(0x02)
+	trakBallUp      = uint8(0x04)
+	trakBallMotionV = uint8(0x08)
+)
+
+// the number of CPU cycles between each change in pointer position. movement
+// from the host is spread out over time so that the VCS program sees every
+// step. a change every scanline is slow enough for all known programs.
+const pointerCyclesPerStep = 76
+
+// the maximum amount of movement that can be waiting to be output. any more
+// than this and the pointer will feel sluggish
+const pointerMaxPending = 32
+
+// the pointer type implements the trak-ball and mouse controllers
+type pointer struct {
+	// the current position of each axis. only the bottom bits are significant
+	h int
+	v int
+
+	// movement that has not yet been output
+	pendingH int
+	pendingV int
+
+	// direction of the most recent movement on each axis
+	left bool
+	down bool
+
+	// cycles since the last step
+	cycles int
+}
+
+// move adds movement to the pending movement of an axis
+func (p *pointer) move(pending *int, n int) {
+	*pending += n
+	if *pending > pointerMaxPending {
+		*pending = pointerMaxPending
+	} else if *pending < -pointerMaxPending {
+		*pending = -pointerMaxPending
+	}
+}
+
+// step is called once every CPU cycle. returns true if the position of the
+// pointer has changed
+func (p *pointer) step() bool {
+	p.cycles++
+	if p.cycles < pointerCyclesPerStep {
+		return false
+	}
+	p.cycles = 0
+
+	changed := false
+
+	if p.pendingH > 0 {
+		p.h++
+		p.pendingH--
+		p.left = false
+		changed = true
+	} else if p.pendingH < 0 {
+		p.h--
+		p.pendingH++
+		p.left = true
+		changed = true
+	}
+
+	if p.pendingV > 0 {
+		p.v++
+		p.pendingV--
+		p.down = true
+		changed = true
+	} else if p.pendingV < 0 {
+		p.v--
+		p.pendingV++
+		p.down = false
+		changed = true
+	}
+
+	return changed
+}
+
+// swcha returns the value that should be written to SWCHA for the pointer
+// type
+func (p pointer) swcha(t ControllerType) uint8 {
+	var pins uint8
+
+	switch t {
+	case AmigaMouseType:
+		pins = amigaMouseH[p.h&0x03] | amigaMouseV[p.v&0x03]
+	case STMouseType:
+		pins = stMouseH[p.h&0x03] | stMouseV[p.v&0x03]
+	case TrakBallType:
+		if p.h&0x01 == 0x01 {
+			pins |= trakBallMotionH
+		}
+		if p.left {
+			pins |= trakBallLeft
+		}
+		if !p.down {
+			pins |= trakBallUp
+		}
+		if p.v&0x01 == 0x01 {
+			pins |= trakBallMotionV
+		}
+	}
+
+	return pins << 4
+}
+
+// isPointer returns true if the controller type is one of the pointer types
+func (t ControllerType) isPointer() bool {
+	return t == TrakBallType || t == AmigaMouseType || t == STMouseType
+}
+
+// movePointer adds movement to the horizontal or vertical axis of the pointer
+func (hc *HandController) movePointer(horizontal bool, f float32) {
+	if horizontal {
+		hc.pointer.move(&hc.pointer.pendingH, int(f))
+	} else {
+		hc.pointer.move(&hc.pointer.pendingV, int(f))
+	}
+}
+
+// stepPointer is called every CPU cycle via Input.Step()
+func (hc *HandController) stepPointer() {
+	if !hc.ControllerType.isPointer() {
+		return
+	}
+	if hc.pointer.step() {
+		hc.writeSWCHA(hc.pointer.swcha(hc.ControllerType), hc.writeMask)
+	}
+}
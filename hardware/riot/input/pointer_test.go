@@ -0,0 +1,98 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+func TestPointer(t *testing.T) {
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc := inp.HandController0
+
+	swcha := func(expected uint8) {
+		t.Helper()
+		v, err := mem.Read(0x0280)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v&0xf0 != expected {
+			t.Errorf("expected SWCHA of %#02x, got %#02x", expected, v&0xf0)
+		}
+	}
+
+	// one scanline's worth of CPU cycles
+	scanline := func() {
+		for i := 0; i < 76; i++ {
+			inp.Step()
+		}
+	}
+
+	// amiga mouse. movement is output one step at a time
+	if err := hc.SwitchType(input.AmigaMouseType); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0x00)
+
+	if err := hc.Handle(input.AmigaMouseX, float32(2)); err != nil {
+		t.Fatal(err)
+	}
+	swcha(0x00)
+	scanline()
+	swcha(0x80)
+	scanline()
+	swcha(0xa0)
+	scanline()
+	swcha(0xa0)
+
+	// events for other pointer types are ignored
+	if err := hc.Handle(input.STMouseY, float32(1)); err != nil {
+		t.Fatal(err)
+	}
+	scanline()
+	swcha(0xa0)
+
+	if err := hc.Handle(input.AmigaMouseY, float32(1)); err != nil {
+		t.Fatal(err)
+	}
+	scanline()
+	swcha(0xe0)
+
+	// trak-ball moving left
+	if err := hc.SwitchType(input.TrakBallType); err != nil {
+		t.Fatal(err)
+	}
+	if err := hc.Handle(input.TrakBallX, float32(-1)); err != nil {
+		t.Fatal(err)
+	}
+	scanline()
+	swcha(0x70)
+}
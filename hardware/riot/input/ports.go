@@ -19,7 +19,10 @@
 
 package input
 
-import "github.com/jetsetilly/gopher2600/errors"
+import (
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/television"
+)
 
 // ID differentiates the different ports attached to the console
 type ID int
@@ -42,6 +45,7 @@ type Port interface {
 	Handle(Event, EventData) error
 	AttachPlayback(Playback)
 	AttachEventRecorder(EventRecorder)
+	Macros() *Macros
 }
 
 // port is the underlying commonality between all Port implementations
@@ -50,6 +54,26 @@ type port struct {
 	playback Playback
 	recorder EventRecorder
 	handle   func(Event, EventData) error
+
+	// the television is required by the light gun, which needs to know the
+	// position of the electron beam, and by the macros, which are timed in
+	// frames. use AttachTV() to set.
+	tv television.Television
+
+	// macros sit between the user and the handle function
+	macros *Macros
+}
+
+// AttachTV gives the port access to the television.
+func (p *port) AttachTV(tv television.Television) {
+	p.tv = tv
+}
+
+// Macros returns the macro engine for the port. Events from the user should
+// be sent to the macro engine's Handle() function rather than the port's
+// Handle() function if the macros are to take effect.
+func (p *port) Macros() *Macros {
+	return p.macros
 }
 
 // Attach a Playback implementation to the port.  Events can still be
@@ -65,21 +89,24 @@ func (p *port) AttachEventRecorder(scribe EventRecorder) {
 	p.recorder = scribe
 }
 
-// CheckInput polls the attached playback for an Event
+// CheckInput polls the attached playback for an Event. If there is no playback
+// attached then any running macros are advanced instead.
 func (p *port) CheckInput() error {
-	if p.playback != nil {
-		ev, v, err := p.playback.CheckInput(p.id)
-		if err != nil {
-			return err
-		}
+	if p.playback == nil {
+		return p.macros.step()
+	}
 
-		err = p.handle(ev, v)
-		if err != nil {
-			if !errors.Is(err, errors.InputDeviceUnplugged) {
-				return err
-			}
-			p.AttachPlayback(nil)
+	ev, v, err := p.playback.CheckInput(p.id)
+	if err != nil {
+		return err
+	}
+
+	err = p.handle(ev, v)
+	if err != nil {
+		if !errors.Is(err, errors.InputDeviceUnplugged) {
+			return err
 		}
+		p.AttachPlayback(nil)
 	}
 
 	return nil
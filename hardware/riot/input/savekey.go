@@ -0,0 +1,323 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory/addresses"
+	"github.com/jetsetilly/gopher2600/paths"
+)
+
+// SaveKeySize is the size of the EEPROM in the SaveKey (and AtariVox)
+const SaveKeySize = 0x8000
+
+// the EEPROM is written to in pages of 64 bytes. the address wraps around to
+// the start of the page if more than 64 bytes are written in one transaction
+const saveKeyPageSize = 0x40
+
+// the name of the file in the resource directory in which the EEPROM data is
+// saved
+const saveKeyFile = "savekey_eeprom.bin"
+
+// the SaveKey is connected to pins 3 and 4 of the controller port. in terms of
+// the SWCHA register these are the bits for joystick left and right. the
+// values are normalised as described in the HandController type
+const (
+	saveKeySDA = uint8(0x40)
+	saveKeySCL = uint8(0x80)
+)
+
+type i2cPhase int
+
+const (
+	i2cIdle i2cPhase = iota
+	i2cControl
+	i2cAddressHi
+	i2cAddressLo
+	i2cWrite
+	i2cRead
+)
+
+func (p i2cPhase) String() string {
+	switch p {
+	case i2cIdle:
+		return "idle"
+	case i2cControl:
+		return "control"
+	case i2cAddressHi:
+		return "address hi"
+	case i2cAddressLo:
+		return "address lo"
+	case i2cWrite:
+		return "write"
+	case i2cRead:
+		return "read"
+	}
+	panic("unknown i2c phase")
+}
+
+// SaveKey emulates the 32K I2C EEPROM found in the SaveKey and AtariVox
+// devices. The EEPROM is driven by the CPU "bit-banging" the SDA and SCL lines
+// through the SWCHA register.
+//
+// The contents of the EEPROM are saved to disk at the end of every write
+// transaction.
+type SaveKey struct {
+	EEPROM [SaveKeySize]uint8
+
+	// the file the EEPROM is loaded from and saved to. an empty string
+	// indicates that the EEPROM should not be saved
+	Filename string
+
+	// the address of the next byte to be read or written
+	Address uint16
+
+	phase i2cPhase
+
+	// the most recent state of the SCL and SDA lines as driven by the CPU
+	scl bool
+	sda bool
+
+	// the number of clock pulses in the current byte. the ninth pulse is the
+	// acknowledgement pulse
+	bit  int
+	data uint8
+
+	// the SaveKey is pulling the SDA line low
+	pullDown bool
+
+	// the SaveKey is acknowledging the most recent byte. if it is not then
+	// the acknowledgement is coming from the CPU
+	acking    bool
+	masterAck bool
+
+	// the EEPROM has changed since the last save
+	dirty bool
+
+	// the error returned by the most recent save attempt
+	err error
+}
+
+// NewSaveKey is the preferred method of initialisation for the SaveKey type.
+// The EEPROM data is loaded from the named file if it exists. If it doesn't
+// exist the EEPROM is blank.
+func NewSaveKey(filename string) *SaveKey {
+	sk := &SaveKey{
+		Filename: filename,
+	}
+
+	// an erased EEPROM is filled with 0xff
+	for i := range sk.EEPROM {
+		sk.EEPROM[i] = 0xff
+	}
+
+	if filename != "" {
+		if data, err := ioutil.ReadFile(filename); err == nil {
+			copy(sk.EEPROM[:], data)
+		}
+	}
+
+	return sk
+}
+
+func (sk *SaveKey) String() string {
+	s := fmt.Sprintf("SaveKey: %s, address $%04x", sk.phase, sk.Address)
+	if sk.err != nil {
+		s = fmt.Sprintf("%s (%v)", s, sk.err)
+	}
+	return s
+}
+
+// Dump returns a table of 256 bytes of EEPROM data, starting at the specified
+// address. The address is rounded down to the nearest multiple of 16.
+func (sk *SaveKey) Dump(address uint16) string {
+	address &= (SaveKeySize - 1) &^ 0x0f
+
+	s := strings.Builder{}
+	s.WriteString("       -0 -1 -2 -3 -4 -5 -6 -7 -8 -9 -A -B -C -D -E -F\n")
+	s.WriteString("     ---- -- -- -- -- -- -- -- -- -- -- -- -- -- -- --\n")
+	for y := uint16(0); y < 16 && address+y*16 < SaveKeySize; y++ {
+		a := address + y*16
+		s.WriteString(fmt.Sprintf("%03x- |", a/16))
+		for x := uint16(0); x < 16; x++ {
+			s.WriteString(fmt.Sprintf(" %02x", sk.EEPROM[a+x]))
+		}
+		s.WriteString("\n")
+	}
+	return strings.Trim(s.String(), "\n")
+}
+
+// step the I2C state machine with the new state of the lines
+func (sk *SaveKey) step(scl bool, sda bool) {
+	defer func() {
+		sk.scl = scl
+		sk.sda = sda
+	}()
+
+	// a change in SDA while SCL is high is either a start or a stop condition
+	if scl && sk.scl && sda != sk.sda {
+		if !sda {
+			sk.phase = i2cControl
+			sk.bit = 0
+			sk.data = 0
+			sk.pullDown = false
+			sk.acking = false
+		} else {
+			sk.phase = i2cIdle
+			sk.pullDown = false
+			sk.save()
+		}
+		return
+	}
+
+	if sk.phase == i2cIdle {
+		return
+	}
+
+	if scl && !sk.scl {
+		// rising edge of the clock: data is sampled
+		if sk.bit < 8 {
+			if sk.phase != i2cRead {
+				sk.data <<= 1
+				if sda {
+					sk.data |= 0x01
+				}
+			}
+		} else if !sk.acking {
+			sk.masterAck = !sda
+		}
+		sk.bit++
+	} else if !scl && sk.scl {
+		// falling edge of the clock: data is changed
+		switch {
+		case sk.bit == 8:
+			if sk.phase == i2cRead {
+				// release the line so that the CPU can acknowledge
+				sk.pullDown = false
+				sk.acking = false
+			} else {
+				sk.acking = sk.receive(sk.data)
+				sk.pullDown = sk.acking
+			}
+
+		case sk.bit == 9:
+			sk.bit = 0
+			sk.data = 0
+			sk.pullDown = false
+
+			if sk.phase == i2cRead {
+				if sk.acking || sk.masterAck {
+					sk.data = sk.EEPROM[sk.Address]
+					sk.Address = (sk.Address + 1) & (SaveKeySize - 1)
+					sk.pullDown = sk.data&0x80 == 0x00
+				} else {
+					sk.phase = i2cIdle
+				}
+			}
+
+			sk.acking = false
+			sk.masterAck = false
+
+		case sk.phase == i2cRead && sk.bit > 0:
+			sk.pullDown = sk.data&(0x80>>sk.bit) == 0x00
+		}
+	}
+}
+
+// receive a byte from the CPU. returns true if the byte should be acknowledged
+func (sk *SaveKey) receive(data uint8) bool {
+	switch sk.phase {
+	case i2cControl:
+		// the SaveKey has the chip select lines tied to ground
+		if data&0xfe != 0xa0 {
+			sk.phase = i2cIdle
+			return false
+		}
+		if data&0x01 == 0x01 {
+			sk.phase = i2cRead
+		} else {
+			sk.phase = i2cAddressHi
+		}
+
+	case i2cAddressHi:
+		sk.Address = uint16(data&0x7f) << 8
+		sk.phase = i2cAddressLo
+
+	case i2cAddressLo:
+		sk.Address |= uint16(data)
+		sk.phase = i2cWrite
+
+	case i2cWrite:
+		sk.EEPROM[sk.Address] = data
+		sk.Address = (sk.Address &^ (saveKeyPageSize - 1)) | ((sk.Address + 1) & (saveKeyPageSize - 1))
+		sk.dirty = true
+	}
+
+	return true
+}
+
+// save EEPROM to disk if it has changed
+func (sk *SaveKey) save() {
+	if !sk.dirty || sk.Filename == "" {
+		return
+	}
+	sk.dirty = false
+	sk.err = ioutil.WriteFile(sk.Filename, sk.EEPROM[:], 0600)
+}
+
+// attachSaveKey creates a SaveKey for the HandController if one has not
+// already been created
+func (hc *HandController) attachSaveKey() error {
+	if hc.SaveKey != nil {
+		return nil
+	}
+
+	fn, err := paths.ResourcePath("", saveKeyFile)
+	if err != nil {
+		return err
+	}
+	hc.SaveKey = NewSaveKey(fn)
+
+	return nil
+}
+
+// updateSaveKey is called whenever the CPU writes to SWCHA or SWACNT. the
+// state of the SDA line, as seen by the CPU, is written back to SWCHA
+func (hc *HandController) updateSaveKey() {
+	if hc.ControllerType != SaveKeyType && hc.ControllerType != AtariVoxType {
+		return
+	}
+
+	// lines that are not being driven by the CPU are pulled high
+	scl := hc.ddr&saveKeySCL == 0x00 || hc.swcha&saveKeySCL == saveKeySCL
+	sda := hc.ddr&saveKeySDA == 0x00 || hc.swcha&saveKeySDA == saveKeySDA
+
+	hc.SaveKey.step(scl, sda)
+
+	bit := hc.normaliseOnWrite(saveKeySDA)
+	if sda && !hc.SaveKey.pullDown {
+		hc.mem.riot.InputDeviceWrite(addresses.SWCHA, bit, ^bit)
+	} else {
+		hc.mem.riot.InputDeviceWrite(addresses.SWCHA, 0x00, ^bit)
+	}
+}
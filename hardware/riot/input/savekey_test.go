@@ -0,0 +1,202 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package input_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jetsetilly/gopher2600/hardware/memory"
+	"github.com/jetsetilly/gopher2600/hardware/memory/bus"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+)
+
+// SaveKey lines for hand controller one
+const (
+	sda = uint8(0x04)
+	scl = uint8(0x08)
+)
+
+// i2c bit-bangs the SaveKey in the same way as a ROM would
+type i2c struct {
+	t     *testing.T
+	mem   *memory.VCSMemory
+	inp   *input.Input
+	swcha uint8
+	ddr   uint8
+}
+
+func (b *i2c) write(reg string, v uint8) {
+	b.inp.Update(bus.ChipData{Name: reg, Value: v})
+}
+
+func (b *i2c) line(bit uint8, high bool) {
+	if high {
+		b.swcha |= bit
+	} else {
+		b.swcha &^= bit
+	}
+	b.write("SWCHA", b.swcha)
+}
+
+// release or drive the SDA line
+func (b *i2c) driveSDA(drive bool) {
+	if drive {
+		b.ddr |= sda
+	} else {
+		b.ddr &^= sda
+	}
+	b.write("SWACNT", b.ddr)
+}
+
+func (b *i2c) readSDA() bool {
+	b.t.Helper()
+	v, err := b.mem.Read(0x0280)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return v&sda == sda
+}
+
+func (b *i2c) start() {
+	b.driveSDA(true)
+	b.line(sda, true)
+	b.line(scl, true)
+	b.line(sda, false)
+	b.line(scl, false)
+}
+
+func (b *i2c) stop() {
+	b.driveSDA(true)
+	b.line(sda, false)
+	b.line(scl, true)
+	b.line(sda, true)
+}
+
+func (b *i2c) writeByte(v uint8) bool {
+	b.t.Helper()
+	for i := 0; i < 8; i++ {
+		b.line(sda, v&0x80 == 0x80)
+		b.line(scl, true)
+		b.line(scl, false)
+		v <<= 1
+	}
+	b.driveSDA(false)
+	b.line(scl, true)
+	ack := !b.readSDA()
+	b.line(scl, false)
+	b.driveSDA(true)
+	return ack
+}
+
+func (b *i2c) readByte(ack bool) uint8 {
+	b.t.Helper()
+	var v uint8
+	b.driveSDA(false)
+	for i := 0; i < 8; i++ {
+		b.line(scl, true)
+		v <<= 1
+		if b.readSDA() {
+			v |= 0x01
+		}
+		b.line(scl, false)
+	}
+	b.driveSDA(true)
+	b.line(sda, !ack)
+	b.line(scl, true)
+	b.line(scl, false)
+	return v
+}
+
+func TestSaveKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "savekey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "eeprom.bin")
+
+	mem, err := memory.NewVCSMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	inp, err := input.NewInput(mem.RIOT, mem.TIA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inp.HandController1.SaveKey = input.NewSaveKey(fn)
+	if err := inp.HandController1.SwitchType(input.SaveKeyType); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &i2c{t: t, mem: mem, inp: inp, swcha: 0xff, ddr: scl}
+	b.write("SWACNT", b.ddr)
+
+	// device with the wrong address does not acknowledge
+	b.start()
+	if b.writeByte(0xa2) {
+		t.Errorf("unexpected acknowledgement of wrong device address")
+	}
+	b.stop()
+
+	// write three bytes to address 0x0100
+	b.start()
+	for _, v := range []uint8{0xa0, 0x01, 0x00, 0x12, 0x34, 0x56} {
+		if !b.writeByte(v) {
+			t.Fatalf("expected acknowledgement of %#02x", v)
+		}
+	}
+	b.stop()
+
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("EEPROM was not saved (%s)", err)
+	}
+	if len(data) != input.SaveKeySize || data[0x100] != 0x12 || data[0x101] != 0x34 || data[0x102] != 0x56 || data[0x103] != 0xff {
+		t.Errorf("EEPROM file has unexpected contents")
+	}
+
+	// random read from address 0x0100
+	b.start()
+	for _, v := range []uint8{0xa0, 0x01, 0x00} {
+		if !b.writeByte(v) {
+			t.Fatalf("expected acknowledgement of %#02x", v)
+		}
+	}
+	b.start()
+	if !b.writeByte(0xa1) {
+		t.Fatalf("expected acknowledgement of read request")
+	}
+	for i, e := range []uint8{0x12, 0x34, 0x56} {
+		if v := b.readByte(i < 2); v != e {
+			t.Errorf("expected %#02x from EEPROM, got %#02x", e, v)
+		}
+	}
+	b.stop()
+
+	// a new SaveKey loads the saved EEPROM
+	sk := input.NewSaveKey(fn)
+	if sk.EEPROM[0x101] != 0x34 {
+		t.Errorf("EEPROM file was not loaded")
+	}
+}
@@ -36,6 +36,9 @@ func (vcs *VCS) Run(continueCheck func() (bool, error)) error {
 	// see the equivalient videoCycle() in the VCS.Step() function for an
 	// explanation for what's going on here:
 	videoCycle := func() error {
+		// the CPU has finished with the address bus for this cycle
+		vcs.Mem.TraceCycle()
+
 		if err := vcs.checkDeviceInput(); err != nil {
 			return err
 		}
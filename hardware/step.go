@@ -63,6 +63,9 @@ func (vcs *VCS) Step(videoCycleCallback func() error) error {
 	// I don't believe any visual or audible artefacts of the VCS (undocumented
 	// or not) rely on the details of the CPU-TIA relationship.
 	videoCycle := func() error {
+		// the CPU has finished with the address bus for this cycle
+		vcs.Mem.TraceCycle()
+
 		// ensure controllers have updated their input
 		if err := vcs.checkDeviceInput(); err != nil {
 			return err
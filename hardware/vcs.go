@@ -76,6 +76,12 @@ func NewVCS(tv television.Television) (*VCS, error) {
 	vcs.HandController0 = vcs.RIOT.Input.HandController0
 	vcs.HandController1 = vcs.RIOT.Input.HandController1
 
+	// the light gun needs to know about the television's electron beam and
+	// macros are timed by the television's frame number
+	vcs.HandController0.AttachTV(vcs.TV)
+	vcs.HandController1.AttachTV(vcs.TV)
+	vcs.Panel.AttachTV(vcs.TV)
+
 	return vcs, nil
 }
 
@@ -114,6 +120,9 @@ func (vcs *VCS) Reset() error {
 	// state (if the emulation has moved forward any cycles that is)
 	// !!TODO: option for random state on VCS reset
 
+	// the CPU must be able to fetch instructions again if it has been killed
+	vcs.CPU.ClearKilled()
+
 	err = vcs.CPU.LoadPCIndirect(addresses.Reset)
 	if err != nil {
 		return err
@@ -45,7 +45,7 @@ func Check(output io.Writer, profile bool, tv television.Television, runTime str
 
 	// attach cartridge to te vcs
 	err = setup.AttachCartridge(vcs, cartload)
-	if err != nil {
+	if err != nil && !errors.Is(err, errors.SetupDetectionError) {
 		return errors.New(errors.PerformanceError, err)
 	}
 
@@ -20,35 +20,92 @@
 package playmode
 
 import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/bindings"
+	"github.com/jetsetilly/gopher2600/errors"
 	"github.com/jetsetilly/gopher2600/gui"
 	"github.com/jetsetilly/gopher2600/hardware"
 	"github.com/jetsetilly/gopher2600/hardware/riot/input"
 )
 
+// the number of revolutions of the driving controller when the mouse is moved
+// from one side of the window to the other
+const drivingRevolutions = 4
+
+// the amount of movement, in pointer steps, when the mouse is moved from one
+// side of the window to the other
+const pointerSteps = 256
+
+// the most recent mouse position. pointer devices require the distance moved
+// rather than the absolute position
+var lastMouse gui.EventMouseMotion
+
 // MouseMotionEventHandler handles mouse events sent from a GUI. Returns true if key
 // has been handled, false otherwise.
 func MouseMotionEventHandler(ev gui.EventMouseMotion, vcs *hardware.VCS) (bool, error) {
-	return true, vcs.HandController0.Handle(input.PaddleSet, ev.X)
+	dx := (ev.X - lastMouse.X) * pointerSteps
+	dy := (ev.Y - lastMouse.Y) * pointerSteps
+	lastMouse = ev
+
+	hc := vcs.HandController0
+
+	switch hc.ControllerType {
+	case input.DrivingType:
+		return true, hc.Handle(input.DrivingAngle, ev.X*drivingRevolutions)
+	case input.TrakBallType:
+		return true, handlePointer(hc, input.TrakBallX, input.TrakBallY, dx, dy)
+	case input.AmigaMouseType:
+		return true, handlePointer(hc, input.AmigaMouseX, input.AmigaMouseY, dx, dy)
+	case input.STMouseType:
+		return true, handlePointer(hc, input.STMouseX, input.STMouseY, dx, dy)
+	case input.LightGunType:
+		if err := hc.Handle(input.LightGunX, ev.X); err != nil {
+			return true, err
+		}
+		return true, hc.Handle(input.LightGunY, ev.Y)
+	}
+
+	return true, hc.Handle(input.PaddleSet, ev.X)
+}
+
+// handlePointer sends the horizontal and vertical movement events to the hand
+// controller. events with no movement are not sent
+func handlePointer(hc *input.HandController, x input.Event, y input.Event, dx float32, dy float32) error {
+	// the hand controller only deals with whole steps
+	dx = float32(int(dx))
+	dy = float32(int(dy))
+
+	if dx != 0 {
+		if err := hc.Handle(x, dx); err != nil {
+			return err
+		}
+	}
+	if dy != 0 {
+		if err := hc.Handle(y, dy); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // MouseButtonEventHandler handles mouse events sent from a GUI. Returns true if key
 // has been handled, false otherwise.
 func MouseButtonEventHandler(ev gui.EventMouseButton, vcs *hardware.VCS, scr gui.GUI) (bool, error) {
-	var handled bool
-	var err error
+	var control string
 
 	switch ev.Button {
 	case gui.MouseButtonLeft:
-		if ev.Down {
-			err = vcs.HandController0.Handle(input.PaddleFire, true)
-		} else {
-			err = vcs.HandController0.Handle(input.PaddleFire, false)
-		}
-
-		handled = true
+		control = "MouseLeft"
+	case gui.MouseButtonRight:
+		control = "MouseRight"
+	case gui.MouseButtonMiddle:
+		control = "MouseMiddle"
+	default:
+		return false, nil
 	}
 
-	return handled, err
+	return handleBindings(vcs, bindings.Lookup(control), ev.Down, true)
 }
 
 // KeyboardEventHandler handles keypresses sent from a GUI. Returns true if
@@ -56,155 +113,58 @@ func MouseButtonEventHandler(ev gui.EventMouseButton, vcs *hardware.VCS, scr gui
 //
 // For reasons of consistency, this handler is used by the debugger too.
 func KeyboardEventHandler(ev gui.EventKeyboard, vcs *hardware.VCS) (bool, error) {
+	// keys pressed with a modifier are treated as though they have been
+	// released
+	return handleBindings(vcs, bindings.Lookup(ev.Key), ev.Down && ev.Mod == gui.KeyModNone, false)
+}
+
+// handleBindings sends the press or release actions of each binding to the
+// bound port. Returns true if any action has been sent.
+func handleBindings(vcs *hardware.VCS, bnds []bindings.Binding, pressed bool, mouse bool) (bool, error) {
 	var handled bool
-	var err error
-
-	if ev.Down && ev.Mod == gui.KeyModNone {
-		switch ev.Key {
-		// panel
-		case "F1":
-			err = vcs.Panel.Handle(input.PanelSelect, true)
-			handled = true
-		case "F2":
-			err = vcs.Panel.Handle(input.PanelReset, true)
-			handled = true
-		case "F3":
-			err = vcs.Panel.Handle(input.PanelToggleColor, nil)
-			handled = true
-		case "F4":
-			err = vcs.Panel.Handle(input.PanelTogglePlayer0Pro, nil)
-			handled = true
-		case "F5":
-			err = vcs.Panel.Handle(input.PanelTogglePlayer1Pro, nil)
-			handled = true
-
-		// joystick
-		case "Left":
-			err = vcs.HandController0.Handle(input.Left, true)
-			handled = true
-		case "Right":
-			err = vcs.HandController0.Handle(input.Right, true)
-			handled = true
-		case "Up":
-			err = vcs.HandController0.Handle(input.Up, true)
-			handled = true
-		case "Down":
-			err = vcs.HandController0.Handle(input.Down, true)
-			handled = true
-		case "Space":
-			err = vcs.HandController0.Handle(input.Fire, true)
-			handled = true
-
-		// keypad (left player)
-		case "1", "2", "3":
-			err = vcs.HandController0.Handle(input.KeypadDown, rune(ev.Key[0]))
-			handled = true
-		case "Q":
-			err = vcs.HandController0.Handle(input.KeypadDown, '4')
-			handled = true
-		case "W":
-			err = vcs.HandController0.Handle(input.KeypadDown, '5')
-			handled = true
-		case "E":
-			err = vcs.HandController0.Handle(input.KeypadDown, '6')
-			handled = true
-		case "A":
-			err = vcs.HandController0.Handle(input.KeypadDown, '7')
-			handled = true
-		case "S":
-			err = vcs.HandController0.Handle(input.KeypadDown, '8')
-			handled = true
-		case "D":
-			err = vcs.HandController0.Handle(input.KeypadDown, '9')
-			handled = true
-		case "Z":
-			err = vcs.HandController0.Handle(input.KeypadDown, '*')
-			handled = true
-		case "X":
-			err = vcs.HandController0.Handle(input.KeypadDown, '0')
-			handled = true
-		case "C":
-			err = vcs.HandController0.Handle(input.KeypadDown, '#')
-			handled = true
-
-		// keypad (right player)
-		case "4":
-			err = vcs.HandController1.Handle(input.KeypadDown, '1')
-			handled = true
-		case "5":
-			err = vcs.HandController1.Handle(input.KeypadDown, '2')
-			handled = true
-		case "6":
-			err = vcs.HandController1.Handle(input.KeypadDown, '3')
-			handled = true
-		case "R":
-			err = vcs.HandController1.Handle(input.KeypadDown, '4')
-			handled = true
-		case "T":
-			err = vcs.HandController1.Handle(input.KeypadDown, '5')
-			handled = true
-		case "Y":
-			err = vcs.HandController1.Handle(input.KeypadDown, '6')
-			handled = true
-		case "F":
-			err = vcs.HandController1.Handle(input.KeypadDown, '7')
-			handled = true
-		case "G":
-			err = vcs.HandController1.Handle(input.KeypadDown, '8')
-			handled = true
-		case "H":
-			err = vcs.HandController1.Handle(input.KeypadDown, '9')
-			handled = true
-		case "V":
-			err = vcs.HandController1.Handle(input.KeypadDown, '*')
-			handled = true
-		case "B":
-			err = vcs.HandController1.Handle(input.KeypadDown, '0')
-			handled = true
-		case "N":
-			err = vcs.HandController1.Handle(input.KeypadDown, '#')
-			handled = true
+
+	for _, b := range bnds {
+		a := b.Release
+		if pressed {
+			a = b.Press
+		}
+
+		if a.Event == input.NoEvent {
+			continue // for loop
+		}
+
+		var port input.Port
+
+		switch b.ID {
+		case input.HandControllerZeroID:
+			port = vcs.HandController0
+		case input.HandControllerOneID:
+			port = vcs.HandController1
+		case input.PanelID:
+			port = vcs.Panel
+		default:
+			continue // for loop
+		}
+
+		// the driving controller, pointer devices and the light gun are
+		// operated by the mouse but use the joystick fire button
+		if mouse && (a.Event == input.PaddleFire || a.Event == input.PaddleFireB) {
+			if hc, ok := port.(*input.HandController); ok {
+				switch hc.ControllerType {
+				case input.DrivingType, input.TrakBallType, input.AmigaMouseType, input.STMouseType, input.LightGunType:
+					a.Event = input.Fire
+				}
+			}
 		}
-	} else {
-		switch ev.Key {
-		// panel
-		case "F1":
-			err = vcs.Panel.Handle(input.PanelSelect, false)
-			handled = true
-		case "F2":
-			err = vcs.Panel.Handle(input.PanelReset, false)
-			handled = true
-
-		// josytick
-		case "Left":
-			err = vcs.HandController0.Handle(input.Left, false)
-			handled = true
-		case "Right":
-			err = vcs.HandController0.Handle(input.Right, false)
-			handled = true
-		case "Up":
-			err = vcs.HandController0.Handle(input.Up, false)
-			handled = true
-		case "Down":
-			err = vcs.HandController0.Handle(input.Down, false)
-			handled = true
-		case "Space":
-			err = vcs.HandController0.Handle(input.Fire, false)
-			handled = true
-
-		// keypad (left player)
-		case "1", "2", "3", "Q", "W", "E", "A", "S", "D", "Z", "X", "C":
-			err = vcs.HandController0.Handle(input.KeypadUp, nil)
-			handled = true
-
-		// keypad (right player)
-		case "4", "5", "6", "R", "T", "Y", "F", "G", "H", "V", "B", "N":
-			err = vcs.HandController1.Handle(input.KeypadUp, nil)
-			handled = true
+
+		if err := port.Macros().Handle(a.Event, a.Data); err != nil {
+			return true, err
 		}
+
+		handled = true
 	}
 
-	return handled, err
+	return handled, nil
 }
 
 func (pl *playmode) guiEventHandler(ev gui.Event) (bool, error) {
@@ -226,6 +186,27 @@ func (pl *playmode) guiEventHandler(ev gui.Event) (bool, error) {
 }
 
 func (pl *playmode) eventHandler() (bool, error) {
+	// a killed CPU will not recover until the VCS is reset. the halt is
+	// reported once and the emulation keeps running (with a frozen screen)
+	// until the reset switch is pressed
+	if pl.vcs.CPU.Killed {
+		if !pl.killReported {
+			pl.killReported = true
+			fmt.Printf("* %s\n", errors.New(errors.CPUKilled,
+				pl.vcs.CPU.LastResult.Defn.OpCode,
+				pl.vcs.CPU.LastResult.Address,
+				pl.vcs.Mem.Cart.GetBank(pl.vcs.CPU.LastResult.Address)))
+			fmt.Println("! press reset to restart the console")
+		}
+
+		if pl.vcs.Panel.IsResetPressed() {
+			if err := pl.vcs.Reset(); err != nil {
+				return false, err
+			}
+			pl.killReported = false
+		}
+	}
+
 	select {
 	case <-pl.intChan:
 		return false, nil
@@ -25,6 +25,7 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/jetsetilly/gopher2600/bindings"
 	"github.com/jetsetilly/gopher2600/cartridgeloader"
 	"github.com/jetsetilly/gopher2600/errors"
 	"github.com/jetsetilly/gopher2600/gui"
@@ -40,6 +41,9 @@ type playmode struct {
 	scr     gui.GUI
 	intChan chan os.Signal
 	guiChan chan gui.Event
+
+	// the killed CPU has been reported to the user
+	killReported bool
 }
 
 // Play is a quick of setting up a playable instance of the emulator.
@@ -64,6 +68,11 @@ func Play(tv television.Television, scr gui.GUI, showOnStable bool, newRecording
 		return errors.New(errors.PlayError, err)
 	}
 
+	err = bindings.Load()
+	if err != nil {
+		return errors.New(errors.PlayError, err)
+	}
+
 	// note that we attach the cartridge in three different branches below,
 	// depending on
 
@@ -91,7 +100,10 @@ func Play(tv television.Television, scr gui.GUI, showOnStable bool, newRecording
 		// setup because we want to catch any setup events in the recording
 		err = setup.AttachCartridge(vcs, cartload)
 		if err != nil {
-			return errors.New(errors.PlayError, err)
+			if !errors.Is(err, errors.SetupDetectionError) {
+				return errors.New(errors.PlayError, err)
+			}
+			fmt.Printf("* %s\n", err)
 		}
 
 	} else if transcript != "" {
@@ -130,7 +142,10 @@ func Play(tv television.Television, scr gui.GUI, showOnStable bool, newRecording
 
 		err = setup.AttachCartridge(vcs, cartload)
 		if err != nil {
-			return errors.New(errors.PlayError, err)
+			if !errors.Is(err, errors.SetupDetectionError) {
+				return errors.New(errors.PlayError, err)
+			}
+			fmt.Printf("* %s\n", err)
 		}
 
 		// apply patch if requested. note that this will be in addition to any
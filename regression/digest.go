@@ -192,7 +192,7 @@ func (reg *DigestRegression) regress(newRegression bool, output io.Writer, msg s
 	}
 
 	err = setup.AttachCartridge(vcs, reg.CartLoad)
-	if err != nil {
+	if err != nil && !errors.Is(err, errors.SetupDetectionError) {
 		return false, "", errors.New(errors.RegressionDigestError, err)
 	}
 
@@ -0,0 +1,104 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package setup
+
+import (
+	"fmt"
+
+	"github.com/jetsetilly/gopher2600/bindings"
+	"github.com/jetsetilly/gopher2600/database"
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware"
+)
+
+const bindingsID = "bindings"
+
+const (
+	bindingsFieldCartHash int = iota
+	bindingsFieldFile
+	bindingsFieldNotes
+	numBindingsFields
+)
+
+// Bindings is used to apply input bindings specific to the cartridge after the
+// cartridge has been attached/loaded
+type Bindings struct {
+	cartHash     string
+	bindingsFile string
+	notes        string
+}
+
+func deserialiseBindingsEntry(fields database.SerialisedEntry) (database.Entry, error) {
+	set := &Bindings{}
+
+	// basic sanity check
+	if len(fields) > numBindingsFields {
+		return nil, errors.New(errors.SetupBindingsError, "too many fields in bindings entry")
+	}
+	if len(fields) < numBindingsFields {
+		return nil, errors.New(errors.SetupBindingsError, "too few fields in bindings entry")
+	}
+
+	set.cartHash = fields[bindingsFieldCartHash]
+	set.bindingsFile = fields[bindingsFieldFile]
+	set.notes = fields[bindingsFieldNotes]
+
+	return set, nil
+}
+
+// ID implements the database.Entry interface
+func (set Bindings) ID() string {
+	return bindingsID
+}
+
+// String implements the database.Entry interface
+func (set Bindings) String() string {
+	return fmt.Sprintf("%s, %s", set.cartHash, set.bindingsFile)
+}
+
+// Serialise implements the database.Entry interface
+func (set *Bindings) Serialise() (database.SerialisedEntry, error) {
+	return database.SerialisedEntry{
+			set.cartHash,
+			set.bindingsFile,
+			set.notes,
+		},
+		nil
+}
+
+// CleanUp implements the database.Entry interface
+func (set Bindings) CleanUp() error {
+	// no cleanup necessary
+	return nil
+}
+
+// matchCartHash implements setupEntry interface
+func (set Bindings) matchCartHash(hash string) bool {
+	return set.cartHash == hash
+}
+
+// apply implements setupEntry interface
+func (set Bindings) apply(vcs *hardware.VCS) error {
+	err := bindings.LoadCartridge(set.bindingsFile)
+	if err != nil {
+		return errors.New(errors.SetupBindingsError, err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+// This file is part of Gopher2600.
+//
+// Gopher2600 is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Gopher2600 is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Gopher2600.  If not, see <https://www.gnu.org/licenses/>.
+//
+// *** NOTE: all historical versions of this file, as found in any
+// git repository, are also covered by the licence, even when this
+// notice is not present ***
+
+package setup
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
+	"github.com/jetsetilly/gopher2600/database"
+	"github.com/jetsetilly/gopher2600/errors"
+	"github.com/jetsetilly/gopher2600/hardware"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
+	"github.com/jetsetilly/gopher2600/paths"
+)
+
+const controllerID = "controller"
+
+const (
+	controllerFieldCartHash int = iota
+	controllerFieldPort
+	controllerFieldType
+	controllerFieldAuto
+	controllerFieldPaddle
+	controllerFieldNotes
+	numControllerFields
+)
+
+// Controller is used to set the type of hand controller plugged into one of
+// the VCS's ports after the cartridge has been attached/loaded
+type Controller struct {
+	cartHash string
+
+	port           int
+	controllerType input.ControllerType
+	auto           bool
+
+	// initial position of the paddles. only used if the controller type is
+	// the paddle type
+	paddle float32
+
+	notes string
+}
+
+// newController is the preferred method of initialisation for the Controller
+// type. the controllerType argument should be one of the strings in
+// input.ControllerTypeList (case insensitive).
+func newController(cartHash string, port int, controllerType string, auto bool, paddle float32, notes string) (*Controller, error) {
+	set := &Controller{
+		cartHash: cartHash,
+		port:     port,
+		auto:     auto,
+		paddle:   paddle,
+		notes:    notes,
+	}
+
+	if port != 0 && port != 1 {
+		return nil, errors.New(errors.SetupControllerError, "invalid port (should be 0 or 1)")
+	}
+
+	if paddle < 0.0 || paddle > 1.0 {
+		return nil, errors.New(errors.SetupControllerError, "invalid paddle position (should be between 0.0 and 1.0)")
+	}
+
+	var err error
+
+	if set.controllerType, err = parseControllerType(controllerType); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// parseControllerType converts a string to a ControllerType. the string should
+// be one of the values in input.ControllerTypeList (case insensitive)
+func parseControllerType(s string) (input.ControllerType, error) {
+	for i, t := range input.ControllerTypeList {
+		if strings.EqualFold(s, t) {
+			return input.ControllerType(i), nil
+		}
+	}
+	return input.JoystickType, errors.New(errors.SetupControllerError, fmt.Sprintf("unrecognised controller type (%s)", s))
+}
+
+func deserialiseControllerEntry(fields database.SerialisedEntry) (database.Entry, error) {
+	set := &Controller{}
+
+	// basic sanity check
+	if len(fields) > numControllerFields {
+		return nil, errors.New(errors.SetupControllerError, "too many fields in controller entry")
+	}
+	if len(fields) < numControllerFields {
+		return nil, errors.New(errors.SetupControllerError, "too few fields in controller entry")
+	}
+
+	var err error
+
+	set.cartHash = fields[controllerFieldCartHash]
+
+	if set.port, err = strconv.Atoi(fields[controllerFieldPort]); err != nil || (set.port != 0 && set.port != 1) {
+		return nil, errors.New(errors.SetupControllerError, "invalid port (should be 0 or 1)")
+	}
+
+	if set.controllerType, err = parseControllerType(fields[controllerFieldType]); err != nil {
+		return nil, err
+	}
+
+	if set.auto, err = strconv.ParseBool(fields[controllerFieldAuto]); err != nil {
+		return nil, errors.New(errors.SetupControllerError, "invalid auto setting")
+	}
+
+	paddle, err := strconv.ParseFloat(fields[controllerFieldPaddle], 32)
+	if err != nil || paddle < 0.0 || paddle > 1.0 {
+		return nil, errors.New(errors.SetupControllerError, "invalid paddle position (should be between 0.0 and 1.0)")
+	}
+	set.paddle = float32(paddle)
+
+	set.notes = fields[controllerFieldNotes]
+
+	return set, nil
+}
+
+// ID implements the database.Entry interface
+func (set Controller) ID() string {
+	return controllerID
+}
+
+// String implements the database.Entry interface
+func (set Controller) String() string {
+	s := fmt.Sprintf("%s, port=%d, type=%s, auto=%v", set.cartHash, set.port, set.controllerType, set.auto)
+	if set.controllerType == input.PaddleType {
+		s = fmt.Sprintf("%s, paddle=%.2f", s, set.paddle)
+	}
+	return s
+}
+
+// Serialise implements the database.Entry interface
+func (set *Controller) Serialise() (database.SerialisedEntry, error) {
+	return database.SerialisedEntry{
+			set.cartHash,
+			strconv.Itoa(set.port),
+			set.controllerType.String(),
+			strconv.FormatBool(set.auto),
+			strconv.FormatFloat(float64(set.paddle), 'f', -1, 32),
+			set.notes,
+		},
+		nil
+}
+
+// CleanUp implements the database.Entry interface
+func (set Controller) CleanUp() error {
+	// no cleanup necessary
+	return nil
+}
+
+// matchCartHash implements setupEntry interface
+func (set Controller) matchCartHash(hash string) bool {
+	return set.cartHash == hash
+}
+
+// apply implements setupEntry interface
+func (set Controller) apply(vcs *hardware.VCS) error {
+	hc := vcs.HandController0
+	if set.port == 1 {
+		hc = vcs.HandController1
+	}
+
+	if err := hc.SwitchType(set.controllerType); err != nil {
+		return errors.New(errors.SetupControllerError, err)
+	}
+
+	// some controller types can not be detected automatically and will have
+	// turned auto-switching off. don't turn it back on again
+	hc.SetAuto(set.auto && hc.AutoControllerType)
+
+	if set.controllerType == input.PaddleType {
+		if err := hc.Handle(input.PaddleSet, set.paddle); err != nil {
+			return errors.New(errors.SetupControllerError, err)
+		}
+		if err := hc.Handle(input.PaddleSetB, set.paddle); err != nil {
+			return errors.New(errors.SetupControllerError, err)
+		}
+	}
+
+	return nil
+}
+
+// AddController adds a new controller entry to the setup database for the
+// specified cartridge
+func AddController(output io.Writer, cartload cartridgeloader.Loader, port int, controllerType string, auto bool, paddle float32, notes string) error {
+	if output == nil {
+		return errors.New(errors.PanicError, "AddController()", "io.Writer should not be nil (use a nopWriter)")
+	}
+
+	// the cartridge hash is used to match the entry when the cartridge is
+	// next attached
+	cart := cartridge.NewCartridge()
+	if err := cart.Attach(cartload); err != nil {
+		return errors.New(errors.SetupControllerError, err)
+	}
+
+	set, err := newController(cart.Hash, port, controllerType, auto, paddle, notes)
+	if err != nil {
+		return err
+	}
+
+	dbPth, err := paths.ResourcePath("", setupDBFile)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+
+	db, err := database.StartSession(dbPth, database.ActivityCreating, initDBSession)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+	defer db.EndSession(true)
+
+	err = db.Add(set)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+
+	output.Write([]byte(fmt.Sprintf("added: %s\n", set)))
+
+	return nil
+}
@@ -24,12 +24,26 @@
 //	Toggling of panel switches
 //	Apply patches to cartridge
 //	Television specification
+//	Hand controller types
+//	Input bindings
+//
+// Before any entries are applied, the attached cartridge is disassembled and
+// analysed for patterns that suggest which type of hand controller is
+// expected in each port. The hand controllers are switched accordingly (see
+// disassembly.ControllerTypes() for details). Automatic switching is turned
+// off for a port that is switched to anything other than a joystick, so that
+// the detected type is not undone by the cartridge's own use of the port.
+//
+// The analysis uses a separate instance of the cartridge, created from the
+// same cartridge loader, so that the state of the attached cartridge is not
+// disturbed. If the cartridge can not be disassembled the hand controllers are
+// left unchanged and the error is returned with the SetupDetectionError ID.
 //
 // Menu driven selection of patches would be a nice feature to have in the
-// future. But at the moment, the package doesn't even facilitate editing of
-// entries. Adding new entries to the setup database therefore requires editing
-// the DB file by hand. For reference the following describes the format of
-// each entry type:
+// future. But at the moment, the package only facilitates the adding of
+// controller entries. Adding other entries to the setup database therefore
+// requires editing the DB file by hand. For reference the following describes
+// the format of each entry type:
 //
 //	Panel Toggles
 //
@@ -48,4 +62,24 @@
 //	<DB Key>, television, <SHA-1 Hash>, <tv spec>, notes
 //
 // TV spec should be one of PAL or NTSC (or AUTO)
+//
+//	Controller
+//
+//	<DB Key>, controller, <SHA-1 Hash>, <port (0 or 1)>, <controller type>, <auto (bool)>, <paddle (float)>, <notes>
+//
+// Controller type should be one of the types listed in
+// input.ControllerTypeList. The paddle value is the initial position of the
+// paddles (between 0.0 and 1.0) and is only used by the Paddle type.
+//
+//	Bindings
+//
+//	<DB Key>, bindings, <SHA-1 Hash>, <bindings file>, <notes>
+//
+// Bindings files are located in the cartbindings sub-directory of the
+// resources path. The bindings in the file take precedence over the default
+// bindings (see bindings package for the file format).
+//
+// Controller entries can be added with the AddController() function. Entries
+// of any type can be listed and deleted with the List() and Delete()
+// functions.
 package setup
@@ -20,11 +20,20 @@
 package setup
 
 import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jetsetilly/gopher2600/bindings"
 	"github.com/jetsetilly/gopher2600/cartridgeloader"
 	"github.com/jetsetilly/gopher2600/database"
+	"github.com/jetsetilly/gopher2600/disassembly"
 	"github.com/jetsetilly/gopher2600/errors"
 	"github.com/jetsetilly/gopher2600/hardware"
+	"github.com/jetsetilly/gopher2600/hardware/memory/cartridge"
+	"github.com/jetsetilly/gopher2600/hardware/riot/input"
 	"github.com/jetsetilly/gopher2600/paths"
+	"github.com/jetsetilly/gopher2600/symbols"
 )
 
 // the location of the setupDB file
@@ -58,30 +67,70 @@ func initDBSession(db *database.Session) error {
 		return err
 	}
 
+	if err := db.RegisterEntryType(controllerID, deserialiseControllerEntry); err != nil {
+		return err
+	}
+
+	if err := db.RegisterEntryType(bindingsID, deserialiseBindingsEntry); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // AttachCartridge to the VCS and apply setup information from the setupDB.
 // This function should be preferred to the hardware.VCS.AttachCartridge()
 // function in almost all cases.
+//
+// The hand controllers are initialised according to an analysis of the
+// cartridge before the setupDB is consulted. Entries in the setupDB therefore
+// take precedence.
+//
+// Failure to detect the hand controllers does not prevent the cartridge from
+// being attached or the setupDB from being applied. The
+// SetupDetectionError error is returned in that case and can be ignored
+// by the caller if required.
 func AttachCartridge(vcs *hardware.VCS, cartload cartridgeloader.Loader) error {
+	_, err := AttachCartridgeWithDisassembly(vcs, cartload, symbols.NewTable())
+	return err
+}
+
+// AttachCartridgeWithDisassembly is the same as AttachCartridge() except that
+// the disassembly used to detect the hand controllers is returned. This is
+// useful if the caller needs a disassembly of its own and doesn't want the
+// cartridge to be disassembled twice.
+//
+// The disassembly is of a separate instance of the cartridge, loaded by the
+// same cartridgeloader.Loader, and uses the supplied symbols table. The
+// disassembly will be nil if the cartridge could not be disassembled or if
+// an entry in the setupDB has patched the cartridge, in which case the
+// disassembly no longer matches the attached cartridge.
+func AttachCartridgeWithDisassembly(vcs *hardware.VCS, cartload cartridgeloader.Loader, symtable *symbols.Table) (*disassembly.Disassembly, error) {
 	err := vcs.AttachCartridge(cartload)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// failure to detect the controllers is not fatal. the controllers are
+	// left as they are and the error is returned once the rest of the setup
+	// has been applied
+	dsm, detectErr := detectControllers(vcs, cartload, symtable)
+
+	// bindings for the previous cartridge are no longer required
+	bindings.ClearCartridge()
+
 	dbPth, err := paths.ResourcePath("", setupDBFile)
 	if err != nil {
-		return errors.New(errors.SetupError, err)
+		return nil, errors.New(errors.SetupError, err)
 	}
 
 	db, err := database.StartSession(dbPth, database.ActivityReading, initDBSession)
 	if err != nil {
 		if errors.Is(err, errors.DatabaseFileUnavailable) {
 			// silently ignore absence of setup database
-			return nil
+			return dsm, detectErr
 		}
-		return errors.New(errors.SetupError, err)
+		return nil, errors.New(errors.SetupError, err)
 	}
 	defer db.EndSession(false)
 
@@ -97,15 +146,137 @@ func AttachCartridge(vcs *hardware.VCS, cartload cartridgeloader.Loader) error {
 			if err != nil {
 				return false, err
 			}
+
+			// the disassembly is of the unpatched cartridge
+			if _, ok := set.(*Patch); ok {
+				dsm = nil
+			}
 		}
 
 		return true, nil
 	}
 
 	_, err = db.SelectAll(onSelect)
+	if err != nil {
+		return nil, errors.New(errors.SetupError, err)
+	}
+
+	return dsm, detectErr
+}
+
+// detectControllers switches the hand controllers to the types suggested by a
+// disassembly of the cartridge. automatic switching is left on if the
+// joystick is detected so that the emulation can still change its mind.
+//
+// the disassembly is made from a separate instance of the cartridge. the
+// decoding process reads and writes cartridge memory and for some cartridge
+// types this can't be undone.
+func detectControllers(vcs *hardware.VCS, cartload cartridgeloader.Loader, symtable *symbols.Table) (*disassembly.Disassembly, error) {
+	if vcs.Mem.Cart.IsEjected() {
+		return nil, nil
+	}
+
+	cart := cartridge.NewCartridge()
+	err := cart.Attach(cartload)
+	if err != nil {
+		return nil, errors.New(errors.SetupDetectionError, err)
+	}
+
+	dsm, err := disassembly.FromMemory(cart, symtable)
+	if err != nil {
+		return nil, errors.New(errors.SetupDetectionError, err)
+	}
+
+	types := dsm.ControllerTypes()
+	for i, hc := range []*input.HandController{vcs.HandController0, vcs.HandController1} {
+		if types[i] == hc.ControllerType || !hc.AutoControllerType {
+			continue // for loop
+		}
+		err = hc.SwitchType(types[i])
+		if err != nil {
+			return dsm, errors.New(errors.SetupDetectionError, err)
+		}
+
+		// the controller type would otherwise be changed back to the joystick
+		// as soon as the cartridge writes to the SWACNT register
+		if types[i] != input.JoystickType {
+			hc.SetAuto(false)
+		}
+	}
+
+	return dsm, nil
+}
+
+// List all entries in the setup database
+func List(output io.Writer) error {
+	if output == nil {
+		return errors.New(errors.PanicError, "setup.List()", "io.Writer should not be nil (use a nopWriter)")
+	}
+
+	dbPth, err := paths.ResourcePath("", setupDBFile)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+
+	db, err := database.StartSession(dbPth, database.ActivityReading, initDBSession)
 	if err != nil {
 		return errors.New(errors.SetupError, err)
 	}
+	defer db.EndSession(false)
+
+	return db.List(output)
+}
+
+// Delete an entry from the setup database. The entry is printed and a "y" is
+// required from the confirmation reader before the entry is deleted.
+func Delete(output io.Writer, confirmation io.Reader, key string) error {
+	if output == nil {
+		return errors.New(errors.PanicError, "setup.Delete()", "io.Writer should not be nil (use a nopWriter)")
+	}
+
+	v, err := strconv.Atoi(key)
+	if err != nil {
+		msg := fmt.Sprintf("invalid key [%s]", key)
+		return errors.New(errors.SetupError, msg)
+	}
+
+	dbPth, err := paths.ResourcePath("", setupDBFile)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+
+	db, err := database.StartSession(dbPth, database.ActivityModifying, initDBSession)
+	if err != nil {
+		return errors.New(errors.SetupError, err)
+	}
+	defer db.EndSession(true)
+
+	ent, err := db.SelectKeys(nil, v)
+	if err != nil {
+		if !errors.Is(err, errors.DatabaseSelectEmpty) {
+			return err
+		}
+
+		// select returned no entries; create DatabaseKeyError and wrap it in a
+		// SetupError
+		return errors.New(errors.SetupError, errors.New(errors.DatabaseKeyError, v))
+	}
+
+	output.Write([]byte(fmt.Sprintf("%s\ndelete? (y/n): ", ent)))
+
+	confirm := make([]byte, 32)
+	_, err = confirmation.Read(confirm)
+	if err != nil {
+		return err
+	}
+
+	if confirm[0] == 'y' || confirm[0] == 'Y' {
+		err = db.Delete(v)
+		if err != nil {
+			return err
+		}
+		output.Write([]byte(fmt.Sprintf("deleted entry #%s from setup database\n", key)))
+	}
 
 	return nil
 }
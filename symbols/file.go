@@ -28,6 +28,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/jetsetilly/gopher2600/cartridgeloader"
 	"github.com/jetsetilly/gopher2600/errors"
 )
 
@@ -59,28 +60,9 @@ func ReadSymbolsFile(cartridgeFilename string) (*Table, error) {
 		return tbl, nil
 	}
 
-	// try to open symbols file
-	symFilename := cartridgeFilename
-	ext := path.Ext(symFilename)
-
-	// try to figure out the case of the file extension
-	if ext == ".BIN" {
-		symFilename = fmt.Sprintf("%s.SYM", symFilename[:len(symFilename)-len(ext)])
-	} else {
-		symFilename = fmt.Sprintf("%s.sym", symFilename[:len(symFilename)-len(ext)])
-	}
-
-	sf, err := os.Open(symFilename)
-	if err != nil {
-		return tbl, errors.New(errors.SymbolsFileUnavailable, cartridgeFilename)
-	}
-	defer func() {
-		_ = sf.Close()
-	}()
-
-	sym, err := ioutil.ReadAll(sf)
+	sym, err := readSymbols(cartridgeFilename)
 	if err != nil {
-		return nil, errors.New(errors.SymbolsFileError, err)
+		return tbl, err
 	}
 	lines := strings.Split(string(sym), "\n")
 
@@ -121,3 +103,54 @@ func ReadSymbolsFile(cartridgeFilename string) (*Table, error) {
 
 	return tbl, nil
 }
+
+// symbolsFilename returns the name of the symbols file that accompanies the
+// cartridge file
+func symbolsFilename(cartridgeFilename string) string {
+	ext := path.Ext(cartridgeFilename)
+
+	// try to figure out the case of the file extension
+	if ext == ".BIN" {
+		return fmt.Sprintf("%s.SYM", cartridgeFilename[:len(cartridgeFilename)-len(ext)])
+	}
+	return fmt.Sprintf("%s.sym", cartridgeFilename[:len(cartridgeFilename)-len(ext)])
+}
+
+// readSymbols returns the contents of the symbols file for the cartridge. if
+// the cartridge has been loaded from an archive then the symbols file is
+// looked for in the archive first and then alongside the archive
+func readSymbols(cartridgeFilename string) ([]byte, error) {
+	cl := cartridgeloader.Loader{Filename: cartridgeFilename}
+
+	if cl.IsArchive() {
+		inner := cl.InnerFilename()
+		if inner == "" {
+			return nil, errors.New(errors.SymbolsFileUnavailable, cartridgeFilename)
+		}
+
+		symFilename := symbolsFilename(inner)
+
+		scl := cartridgeloader.Loader{Filename: fmt.Sprintf("%s#%s", cl.ArchiveFilename(), symFilename)}
+		if sym, err := scl.Load(); err == nil {
+			return sym, nil
+		}
+
+		// look for the symbols file in the same directory as the archive
+		cartridgeFilename = path.Join(path.Dir(cl.ArchiveFilename()), path.Base(inner))
+	}
+
+	sf, err := os.Open(symbolsFilename(cartridgeFilename))
+	if err != nil {
+		return nil, errors.New(errors.SymbolsFileUnavailable, cartridgeFilename)
+	}
+	defer func() {
+		_ = sf.Close()
+	}()
+
+	sym, err := ioutil.ReadAll(sf)
+	if err != nil {
+		return nil, errors.New(errors.SymbolsFileError, err)
+	}
+
+	return sym, nil
+}
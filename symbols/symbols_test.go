@@ -41,6 +41,18 @@ func TestDefaultSymbols(t *testing.T) {
 	}
 }
 
+func TestNewTable(t *testing.T) {
+	syms := symbols.NewTable()
+
+	tw := &test.Writer{}
+
+	syms.ListSymbols(tw)
+
+	if !tw.Compare(expectedDefaultSymbols) {
+		t.Errorf("new table symbols list is wrong")
+	}
+}
+
 func TestFlappySymbols(t *testing.T) {
 	syms, err := symbols.ReadSymbolsFile("testdata/flappy.sym")
 	if err != nil {
@@ -56,6 +68,21 @@ func TestFlappySymbols(t *testing.T) {
 	}
 }
 
+func TestArchiveSymbols(t *testing.T) {
+	syms, err := symbols.ReadSymbolsFile("testdata/flappy.zip")
+	if err != nil {
+		t.Errorf("unexpected error (%s)", err)
+	}
+
+	tw := &test.Writer{}
+
+	syms.ListSymbols(tw)
+
+	if !tw.Compare(expectedFlappySymbols) {
+		t.Errorf("flappy symbols list from archive is wrong")
+	}
+}
+
 const expectedDefaultSymbols = `Locations
 ---------
 
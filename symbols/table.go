@@ -43,7 +43,11 @@ type Table struct {
 // many instances however, ReadSymbolsFile() might be more appropriate. Naked
 // initalisation of the Table type (ie. &Table{}) will rarely be useful.
 func NewTable() *Table {
-	tbl := &Table{}
+	tbl := &Table{
+		Locations: newTable(),
+		Read:      newTable(),
+		Write:     newTable(),
+	}
 	tbl.canoniseTable(true)
 	return tbl
 }